@@ -0,0 +1,75 @@
+package client
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/keboola/go-client/pkg/client/cache"
+)
+
+// cacheRoundTripper serves GET requests from the cache.Store, see Client.WithCache.
+// It wraps the retry round tripper, so a cache hit skips all retries and the circuit breaker.
+//
+// Errors of the store are ignored, the cache is an optimization, the request is sent as without the cache.
+type cacheRoundTripper struct {
+	store   cache.Store
+	ttl     time.Duration
+	wrapped http.RoundTripper
+}
+
+func (rt cacheRoundTripper) RoundTrip(req *http.Request) (*http.Response, error) {
+	ctx := req.Context()
+	key := cache.Key(req)
+
+	// Use a fresh entry without sending the request
+	entry, found, err := rt.store.Get(ctx, key)
+	if err != nil || !found {
+		entry = nil
+	} else if entry.Fresh(time.Now()) {
+		return entry.Response(req), nil
+	}
+
+	// Revalidate a stale entry, if the request doesn't contain own conditional header
+	revalidate := entry != nil && entry.ETag() != "" && req.Header.Get("If-None-Match") == ""
+	if revalidate {
+		req = req.Clone(ctx)
+		req.Header.Set("If-None-Match", entry.ETag())
+	}
+
+	// Send
+	res, err := rt.wrapped.RoundTrip(req)
+	if err != nil {
+		return res, err
+	}
+
+	// The stale entry is still valid, update its headers and expiration
+	if revalidate && res.StatusCode == http.StatusNotModified {
+		_ = res.Body.Close()
+		header := entry.Header.Clone()
+		for k, v := range res.Header {
+			header[k] = v
+		}
+		if updated, ok := cache.NewEntry(&http.Response{StatusCode: entry.StatusCode, Header: header}, entry.Body, rt.ttl, time.Now()); ok {
+			_ = rt.store.Set(ctx, key, updated)
+			entry = updated
+		}
+		return entry.Response(req), nil
+	}
+
+	// Store the response
+	if res.StatusCode == http.StatusOK && res.Body != nil && res.Body != http.NoBody {
+		body, err := io.ReadAll(res.Body)
+		_ = res.Body.Close()
+		if err != nil {
+			return nil, err
+		}
+		res.Body = io.NopCloser(bytes.NewReader(body))
+		if newEntry, ok := cache.NewEntry(res, body, rt.ttl, time.Now()); ok {
+			_ = rt.store.Set(ctx, key, newEntry)
+		}
+	}
+
+	return res, nil
+}
@@ -0,0 +1,121 @@
+// Package cache provides stores for the HTTP response cache of the client.Client, see client.Client.WithCache.
+//
+// Only GET requests are cached, and only if the caching is enabled for the request, see request.HTTPRequest.WithCache.
+// The cache honors the Cache-Control and ETag response headers,
+// a stale entry with the ETag is revalidated by the If-None-Match request header.
+package cache
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Store stores cache entries, see NewMemoryStore and NewDiskStore.
+// The implementation must be safe for concurrent use.
+type Store interface {
+	// Get returns the entry, the found flag is false, if the entry doesn't exist.
+	Get(ctx context.Context, key string) (entry *Entry, found bool, err error)
+	// Set stores the entry.
+	Set(ctx context.Context, key string, entry *Entry) error
+	// Delete removes the entry, if it exists.
+	Delete(ctx context.Context, key string) error
+}
+
+// Entry is a cached HTTP response.
+type Entry struct {
+	StatusCode int         `json:"statusCode"`
+	Header     http.Header `json:"header"`
+	Body       []byte      `json:"body"`
+	ExpiresAt  time.Time   `json:"expiresAt"`
+}
+
+// ignoredKeyHeaders are not part of the cache key, they are different for each request.
+//
+//nolint:gochecknoglobals
+var ignoredKeyHeaders = []string{"Traceparent", "Tracestate", "Baggage", "Idempotency-Key", "If-None-Match", "If-Modified-Since"}
+
+// Key returns the cache key of the request.
+// The key is a hash of the method, the URL and the request headers, so responses for different tokens are not mixed.
+func Key(req *http.Request) string {
+	hash := sha256.New()
+	_, _ = io.WriteString(hash, req.Method+"\n"+req.URL.String()+"\n")
+
+	names := make([]string, 0, len(req.Header))
+	for name := range req.Header {
+		if !slices.Contains(ignoredKeyHeaders, http.CanonicalHeaderKey(name)) {
+			names = append(names, name)
+		}
+	}
+	slices.Sort(names)
+	for _, name := range names {
+		_, _ = io.WriteString(hash, name+": "+strings.Join(req.Header.Values(name), ", ")+"\n")
+	}
+
+	return hex.EncodeToString(hash.Sum(nil))
+}
+
+// NewEntry creates an Entry from the response and its body.
+// The storable flag is false, if the response must not be cached.
+//
+// The freshness lifetime is the "max-age" of the Cache-Control header, limited by the ttl,
+// or the ttl, if the "max-age" is not present.
+// A response with zero lifetime is stored only if it has the ETag, so it can be revalidated.
+func NewEntry(res *http.Response, body []byte, ttl time.Duration, now time.Time) (entry *Entry, storable bool) {
+	if res.StatusCode != http.StatusOK {
+		return nil, false
+	}
+
+	lifetime := ttl
+	for _, directive := range strings.Split(res.Header.Get("Cache-Control"), ",") {
+		name, value, _ := strings.Cut(strings.ToLower(strings.TrimSpace(directive)), "=")
+		switch name {
+		case "no-store":
+			return nil, false
+		case "no-cache":
+			lifetime = 0
+		case "max-age":
+			if seconds, err := strconv.Atoi(strings.Trim(value, `"`)); err == nil && seconds >= 0 {
+				lifetime = min(lifetime, time.Duration(seconds)*time.Second)
+			}
+		}
+	}
+
+	entry = &Entry{StatusCode: res.StatusCode, Header: res.Header.Clone(), Body: body, ExpiresAt: now.Add(lifetime)}
+	if lifetime <= 0 && entry.ETag() == "" {
+		return nil, false
+	}
+	return entry, true
+}
+
+// Fresh returns true, if the entry can be used without revalidation.
+func (e *Entry) Fresh(now time.Time) bool {
+	return now.Before(e.ExpiresAt)
+}
+
+// ETag returns the ETag header of the cached response, if any.
+func (e *Entry) ETag() string {
+	return e.Header.Get("ETag")
+}
+
+// Response creates a new HTTP response from the entry.
+func (e *Entry) Response(req *http.Request) *http.Response {
+	return &http.Response{
+		Status:        strconv.Itoa(e.StatusCode) + " " + http.StatusText(e.StatusCode),
+		StatusCode:    e.StatusCode,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        e.Header.Clone(),
+		Body:          io.NopCloser(bytes.NewReader(e.Body)),
+		ContentLength: int64(len(e.Body)),
+		Request:       req,
+	}
+}
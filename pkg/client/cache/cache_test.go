@@ -0,0 +1,104 @@
+package cache
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestKey(t *testing.T) {
+	t.Parallel()
+
+	newReq := func(headers map[string]string) *http.Request {
+		req, err := http.NewRequest(http.MethodGet, "https://example.com/foo", nil)
+		require.NoError(t, err)
+		for k, v := range headers {
+			req.Header.Set(k, v)
+		}
+		return req
+	}
+
+	base := Key(newReq(map[string]string{"X-StorageApi-Token": "foo"}))
+	assert.Equal(t, base, Key(newReq(map[string]string{"X-StorageApi-Token": "foo", "Traceparent": "123", "If-None-Match": `"v1"`})))
+	assert.NotEqual(t, base, Key(newReq(map[string]string{"X-StorageApi-Token": "bar"})))
+	assert.NotEqual(t, base, Key(newReq(nil)))
+}
+
+func TestNewEntry(t *testing.T) {
+	t.Parallel()
+
+	now := time.Now()
+	cases := []struct {
+		name      string
+		status    int
+		header    map[string]string
+		storable  bool
+		expiresAt time.Time
+	}{
+		{name: "ttl", status: http.StatusOK, storable: true, expiresAt: now.Add(time.Hour)},
+		{name: "max-age", status: http.StatusOK, header: map[string]string{"Cache-Control": "public, max-age=60"}, storable: true, expiresAt: now.Add(time.Minute)},
+		{name: "max-age-over-ttl", status: http.StatusOK, header: map[string]string{"Cache-Control": "max-age=7200"}, storable: true, expiresAt: now.Add(time.Hour)},
+		{name: "no-store", status: http.StatusOK, header: map[string]string{"Cache-Control": "no-store", "ETag": `"v1"`}},
+		{name: "no-cache", status: http.StatusOK, header: map[string]string{"Cache-Control": "no-cache"}},
+		{name: "no-cache-etag", status: http.StatusOK, header: map[string]string{"Cache-Control": "no-cache", "ETag": `"v1"`}, storable: true, expiresAt: now},
+		{name: "error", status: http.StatusInternalServerError},
+	}
+
+	for _, tc := range cases {
+		res := &http.Response{StatusCode: tc.status, Header: make(http.Header)}
+		for k, v := range tc.header {
+			res.Header.Set(k, v)
+		}
+		entry, storable := NewEntry(res, []byte("body"), time.Hour, now)
+		assert.Equal(t, tc.storable, storable, tc.name)
+		if storable {
+			assert.Equal(t, tc.expiresAt, entry.ExpiresAt, tc.name)
+		}
+	}
+}
+
+func TestStores(t *testing.T) {
+	t.Parallel()
+
+	stores := map[string]Store{
+		"memory": NewMemoryStore(),
+		"disk":   NewDiskStore(t.TempDir() + "/cache"),
+	}
+
+	for name, store := range stores {
+		ctx := context.Background()
+
+		// Not found
+		_, found, err := store.Get(ctx, "key")
+		require.NoError(t, err, name)
+		assert.False(t, found, name)
+
+		// Set
+		header := http.Header{"Etag": []string{`"v1"`}}
+		expiresAt := time.Now().Add(time.Hour).UTC().Truncate(time.Second)
+		require.NoError(t, store.Set(ctx, "key", &Entry{StatusCode: http.StatusOK, Header: header, Body: []byte("body"), ExpiresAt: expiresAt}), name)
+
+		// Get
+		entry, found, err := store.Get(ctx, "key")
+		require.NoError(t, err, name)
+		require.True(t, found, name)
+		assert.Equal(t, `"v1"`, entry.ETag(), name)
+		assert.True(t, entry.ExpiresAt.Equal(expiresAt), name)
+		assert.True(t, entry.Fresh(time.Now()), name)
+		body, err := io.ReadAll(entry.Response(nil).Body)
+		require.NoError(t, err, name)
+		assert.Equal(t, "body", string(body), name)
+
+		// Delete
+		require.NoError(t, store.Delete(ctx, "key"), name)
+		require.NoError(t, store.Delete(ctx, "key"), name)
+		_, found, err = store.Get(ctx, "key")
+		require.NoError(t, err, name)
+		assert.False(t, found, name)
+	}
+}
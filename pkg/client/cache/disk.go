@@ -0,0 +1,79 @@
+package cache
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+)
+
+// DiskStore is an on-disk Store, each entry is stored as a JSON file in the directory.
+// It can be used to share the cache between short-lived processes, for example CLI runs.
+type DiskStore struct {
+	dir string
+}
+
+// NewDiskStore creates an on-disk Store, the directory is created on the first write, if it doesn't exist.
+func NewDiskStore(dir string) *DiskStore {
+	return &DiskStore{dir: dir}
+}
+
+func (s *DiskStore) Get(_ context.Context, key string) (*Entry, bool, error) {
+	content, err := os.ReadFile(s.path(key))
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, false, nil
+	} else if err != nil {
+		return nil, false, fmt.Errorf(`cannot read cache entry "%s": %w`, key, err)
+	}
+
+	entry := &Entry{}
+	if err := json.Unmarshal(content, entry); err != nil {
+		return nil, false, fmt.Errorf(`cannot decode cache entry "%s": %w`, key, err)
+	}
+	return entry, true, nil
+}
+
+func (s *DiskStore) Set(_ context.Context, key string, entry *Entry) error {
+	content, err := json.Marshal(entry)
+	if err != nil {
+		return fmt.Errorf(`cannot encode cache entry "%s": %w`, key, err)
+	}
+
+	if err := os.MkdirAll(s.dir, 0o700); err != nil {
+		return fmt.Errorf(`cannot create cache dir "%s": %w`, s.dir, err)
+	}
+
+	// Write to a temporary file and rename it, so a concurrent reader never sees a partial entry
+	tmp, err := os.CreateTemp(s.dir, key+".*.tmp")
+	if err != nil {
+		return fmt.Errorf(`cannot write cache entry "%s": %w`, key, err)
+	}
+	if _, err := tmp.Write(content); err != nil {
+		_ = tmp.Close()
+		_ = os.Remove(tmp.Name())
+		return fmt.Errorf(`cannot write cache entry "%s": %w`, key, err)
+	}
+	if err := tmp.Close(); err != nil {
+		_ = os.Remove(tmp.Name())
+		return fmt.Errorf(`cannot write cache entry "%s": %w`, key, err)
+	}
+	if err := os.Rename(tmp.Name(), s.path(key)); err != nil {
+		_ = os.Remove(tmp.Name())
+		return fmt.Errorf(`cannot write cache entry "%s": %w`, key, err)
+	}
+	return nil
+}
+
+func (s *DiskStore) Delete(_ context.Context, key string) error {
+	if err := os.Remove(s.path(key)); err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return fmt.Errorf(`cannot delete cache entry "%s": %w`, key, err)
+	}
+	return nil
+}
+
+func (s *DiskStore) path(key string) string {
+	return filepath.Join(s.dir, key+".json")
+}
@@ -0,0 +1,38 @@
+package cache
+
+import (
+	"context"
+	"sync"
+)
+
+// MemoryStore is an in-memory Store, entries are lost when the process ends.
+type MemoryStore struct {
+	lock    sync.RWMutex
+	entries map[string]*Entry
+}
+
+// NewMemoryStore creates an in-memory Store.
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{entries: make(map[string]*Entry)}
+}
+
+func (s *MemoryStore) Get(_ context.Context, key string) (*Entry, bool, error) {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+	entry, found := s.entries[key]
+	return entry, found, nil
+}
+
+func (s *MemoryStore) Set(_ context.Context, key string, entry *Entry) error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	s.entries[key] = entry
+	return nil
+}
+
+func (s *MemoryStore) Delete(_ context.Context, key string) error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	delete(s.entries, key)
+	return nil
+}
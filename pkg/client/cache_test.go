@@ -0,0 +1,101 @@
+package client_test
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/jarcoal/httpmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	. "github.com/keboola/go-client/pkg/client"
+	"github.com/keboola/go-client/pkg/client/cache"
+	"github.com/keboola/go-client/pkg/request"
+)
+
+func TestCache_TTL(t *testing.T) {
+	t.Parallel()
+
+	// Mocked response
+	transport := httpmock.NewMockTransport()
+	transport.RegisterResponder("GET", `https://example.com`, httpmock.NewStringResponder(http.StatusOK, "test"))
+
+	// Create client
+	ctx := context.Background()
+	c := New().WithTransport(transport).WithRetry(TestingRetry()).WithCache(cache.NewMemoryStore())
+
+	// The first request is sent, the second is served from the cache
+	for range 2 {
+		var out string
+		_, _, err := request.NewHTTPRequest(c).WithGet("https://example.com").WithResult(&out).WithCache(time.Hour).Send(ctx)
+		require.NoError(t, err)
+		assert.Equal(t, "test", out)
+	}
+	assert.Equal(t, 1, transport.GetCallCountInfo()["GET https://example.com"])
+
+	// Request without the caching enabled is always sent
+	_, _, err := request.NewHTTPRequest(c).WithGet("https://example.com").Send(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 2, transport.GetCallCountInfo()["GET https://example.com"])
+
+	// Responses for a different token are not mixed
+	_, _, err = request.NewHTTPRequest(c).WithGet("https://example.com").AndHeader("X-StorageApi-Token", "foo").WithCache(time.Hour).Send(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 3, transport.GetCallCountInfo()["GET https://example.com"])
+}
+
+func TestCache_ETag(t *testing.T) {
+	t.Parallel()
+
+	// Mocked response, the response is always stale, it must be revalidated by the ETag
+	transport := httpmock.NewMockTransport()
+	transport.RegisterResponder("GET", `https://example.com`, func(req *http.Request) (*http.Response, error) {
+		if req.Header.Get("If-None-Match") == `"v1"` {
+			return httpmock.NewStringResponse(http.StatusNotModified, ""), nil
+		}
+		res := httpmock.NewStringResponse(http.StatusOK, "test")
+		res.Header.Set("ETag", `"v1"`)
+		res.Header.Set("Cache-Control", "no-cache")
+		return res, nil
+	})
+
+	// Create client
+	ctx := context.Background()
+	store := cache.NewMemoryStore()
+	c := New().WithTransport(transport).WithRetry(TestingRetry()).WithCache(store)
+
+	// Each request is sent, the second response is served from the cache after revalidation
+	for range 2 {
+		var out string
+		res, _, err := request.NewHTTPRequest(c).WithGet("https://example.com").WithResult(&out).WithCache(time.Hour).Send(ctx)
+		require.NoError(t, err)
+		assert.Equal(t, http.StatusOK, res.StatusCode())
+		assert.Equal(t, "test", out)
+	}
+	assert.Equal(t, 2, transport.GetCallCountInfo()["GET https://example.com"])
+}
+
+func TestCache_NoStore(t *testing.T) {
+	t.Parallel()
+
+	// Mocked response
+	transport := httpmock.NewMockTransport()
+	transport.RegisterResponder("GET", `https://example.com`, func(req *http.Request) (*http.Response, error) {
+		res := httpmock.NewStringResponse(http.StatusOK, "test")
+		res.Header.Set("Cache-Control", "no-store")
+		return res, nil
+	})
+
+	// Create client
+	ctx := context.Background()
+	c := New().WithTransport(transport).WithRetry(TestingRetry()).WithCache(cache.NewMemoryStore())
+
+	// Both requests are sent
+	for range 2 {
+		_, _, err := request.NewHTTPRequest(c).WithGet("https://example.com").WithCache(time.Hour).Send(ctx)
+		require.NoError(t, err)
+	}
+	assert.Equal(t, 2, transport.GetCallCountInfo()["GET https://example.com"])
+}
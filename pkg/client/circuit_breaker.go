@@ -0,0 +1,180 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/keboola/go-client/pkg/client/trace"
+)
+
+// CircuitBreakerThreshold - default number of consecutive failures which opens the circuit.
+const CircuitBreakerThreshold = 5
+
+// CircuitBreakerCoolDown - default time for which the circuit is open.
+const CircuitBreakerCoolDown = 30 * time.Second
+
+// CircuitBreakerConfig configures the CircuitBreaker.
+type CircuitBreakerConfig struct {
+	// FailureThreshold is the number of consecutive failures of a host which opens its circuit.
+	FailureThreshold int
+	// CoolDown is the time for which the circuit is open, then one probe request is allowed, in the half-open state.
+	CoolDown time.Duration
+	// Failure defines which responses are failures of the host.
+	Failure func(*http.Response, error) bool
+}
+
+// CircuitBreaker rejects requests to a host which is failing, so the caller doesn't wait for all retries.
+//
+// Each host has own circuit, it starts closed, all requests are sent.
+// After the FailureThreshold consecutive failures, the circuit is opened and requests are rejected by the CircuitOpenError.
+// After the CoolDown period, the circuit is half-open, one probe request is sent, it decides whether the circuit is closed or opened again.
+//
+// The CircuitBreaker is shared by all clones of the Client, see Client.WithCircuitBreaker.
+type CircuitBreaker struct {
+	config   CircuitBreakerConfig
+	lock     sync.Mutex
+	circuits map[string]*circuit
+}
+
+// circuit is a state of one host.
+type circuit struct {
+	state    trace.CircuitState
+	failures int
+	openedAt time.Time
+	probing  bool // the half-open probe request is in progress
+}
+
+// CircuitOpenError is returned if a request is rejected by the CircuitBreaker.
+type CircuitOpenError struct {
+	Host    string
+	RetryAt time.Time
+}
+
+func (e CircuitOpenError) Error() string {
+	return fmt.Sprintf(`circuit breaker is open for host "%s", retry after %s`, e.Host, time.Until(e.RetryAt).Round(time.Second))
+}
+
+// DefaultCircuitBreakerConfig returns a default CircuitBreakerConfig.
+func DefaultCircuitBreakerConfig() CircuitBreakerConfig {
+	return CircuitBreakerConfig{
+		FailureThreshold: CircuitBreakerThreshold,
+		CoolDown:         CircuitBreakerCoolDown,
+		Failure:          DefaultCircuitBreakerFailure(),
+	}
+}
+
+// DefaultCircuitBreakerFailure considers network errors and server errors as failures, a canceled request is not a failure.
+func DefaultCircuitBreakerFailure() func(*http.Response, error) bool {
+	return func(response *http.Response, err error) bool {
+		if response == nil || response.StatusCode == 0 {
+			return err != nil && !errors.Is(err, context.Canceled)
+		}
+		switch response.StatusCode {
+		case
+			http.StatusInternalServerError,
+			http.StatusBadGateway,
+			http.StatusServiceUnavailable,
+			http.StatusGatewayTimeout:
+			return true
+		default:
+			return false
+		}
+	}
+}
+
+// NewCircuitBreaker creates a CircuitBreaker, it can be registered by the Client.WithCircuitBreaker method.
+func NewCircuitBreaker(config CircuitBreakerConfig) *CircuitBreaker {
+	if config.FailureThreshold <= 0 {
+		panic(fmt.Errorf("failure threshold must be greater than 0, found %d", config.FailureThreshold))
+	}
+	if config.Failure == nil {
+		config.Failure = DefaultCircuitBreakerFailure()
+	}
+	return &CircuitBreaker{config: config, circuits: make(map[string]*circuit)}
+}
+
+// State returns the current state of the host circuit.
+func (b *CircuitBreaker) State(host string) trace.CircuitState {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+	if c, found := b.circuits[host]; found {
+		return c.state
+	}
+	return trace.CircuitClosed
+}
+
+// allow checks if a request to the host can be sent, the state may change from open to half-open.
+// If the request is the half-open probe, the probe flag is true.
+func (b *CircuitBreaker) allow(host string, tc *trace.ClientTrace) (probe bool, err error) {
+	b.lock.Lock()
+	c := b.circuit(host)
+	from := c.state
+	switch c.state {
+	case trace.CircuitOpen:
+		if retryAt := c.openedAt.Add(b.config.CoolDown); time.Now().Before(retryAt) {
+			err = CircuitOpenError{Host: host, RetryAt: retryAt}
+		} else {
+			c.state = trace.CircuitHalfOpen
+			c.probing, probe = true, true
+		}
+	case trace.CircuitHalfOpen:
+		if c.probing {
+			err = CircuitOpenError{Host: host, RetryAt: time.Now()}
+		} else {
+			c.probing, probe = true, true
+		}
+	}
+	to := c.state
+	b.lock.Unlock()
+
+	traceCircuitStateChange(tc, host, from, to)
+	return probe, err
+}
+
+// record updates the host circuit by the request result.
+func (b *CircuitBreaker) record(host string, probe bool, res *http.Response, err error, tc *trace.ClientTrace) {
+	b.lock.Lock()
+	c := b.circuit(host)
+	from := c.state
+	if probe {
+		c.probing = false
+	}
+	switch {
+	case c.state == trace.CircuitHalfOpen && !probe:
+		// The request has been sent before the circuit was opened, only the probe decides the half-open state
+	case err != nil && errors.Is(err, context.Canceled):
+		// The request has been canceled by the caller, the result says nothing about the host
+	case !b.config.Failure(res, err):
+		c.failures = 0
+		c.state = trace.CircuitClosed
+	default:
+		c.failures++
+		if c.state == trace.CircuitHalfOpen || c.failures >= b.config.FailureThreshold {
+			c.openedAt = time.Now()
+			c.state = trace.CircuitOpen
+		}
+	}
+	to := c.state
+	b.lock.Unlock()
+
+	traceCircuitStateChange(tc, host, from, to)
+}
+
+func (b *CircuitBreaker) circuit(host string) *circuit {
+	c, found := b.circuits[host]
+	if !found {
+		c = &circuit{state: trace.CircuitClosed}
+		b.circuits[host] = c
+	}
+	return c
+}
+
+func traceCircuitStateChange(tc *trace.ClientTrace, host string, from, to trace.CircuitState) {
+	if from != to && tc != nil && tc.CircuitStateChange != nil {
+		tc.CircuitStateChange(host, from, to)
+	}
+}
@@ -0,0 +1,177 @@
+package client_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/jarcoal/httpmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	. "github.com/keboola/go-client/pkg/client"
+	. "github.com/keboola/go-client/pkg/client/trace"
+	"github.com/keboola/go-client/pkg/request"
+)
+
+func TestCircuitBreaker_Open(t *testing.T) {
+	t.Parallel()
+
+	// Mocked response
+	transport := httpmock.NewMockTransport()
+	transport.RegisterResponder("GET", `https://foo.example.com`, httpmock.NewStringResponder(http.StatusServiceUnavailable, "down"))
+	transport.RegisterResponder("GET", `https://bar.example.com`, httpmock.NewStringResponder(http.StatusOK, "OK"))
+
+	// Create client
+	var changes []string
+	ctx := context.Background()
+	breaker := NewCircuitBreaker(CircuitBreakerConfig{FailureThreshold: 3, CoolDown: time.Hour})
+	c := New().
+		WithTransport(transport).
+		WithRetry(TestingRetry()).
+		WithCircuitBreaker(breaker).
+		AndTrace(circuitTracer(&changes))
+
+	// The circuit is opened after 3 failures, other retries are skipped
+	_, _, err := request.NewHTTPRequest(c).WithGet("https://foo.example.com").Send(ctx)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), `request GET "https://foo.example.com" failed: circuit breaker is open for host "foo.example.com", retry after `)
+	var openErr CircuitOpenError
+	assert.True(t, errors.As(err, &openErr))
+	assert.Equal(t, 3, transport.GetCallCountInfo()["GET https://foo.example.com"])
+	assert.Equal(t, CircuitOpen, breaker.State("foo.example.com"))
+	assert.Equal(t, []string{"foo.example.com: closed -> open"}, changes)
+
+	// The next request is rejected immediately
+	_, _, err = request.NewHTTPRequest(c).WithGet("https://foo.example.com").Send(ctx)
+	require.Error(t, err)
+	assert.Equal(t, 3, transport.GetCallCountInfo()["GET https://foo.example.com"])
+
+	// Other host has own circuit
+	_, _, err = request.NewHTTPRequest(c).WithGet("https://bar.example.com").Send(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, CircuitClosed, breaker.State("bar.example.com"))
+}
+
+func TestCircuitBreaker_HalfOpen(t *testing.T) {
+	t.Parallel()
+
+	// Mocked response, the host recovers after 2 failed requests
+	calls := 0
+	transport := httpmock.NewMockTransport()
+	transport.RegisterResponder("GET", `https://example.com`, func(req *http.Request) (*http.Response, error) {
+		calls++
+		if calls <= 2 {
+			return httpmock.NewStringResponse(http.StatusBadGateway, "down"), nil
+		}
+		return httpmock.NewStringResponse(http.StatusOK, "OK"), nil
+	})
+
+	// Create client, without retries
+	var changes []string
+	ctx := context.Background()
+	breaker := NewCircuitBreaker(CircuitBreakerConfig{FailureThreshold: 1, CoolDown: 10 * time.Millisecond})
+	c := New().
+		WithTransport(transport).
+		WithRetry(RetryConfig{}).
+		WithCircuitBreaker(breaker).
+		AndTrace(circuitTracer(&changes))
+
+	// Open the circuit
+	_, _, err := request.NewHTTPRequest(c).WithGet("https://example.com").Send(ctx)
+	assert.Error(t, err)
+	assert.Equal(t, CircuitOpen, breaker.State("example.com"))
+
+	// The probe request fails, the circuit is opened again
+	time.Sleep(20 * time.Millisecond)
+	_, _, err = request.NewHTTPRequest(c).WithGet("https://example.com").Send(ctx)
+	assert.Error(t, err)
+	assert.Equal(t, CircuitOpen, breaker.State("example.com"))
+
+	// The probe request succeeds, the circuit is closed
+	time.Sleep(20 * time.Millisecond)
+	_, _, err = request.NewHTTPRequest(c).WithGet("https://example.com").Send(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, CircuitClosed, breaker.State("example.com"))
+
+	assert.Equal(t, 3, calls)
+	assert.Equal(t, []string{
+		"example.com: closed -> open",
+		"example.com: open -> half-open",
+		"example.com: half-open -> open",
+		"example.com: open -> half-open",
+		"example.com: half-open -> closed",
+	}, changes)
+}
+
+func TestCircuitBreaker_HalfOpen_LateResult(t *testing.T) {
+	t.Parallel()
+
+	// Mocked responses, the slow and the probe requests wait until they are released
+	slowStarted, releaseSlow := make(chan struct{}), make(chan struct{})
+	probeStarted, releaseProbe := make(chan struct{}), make(chan struct{})
+	transport := httpmock.NewMockTransport()
+	transport.RegisterResponder("GET", `https://example.com/slow`, func(req *http.Request) (*http.Response, error) {
+		close(slowStarted)
+		<-releaseSlow
+		return httpmock.NewStringResponse(http.StatusOK, "OK"), nil
+	})
+	transport.RegisterResponder("GET", `https://example.com/fail`, httpmock.NewStringResponder(http.StatusBadGateway, "down"))
+	transport.RegisterResponder("GET", `https://example.com/probe`, func(req *http.Request) (*http.Response, error) {
+		close(probeStarted)
+		<-releaseProbe
+		return httpmock.NewStringResponse(http.StatusBadGateway, "down"), nil
+	})
+
+	// Create client, without retries
+	ctx := context.Background()
+	breaker := NewCircuitBreaker(CircuitBreakerConfig{FailureThreshold: 2, CoolDown: 10 * time.Millisecond})
+	c := New().
+		WithTransport(transport).
+		WithRetry(RetryConfig{}).
+		WithCircuitBreaker(breaker)
+
+	// The slow request is sent when the circuit is closed
+	slowDone := make(chan error)
+	go func() {
+		slowDone <- request.NewHTTPRequest(c).WithGet("https://example.com/slow").SendOrErr(ctx)
+	}()
+	<-slowStarted
+
+	// Open the circuit
+	assert.Error(t, request.NewHTTPRequest(c).WithGet("https://example.com/fail").SendOrErr(ctx))
+	assert.Error(t, request.NewHTTPRequest(c).WithGet("https://example.com/fail").SendOrErr(ctx))
+	assert.Equal(t, CircuitOpen, breaker.State("example.com"))
+
+	// Send the probe request
+	time.Sleep(20 * time.Millisecond)
+	probeDone := make(chan error)
+	go func() {
+		probeDone <- request.NewHTTPRequest(c).WithGet("https://example.com/probe").SendOrErr(ctx)
+	}()
+	<-probeStarted
+	assert.Equal(t, CircuitHalfOpen, breaker.State("example.com"))
+
+	// The late success of the slow request doesn't close the circuit
+	close(releaseSlow)
+	assert.NoError(t, <-slowDone)
+	assert.Equal(t, CircuitHalfOpen, breaker.State("example.com"))
+
+	// The probe decides
+	close(releaseProbe)
+	assert.Error(t, <-probeDone)
+	assert.Equal(t, CircuitOpen, breaker.State("example.com"))
+}
+
+func circuitTracer(changes *[]string) Factory {
+	return func(ctx context.Context, reqDef request.HTTPRequest) (context.Context, *ClientTrace) {
+		return ctx, &ClientTrace{
+			CircuitStateChange: func(host string, from, to CircuitState) {
+				*changes = append(*changes, fmt.Sprintf("%s: %s -> %s", host, from, to))
+			},
+		}
+	}
+}
@@ -6,15 +6,16 @@
 package client
 
 import (
-	"bytes"
 	"context"
 	"errors"
 	"fmt"
 	"io"
+	"maps"
 	"net"
 	"net/http"
 	"net/http/httptrace"
 	"net/url"
+	"slices"
 	"strings"
 	"time"
 
@@ -22,8 +23,10 @@ import (
 	otelMetric "go.opentelemetry.io/otel/metric"
 	otelTrace "go.opentelemetry.io/otel/trace"
 
+	"github.com/keboola/go-client/pkg/client/cache"
 	"github.com/keboola/go-client/pkg/client/counter"
 	"github.com/keboola/go-client/pkg/client/decode"
+	"github.com/keboola/go-client/pkg/client/encode"
 	"github.com/keboola/go-client/pkg/client/trace"
 	"github.com/keboola/go-client/pkg/client/trace/otel"
 	"github.com/keboola/go-client/pkg/request"
@@ -44,12 +47,16 @@ type Client struct {
 	baseURL        *url.URL
 	header         http.Header
 	retry          RetryConfig
+	circuitBreaker *CircuitBreaker
+	cache          cache.Store
+	codecs         map[string]Codec
+	middlewares    []request.Middleware
 	traceFactories []trace.Factory
 }
 
 // New creates new HTTP Client.
 func New() Client {
-	c := Client{transport: DefaultTransport(), header: make(http.Header), retry: DefaultRetry()}
+	c := Client{transport: DefaultTransport(), header: make(http.Header), retry: DefaultRetry(), codecs: DefaultCodecs()}
 	c.header.Set("User-Agent", "keboola-go-client")
 	c.header.Set("Accept-Encoding", "gzip, br")
 	return c
@@ -67,6 +74,15 @@ func (c Client) WithBaseURL(baseURLStr string) Client {
 	return c
 }
 
+// BaseURL returns the base url, if any.
+func (c Client) BaseURL() *url.URL {
+	if c.baseURL == nil {
+		return nil
+	}
+	out := *c.baseURL
+	return &out
+}
+
 // WithUserAgent returns a clone of the Client with user agent set.
 func (c Client) WithUserAgent(v string) Client {
 	c.header.Set("User-Agent", v)
@@ -104,6 +120,39 @@ func (c Client) WithRetry(retry RetryConfig) Client {
 	return c
 }
 
+// WithCircuitBreaker returns a clone of the Client with the circuit breaker set.
+// The circuit breaker state is shared by all clones, see CircuitBreaker.
+func (c Client) WithCircuitBreaker(breaker *CircuitBreaker) Client {
+	c.circuitBreaker = breaker
+	return c
+}
+
+// WithCache returns a clone of the Client with the response cache set.
+// Only GET requests with the caching enabled are cached, see request.HTTPRequest.WithCache.
+func (c Client) WithCache(store cache.Store) Client {
+	c.cache = store
+	return c
+}
+
+// WithCodec returns a clone of the Client with the codec of the media type set, for example "text/csv".
+// The codec decodes a successful response to the result definition of the request, see DefaultCodecs.
+// If the result definition can be decoded only by a codec, for example a CSVUnmarshaler, and the media type has no codec, an error is returned.
+func (c Client) WithCodec(mediaType string, codec Codec) Client {
+	c.codecs = maps.Clone(c.codecs)
+	if c.codecs == nil {
+		c.codecs = make(map[string]Codec)
+	}
+	c.codecs[strings.ToLower(mediaType)] = codec
+	return c
+}
+
+// WithMiddleware returns a clone of the Client with the middlewares added.
+// The first registered middleware is the outermost, it processes the request first and the response last.
+func (c Client) WithMiddleware(middlewares ...request.Middleware) Client {
+	c.middlewares = append(slices.Clone(c.middlewares), middlewares...)
+	return c
+}
+
 // WithTelemetry enables OpenTelemetry tracing and metrics.
 func (c Client) WithTelemetry(tracerProvider otelTrace.TracerProvider, meterProvider otelMetric.MeterProvider, opts ...otel.Option) Client {
 	if tracerProvider == nil && meterProvider == nil {
@@ -128,12 +177,21 @@ func (c Client) Tracer() otelTrace.Tracer {
 }
 
 // Send method sends HTTP request and returns HTTP response, it implements the Sender interface.
+// The request is processed by the registered middlewares first, see WithMiddleware.
 func (c Client) Send(ctx context.Context, reqDef request.HTTPRequest) (res *http.Response, result any, err error) {
 	// Method cannot be called on an empty value
 	if c.transport == nil {
 		panic(fmt.Errorf("client value is not initialized"))
 	}
 
+	if len(c.middlewares) > 0 {
+		return request.Chain(request.SenderFunc(c.send), c.middlewares...).Send(ctx, reqDef)
+	}
+	return c.send(ctx, reqDef)
+}
+
+// send method sends HTTP request by the native HTTP client, without middlewares.
+func (c Client) send(ctx context.Context, reqDef request.HTTPRequest) (res *http.Response, result any, err error) {
 	// If method or url is not set, panic occurs. So we get these values first.
 	method := reqDef.Method()
 	reqURL := reqDef.URL()
@@ -197,12 +255,31 @@ func (c Client) Send(ctx context.Context, reqDef request.HTTPRequest) (res *http
 		if err != nil {
 			return nil, nil, err
 		}
+		if req.Body != nil && reqDef.BodyEncoding() != "" {
+			req.Header.Set("Content-Encoding", reqDef.BodyEncoding())
+		}
+	}
+
+	// Retry and timeout can be overridden by the request
+	retry := c.retry
+	if v := reqDef.Retry(); v != nil {
+		retry = *v
+	}
+	if fn := reqDef.RetryModifier(); fn != nil {
+		retry = fn(retry)
+	}
+	if v := reqDef.Timeout(); v > 0 {
+		retry.TotalRequestTimeout = v
 	}
 
 	// Setup native client
+	var transport http.RoundTripper = roundTripper{retry: retry, idempotent: reqDef.Idempotent(), breaker: c.circuitBreaker, trace: tc, wrapped: c.transport} // wrapped transport for trace/retry
+	if c.cache != nil && method == http.MethodGet && reqDef.CacheTTL() > 0 {
+		transport = cacheRoundTripper{store: c.cache, ttl: reqDef.CacheTTL(), wrapped: transport}
+	}
 	nativeClient := http.Client{
-		Timeout:   c.retry.TotalRequestTimeout,
-		Transport: roundTripper{retry: c.retry, trace: tc, wrapped: c.transport}, // wrapped transport for trace/retry
+		Timeout:   retry.TotalRequestTimeout,
+		Transport: transport,
 	}
 
 	// Send request
@@ -218,7 +295,7 @@ func (c Client) Send(ctx context.Context, reqDef request.HTTPRequest) (res *http
 
 	// Handle send error
 	if err != nil {
-		return nil, nil, handleSendError(startedAt, c.retry.TotalRequestTimeout, req, err)
+		return nil, nil, handleSendError(startedAt, retry.TotalRequestTimeout, req, err)
 	}
 
 	// Parse body
@@ -230,7 +307,7 @@ func (c Client) Send(ctx context.Context, reqDef request.HTTPRequest) (res *http
 
 		// Parse
 		var parseError error
-		result, err, parseError = handleResponseBody(res, reqDef.ResultDef(), reqDef.ErrorDef())
+		result, err, parseError = handleResponseBody(res, reqDef.ResultDef(), reqDef.ErrorDef(), reqDef.ResponseMediaType(), c.codecs)
 
 		// Trace BodyParseDone
 		if tc != nil && tc.BodyParseDone != nil {
@@ -251,46 +328,16 @@ func (c Client) Send(ctx context.Context, reqDef request.HTTPRequest) (res *http
 	return res, result, err
 }
 
+// requestBody returns a new stream of the request body, compressed, if the request body encoding is set.
 func requestBody(r request.HTTPRequest) (io.ReadCloser, error) {
-	contentType := r.RequestHeader().Get("Content-Type")
-	body := r.RequestBody()
-	if v, ok := body.(string); ok {
-		return io.NopCloser(strings.NewReader(v)), nil
-	}
-	if v, ok := body.([]byte); ok {
-		return io.NopCloser(bytes.NewReader(v)), nil
-	}
-	if v, ok := body.(io.ReadSeekCloser); ok {
-		// io.ReadSeekCloser stream
-		if _, err := v.Seek(0, io.SeekStart); err != nil {
-			return nil, err
-		}
-		return v, nil
+	body, err := request.EncodeBody(r)
+	if err != nil || body == nil || r.BodyEncoding() == "" {
+		return body, err
 	}
-	if v, ok := body.(io.ReadSeeker); ok {
-		// io.ReadSeeker stream
-		if _, err := v.Seek(0, io.SeekStart); err != nil {
-			return nil, err
-		}
-		return io.NopCloser(v), nil
-	}
-	if body != nil && isJSONContentType(contentType) {
-		// Json body
-		c, err := json.Marshal(body)
-		if err != nil {
-			return nil, fmt.Errorf(`cannot encode JSON body: %w`, err)
-		}
-		return io.NopCloser(bytes.NewReader(c)), nil
-	}
-	if body == nil {
-		// empty body
-		return nil, nil
-	}
-	// unsupported body
-	return nil, fmt.Errorf(`unsupported request body type "%T", to encode body as JSON please specify content-type header`, body)
+	return encode.Encode(body, r.BodyEncoding())
 }
 
-func handleResponseBody(r *http.Response, resultDef any, errDef error) (result any, err error, parseError error) {
+func handleResponseBody(r *http.Response, resultDef any, errDef error, mediaType string, codecs map[string]Codec) (result any, err error, parseError error) {
 	defer r.Body.Close()
 
 	if r.StatusCode == http.StatusNoContent {
@@ -305,7 +352,11 @@ func handleResponseBody(r *http.Response, resultDef any, errDef error) (result a
 
 	// Process content type, for example "application/json; charset=utf-8"
 	contentType, _, _ := strings.Cut(r.Header.Get("Content-Type"), ";")
-	contentType = strings.TrimSpace(contentType)
+	contentType = strings.ToLower(strings.TrimSpace(contentType))
+	if mediaType != "" && r.StatusCode > 199 && r.StatusCode < 300 {
+		// The media type of a successful response is set by the request, see HTTPRequest.WithResponseMediaType
+		contentType = mediaType
+	}
 	if v, ok := resultDef.(*[]byte); ok {
 		// Load response body as []byte
 		bodyBytes, err := io.ReadAll(decodedBody)
@@ -337,7 +388,13 @@ func handleResponseBody(r *http.Response, resultDef any, errDef error) (result a
 		}
 	} else if isJSONContentType(contentType) {
 		// Map JSON response
-		if r.StatusCode > 199 && r.StatusCode < 300 && resultDef != nil {
+		if v, ok := resultDef.(request.StreamDecoder); ok && r.StatusCode > 199 && r.StatusCode < 300 {
+			// Decode JSON response as a stream
+			if err := v.DecodeStream(decodedBody); err != nil {
+				return nil, nil, err
+			}
+			return resultDef, nil, nil
+		} else if r.StatusCode > 199 && r.StatusCode < 300 && resultDef != nil {
 			// Map JSON response to defined result
 			if err := json.NewDecoder(decodedBody).Decode(resultDef); err != nil {
 				return nil, nil, fmt.Errorf(`cannot decode JSON result: %w`, err)
@@ -362,6 +419,21 @@ func handleResponseBody(r *http.Response, resultDef any, errDef error) (result a
 			}
 			return nil, errDef, nil
 		}
+	} else if r.StatusCode > 199 && r.StatusCode < 300 && resultDef != nil {
+		// Map response to defined result by the codec of the content type
+		if codec, found := codecs[contentType]; found {
+			if err := codec.Decode(decodedBody, resultDef); err != nil {
+				return nil, nil, err
+			}
+			return resultDef, nil, nil
+		}
+		// The result can be decoded only by a codec, an empty result would be silently returned.
+		// Other results of a content type without a codec are ignored, as before the codecs were introduced.
+		if isCodecResult(resultDef) {
+			if n, _ := io.Copy(io.Discard, decodedBody); n > 0 {
+				return nil, nil, fmt.Errorf(`cannot decode result "%T": unsupported content type "%s", see Client.WithCodec and HTTPRequest.WithResponseMediaType`, resultDef, contentType)
+			}
+		}
 	}
 	return nil, nil, nil
 }
@@ -392,16 +464,34 @@ func handleSendError(startedAt time.Time, clientTimeout time.Duration, req *http
 
 // roundTripper wraps a http.RoundTripper and adds trace and retry functionality.
 type roundTripper struct {
-	trace   *trace.ClientTrace
-	retry   RetryConfig
-	wrapped http.RoundTripper
+	trace      *trace.ClientTrace
+	retry      RetryConfig
+	idempotent *bool // explicit idempotency flag from the request definition, if any
+	breaker    *CircuitBreaker
+	wrapped    http.RoundTripper
 }
 
 func (rt roundTripper) RoundTrip(req *http.Request) (*http.Response, error) {
 	ctx := req.Context()
 	state := rt.retry.NewBackoff()
+	startedAt := time.Now()
+	idempotent := request.IsIdempotent(req, rt.idempotent)
+	condition := rt.retry.ConditionFor(idempotent)
+	if condition == nil && !idempotent {
+		// See RetryConfig.NonIdempotentCondition
+		condition = ConnectionRetryCondition()
+	}
 	attempt := 0
 	for {
+		// Reject the request, if the host is failing
+		var probe bool
+		if rt.breaker != nil {
+			var err error
+			if probe, err = rt.breaker.allow(req.URL.Host, rt.trace); err != nil {
+				return nil, err
+			}
+		}
+
 		// Trace request start
 		if rt.trace != nil && rt.trace.HTTPRequestStart != nil {
 			rt.trace.HTTPRequestStart(req)
@@ -425,6 +515,11 @@ func (rt roundTripper) RoundTrip(req *http.Request) (*http.Response, error) {
 		// Send
 		res, err := rt.wrapped.RoundTrip(req)
 
+		// Update the circuit of the host
+		if rt.breaker != nil {
+			rt.breaker.record(req.URL.Host, probe, res, err, rt.trace)
+		}
+
 		// Trace response
 		if rt.trace != nil && rt.trace.HTTPResponse != nil {
 			rt.trace.HTTPResponse(res, err)
@@ -451,7 +546,7 @@ func (rt roundTripper) RoundTrip(req *http.Request) (*http.Response, error) {
 		}
 
 		// Check if we should retry
-		if rt.retry.Condition == nil || !rt.retry.Condition(res, err) || attempt >= rt.retry.Count {
+		if condition == nil || !condition(res, err) || attempt >= rt.retry.Count {
 			// No retry
 			return res, err
 		}
@@ -468,10 +563,27 @@ func (rt roundTripper) RoundTrip(req *http.Request) (*http.Response, error) {
 			return res, err
 		}
 
+		// The delay requested by the server has priority, it is capped by the maximum wait time and the remaining timeout
+		reason := trace.RetryDelayBackoff
+		if v, r, ok := retryDelayFromHeader(res, time.Now()); ok {
+			delay, reason = v, r
+			if rt.retry.WaitTimeMax > 0 {
+				delay = min(delay, rt.retry.WaitTimeMax)
+			}
+			if rt.retry.TotalRequestTimeout > 0 {
+				delay = max(min(delay, rt.retry.TotalRequestTimeout-time.Since(startedAt)), 0)
+			}
+		}
+
 		// Trace retry
 		attempt++
 		if rt.trace != nil && rt.trace.RetryDelay != nil {
-			rt.trace.RetryDelay(attempt, delay)
+			rt.trace.RetryDelay(attempt, delay, reason)
+		}
+
+		// Trace retry of a mutation, the previous attempt may have been processed by the server
+		if rt.trace != nil && rt.trace.MutationRetry != nil && !request.IsSafeMethod(req.Method) {
+			rt.trace.MutationRetry(req, attempt, res, err)
 		}
 
 		// Rewind body before retry
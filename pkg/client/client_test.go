@@ -2,6 +2,7 @@ package client_test
 
 import (
 	"context"
+	"encoding/json"
 	"io"
 	"net/http"
 	"strings"
@@ -12,8 +13,9 @@ import (
 	"github.com/stretchr/testify/assert"
 
 	. "github.com/keboola/go-client/pkg/client"
+	"github.com/keboola/go-client/pkg/client/decode"
 	. "github.com/keboola/go-client/pkg/client/trace"
-	. "github.com/keboola/go-client/pkg/request"
+	"github.com/keboola/go-client/pkg/request"
 )
 
 type testStruct struct {
@@ -52,7 +54,7 @@ func TestRequest(t *testing.T) {
 
 	ctx := context.Background()
 	c := New().WithTransport(transport).WithRetry(TestingRetry())
-	_, _, err := NewHTTPRequest(c).WithGet("https://example.com").Send(ctx)
+	_, _, err := request.NewHTTPRequest(c).WithGet("https://example.com").Send(ctx)
 	assert.NoError(t, err)
 	assert.Equal(t, 1, transport.GetCallCountInfo()["GET https://example.com"])
 }
@@ -67,7 +69,7 @@ func TestBytesResult(t *testing.T) {
 	ctx := context.Background()
 	c := New().WithTransport(transport).WithRetry(TestingRetry())
 	var resultDef []byte
-	_, result, err := NewHTTPRequest(c).WithGet("https://example.com").WithResult(&resultDef).Send(ctx)
+	_, result, err := request.NewHTTPRequest(c).WithGet("https://example.com").WithResult(&resultDef).Send(ctx)
 	assert.NoError(t, err)
 	assert.Same(t, &resultDef, result)
 	assert.Equal(t, []byte(`{"foo":"bar"}`), resultDef)
@@ -84,7 +86,7 @@ func TestWriterResult(t *testing.T) {
 	ctx := context.Background()
 	c := New().WithTransport(transport).WithRetry(TestingRetry())
 	var out strings.Builder
-	_, _, err := NewHTTPRequest(c).WithGet("https://example.com").WithResult(io.Writer(&out)).Send(ctx)
+	_, _, err := request.NewHTTPRequest(c).WithGet("https://example.com").WithResult(io.Writer(&out)).Send(ctx)
 	assert.NoError(t, err)
 	assert.Equal(t, `{"foo":"bar"}`, out.String())
 	assert.Equal(t, 1, transport.GetCallCountInfo()["GET https://example.com"])
@@ -100,7 +102,7 @@ func TestWriteCloserResult(t *testing.T) {
 	ctx := context.Background()
 	c := New().WithTransport(transport).WithRetry(TestingRetry())
 	var out strings.Builder
-	_, _, err := NewHTTPRequest(c).WithGet("https://example.com").WithResult(testWriteCloser{Writer: &out}).Send(ctx)
+	_, _, err := request.NewHTTPRequest(c).WithGet("https://example.com").WithResult(testWriteCloser{Writer: &out}).Send(ctx)
 	assert.NoError(t, err)
 	assert.Equal(t, `{"foo":"bar"}<CLOSE>`, out.String())
 	assert.Equal(t, 1, transport.GetCallCountInfo()["GET https://example.com"])
@@ -116,7 +118,7 @@ func TestJsonMapResult(t *testing.T) {
 	ctx := context.Background()
 	c := New().WithTransport(transport).WithRetry(TestingRetry())
 	resultDef := make(map[string]any)
-	_, result, err := NewHTTPRequest(c).WithGet("https://example.com").WithResult(&resultDef).Send(ctx)
+	_, result, err := request.NewHTTPRequest(c).WithGet("https://example.com").WithResult(&resultDef).Send(ctx)
 	assert.NoError(t, err)
 	assert.Same(t, &resultDef, result)
 	assert.Equal(t, &map[string]any{"foo": "bar"}, result)
@@ -137,7 +139,7 @@ func TestJsonMapResult_ContentTypeWithCharset(t *testing.T) {
 	ctx := context.Background()
 	c := New().WithTransport(transport).WithRetry(TestingRetry())
 	resultDef := make(map[string]any)
-	_, result, err := NewHTTPRequest(c).WithGet("https://example.com").WithResult(&resultDef).Send(ctx)
+	_, result, err := request.NewHTTPRequest(c).WithGet("https://example.com").WithResult(&resultDef).Send(ctx)
 	assert.NoError(t, err)
 	assert.Same(t, &resultDef, result)
 	assert.Equal(t, &map[string]any{"foo": "bar"}, result)
@@ -154,7 +156,7 @@ func TestJsonStructResult(t *testing.T) {
 	ctx := context.Background()
 	c := New().WithTransport(transport).WithRetry(TestingRetry())
 	resultDef := &testStruct{}
-	_, result, err := NewHTTPRequest(c).WithGet("https://example.com").WithResult(resultDef).Send(ctx)
+	_, result, err := request.NewHTTPRequest(c).WithGet("https://example.com").WithResult(resultDef).Send(ctx)
 	assert.NoError(t, err)
 	assert.Same(t, resultDef, result)
 	assert.Equal(t, &testStruct{Foo: "bar"}, result)
@@ -171,7 +173,7 @@ func TestJsonErrorResult(t *testing.T) {
 	ctx := context.Background()
 	c := New().WithTransport(transport).WithRetry(TestingRetry())
 	errDef := &testError{}
-	_, _, err := NewHTTPRequest(c).WithGet("https://example.com").WithError(errDef).Send(ctx)
+	_, _, err := request.NewHTTPRequest(c).WithGet("https://example.com").WithError(errDef).Send(ctx)
 	assert.Error(t, err)
 	assert.Same(t, errDef, err)
 	assert.Equal(t, &testError{ErrorMsg: "error message"}, err)
@@ -187,7 +189,7 @@ func TestWithBaseUrl(t *testing.T) {
 
 	ctx := context.Background()
 	c := New().WithTransport(transport).WithRetry(TestingRetry()).WithBaseURL("https://example.com")
-	_, _, err := NewHTTPRequest(c).WithGet("baz").Send(ctx)
+	_, _, err := request.NewHTTPRequest(c).WithGet("baz").Send(ctx)
 	assert.NoError(t, err)
 	assert.Equal(t, 1, transport.GetCallCountInfo()["GET https://example.com/baz"])
 }
@@ -204,7 +206,7 @@ func TestRequestContext(t *testing.T) {
 	})
 	ctx := context.WithValue(context.Background(), "testKey", "testValue") //nolint:staticcheck
 	c := New().WithTransport(transport).WithRetry(TestingRetry())
-	_, _, err := NewHTTPRequest(c).WithGet("https://example.com").Send(ctx)
+	_, _, err := request.NewHTTPRequest(c).WithGet("https://example.com").Send(ctx)
 	assert.NoError(t, err)
 	assert.Equal(t, 1, transport.GetCallCountInfo()["GET https://example.com"])
 }
@@ -224,7 +226,7 @@ func TestDefaultHeaders(t *testing.T) {
 
 	ctx := context.Background()
 	c := New().WithTransport(transport).WithRetry(TestingRetry())
-	_, _, err := NewHTTPRequest(c).WithGet("https://example.com").Send(ctx)
+	_, _, err := request.NewHTTPRequest(c).WithGet("https://example.com").Send(ctx)
 	assert.NoError(t, err)
 	assert.Equal(t, 1, transport.GetCallCountInfo()["GET https://example.com"])
 }
@@ -244,7 +246,7 @@ func TestWithUserAgent(t *testing.T) {
 
 	ctx := context.Background()
 	c := New().WithTransport(transport).WithRetry(TestingRetry()).WithUserAgent("my-user-agent")
-	_, _, err := NewHTTPRequest(c).WithGet("https://example.com").Send(ctx)
+	_, _, err := request.NewHTTPRequest(c).WithGet("https://example.com").Send(ctx)
 	assert.NoError(t, err)
 	assert.Equal(t, 1, transport.GetCallCountInfo()["GET https://example.com"])
 }
@@ -265,7 +267,7 @@ func TestWithHeader(t *testing.T) {
 
 	ctx := context.Background()
 	c := New().WithTransport(transport).WithRetry(TestingRetry()).WithHeader("my-header", "my-value")
-	_, _, err := NewHTTPRequest(c).WithGet("https://example.com").Send(ctx)
+	_, _, err := request.NewHTTPRequest(c).WithGet("https://example.com").Send(ctx)
 	assert.NoError(t, err)
 	assert.Equal(t, 1, transport.GetCallCountInfo()["GET https://example.com"])
 }
@@ -290,7 +292,7 @@ func TestWithHeaders(t *testing.T) {
 		"key1": "value1",
 		"key2": "value2",
 	})
-	_, _, err := NewHTTPRequest(c).WithGet("https://example.com").Send(ctx)
+	_, _, err := request.NewHTTPRequest(c).WithGet("https://example.com").Send(ctx)
 	assert.NoError(t, err)
 	assert.Equal(t, 1, transport.GetCallCountInfo()["GET https://example.com"])
 }
@@ -316,7 +318,7 @@ func TestRequestTimeout(t *testing.T) {
 		})
 
 	// Get
-	_, _, err := NewHTTPRequest(c).WithGet("https://example.com").Send(ctx)
+	_, _, err := request.NewHTTPRequest(c).WithGet("https://example.com").Send(ctx)
 	assert.Error(t, err)
 	assert.Contains(t, err.Error(), `request GET "https://example.com" failed: timeout after`)
 }
@@ -336,8 +338,8 @@ func TestContext_DeadlineExceeded(t *testing.T) {
 	defer cancel()
 	c := New().WithTransport(transport)
 
-	wg := NewWaitGroup(ctx)
-	wg.Send(NewHTTPRequest(c).WithGet("https://example.com"))
+	wg := request.NewWaitGroup(ctx)
+	wg.Send(request.NewHTTPRequest(c).WithGet("https://example.com"))
 	err := wg.Wait()
 	assert.Error(t, err)
 	assert.Contains(t, err.Error(), `request GET "https://example.com" failed: timeout after`)
@@ -357,8 +359,8 @@ func TestContext_Canceled(t *testing.T) {
 	ctx, cancel := context.WithCancel(context.Background())
 	c := New().WithTransport(transport)
 
-	wg := NewWaitGroup(ctx)
-	wg.Send(NewHTTPRequest(c).WithGet("https://example.com"))
+	wg := request.NewWaitGroup(ctx)
+	wg.Send(request.NewHTTPRequest(c).WithGet("https://example.com"))
 
 	time.Sleep(50 * time.Millisecond)
 	cancel()
@@ -389,16 +391,16 @@ func TestStopRetryOnRequestTimeout(t *testing.T) {
 			WaitTimeStart:       40 * time.Millisecond, // <<<<<<<
 			WaitTimeMax:         40 * time.Millisecond,
 		}).
-		AndTrace(func(ctx context.Context, _ HTTPRequest) (context.Context, *ClientTrace) {
+		AndTrace(func(ctx context.Context, _ request.HTTPRequest) (context.Context, *ClientTrace) {
 			return ctx, &ClientTrace{
-				RetryDelay: func(_ int, delay time.Duration) {
+				RetryDelay: func(_ int, delay time.Duration, _ RetryDelayReason) {
 					delays = append(delays, delay)
 				},
 			}
 		})
 
 	// Get
-	_, _, err := NewHTTPRequest(c).WithGet("https://example.com").Send(ctx)
+	_, _, err := request.NewHTTPRequest(c).WithGet("https://example.com").Send(ctx)
 	assert.Error(t, err)
 	assert.Equal(t, `request GET "https://example.com" failed: 504 Gateway Timeout`, err.Error())
 
@@ -408,3 +410,104 @@ func TestStopRetryOnRequestTimeout(t *testing.T) {
 	// Check delays
 	assert.Empty(t, delays)
 }
+
+func TestRequestTimeoutOverride(t *testing.T) {
+	t.Parallel()
+
+	// Mocked response
+	transport := httpmock.NewMockTransport()
+	transport.RegisterResponder("GET", "https://example.com", func(request *http.Request) (*http.Response, error) {
+		time.Sleep(100 * time.Millisecond) // <<<<<<<
+		return httpmock.NewStringResponse(200, "test"), nil
+	})
+
+	// Create client
+	ctx := context.Background()
+	c := New().
+		WithTransport(transport).
+		WithRetry(RetryConfig{
+			Condition:           DefaultRetryCondition(),
+			Count:               10,
+			TotalRequestTimeout: 5 * time.Millisecond, // <<<<<<<
+		})
+
+	// Get, the default timeout is exceeded
+	_, _, err := request.NewHTTPRequest(c).WithGet("https://example.com").Send(ctx)
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), `request GET "https://example.com" failed: timeout after`)
+
+	// Get, the timeout is overridden
+	_, _, err = request.NewHTTPRequest(c).WithGet("https://example.com").WithTimeout(time.Second).Send(ctx)
+	assert.NoError(t, err)
+}
+
+func TestWithMiddleware(t *testing.T) {
+	t.Parallel()
+
+	// Mocked response
+	transport := httpmock.NewMockTransport()
+	transport.RegisterResponder("GET", "https://example.com", func(req *http.Request) (*http.Response, error) {
+		return httpmock.NewStringResponse(http.StatusOK, req.Header.Get("X-Foo")), nil
+	})
+
+	// Middleware sets a header
+	headerMiddleware := func(value string) request.Middleware {
+		return func(next request.Sender) request.Sender {
+			return request.SenderFunc(func(ctx context.Context, req request.HTTPRequest) (*http.Response, any, error) {
+				return next.Send(ctx, req.AndHeader("X-Foo", req.RequestHeader().Get("X-Foo")+value))
+			})
+		}
+	}
+
+	// Middlewares are composable, the original client is not modified
+	ctx := context.Background()
+	c := New().WithTransport(transport).WithMiddleware(headerMiddleware("1"))
+	c2 := c.WithMiddleware(headerMiddleware("2"), headerMiddleware("3"))
+
+	var result string
+	_, _, err := request.NewHTTPRequest(c).WithGet("https://example.com").WithResult(&result).Send(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, "1", result)
+
+	_, _, err = request.NewHTTPRequest(c2).WithGet("https://example.com").WithResult(&result).Send(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, "123", result)
+}
+
+func TestCompressedBody(t *testing.T) {
+	t.Parallel()
+
+	// Mocked response, decompress the body
+	jsonBody := map[string]any{"foo": strings.Repeat("bar", 1000)}
+	var received []int
+	transport := httpmock.NewMockTransport()
+	transport.RegisterResponder("PUT", "https://example.com", func(req *http.Request) (*http.Response, error) {
+		body, err := decode.Decode(req.Body, req.Header.Get("Content-Encoding"))
+		assert.NoError(t, err)
+		var out map[string]any
+		assert.NoError(t, json.NewDecoder(body).Decode(&out))
+		assert.Equal(t, jsonBody, out)
+		return httpmock.NewStringResponse(http.StatusBadGateway, "retry!"), nil
+	})
+
+	// Trace the size of the sent body
+	c := New().WithTransport(transport).WithRetry(TestingRetry()).AndTrace(func(ctx context.Context, _ request.HTTPRequest) (context.Context, *ClientTrace) {
+		return ctx, &ClientTrace{
+			HTTPRequestDone: func(_ *http.Response, send, _ int64, _ error) {
+				received = append(received, int(send))
+			},
+		}
+	})
+
+	// The body is compressed and rewound for each retry attempt
+	for _, encoding := range []string{"gzip", "br"} {
+		received = nil
+		_, _, err := request.NewHTTPRequest(c).WithPut("https://example.com").WithJSONBody(jsonBody).WithCompressedBody(encoding).Send(context.Background())
+		assert.Error(t, err)
+		assert.Len(t, received, 1+5)
+		for _, send := range received {
+			assert.Positive(t, send)
+			assert.Less(t, send, 3000, encoding)
+		}
+	}
+}
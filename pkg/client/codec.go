@@ -0,0 +1,141 @@
+package client
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/csv"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"reflect"
+)
+
+const (
+	ContentTypeApplicationNDJSON = "application/x-ndjson"
+	ContentTypeApplicationXML    = "application/xml"
+	ContentTypeTextXML           = "text/xml"
+	ContentTypeTextCSV           = "text/csv"
+)
+
+// Codec decodes a response body of a media type to the result definition of the request, see Client.WithCodec.
+type Codec interface {
+	Decode(body io.Reader, resultDef any) error
+}
+
+// CodecFunc is an adapter to allow the use of an ordinary function as the Codec.
+type CodecFunc func(body io.Reader, resultDef any) error
+
+// Decode calls f(body, resultDef).
+func (f CodecFunc) Decode(body io.Reader, resultDef any) error {
+	return f(body, resultDef)
+}
+
+// CSVUnmarshaler is implemented by types that can decode CSV records, see CSVCodec.
+type CSVUnmarshaler interface {
+	UnmarshalCSV(records [][]string) error
+}
+
+// isCodecResult returns true, if the result definition can be decoded only by a codec, not by the built-in JSON decoder.
+func isCodecResult(resultDef any) bool {
+	switch resultDef.(type) {
+	case *[][]string, CSVUnmarshaler:
+		return true
+	default:
+		return false
+	}
+}
+
+// DefaultCodecs returns codecs registered in a new Client.
+// The JSON content types are always decoded by the built-in JSON decoder.
+func DefaultCodecs() map[string]Codec {
+	return map[string]Codec{
+		ContentTypeApplicationNDJSON: NDJSONCodec(),
+		ContentTypeTextCSV:           CSVCodec(),
+		ContentTypeApplicationXML:    XMLCodec(),
+		ContentTypeTextXML:           XMLCodec(),
+	}
+}
+
+// NDJSONCodec decodes newline delimited JSON values.
+// The result definition must be a pointer to a slice, or a callback func(item T) error, which is called for each value.
+func NDJSONCodec() Codec {
+	errorType := reflect.TypeOf((*error)(nil)).Elem()
+	return CodecFunc(func(body io.Reader, resultDef any) error {
+		reader := bufio.NewReader(body)
+		target := reflect.ValueOf(resultDef)
+
+		// Get the item type and the function which processes the item
+		var itemType reflect.Type
+		var processItem func(item reflect.Value) error
+		switch {
+		case target.Kind() == reflect.Pointer && target.Elem().Kind() == reflect.Slice:
+			slice := target.Elem()
+			itemType = slice.Type().Elem()
+			processItem = func(item reflect.Value) error {
+				slice.Set(reflect.Append(slice, item))
+				return nil
+			}
+		case target.Kind() == reflect.Func && target.Type().NumIn() == 1 && target.Type().NumOut() == 1 && target.Type().Out(0) == errorType:
+			itemType = target.Type().In(0)
+			processItem = func(item reflect.Value) error {
+				err, _ := target.Call([]reflect.Value{item})[0].Interface().(error)
+				return err
+			}
+		default:
+			return fmt.Errorf(`unsupported NDJSON result type "%T", expected a pointer to a slice or a func(item T) error`, resultDef)
+		}
+
+		for {
+			line, readErr := reader.ReadBytes('\n')
+			if readErr != nil && !errors.Is(readErr, io.EOF) {
+				return fmt.Errorf(`cannot read NDJSON: %w`, readErr)
+			}
+
+			// Skip empty lines
+			if line = bytes.TrimSpace(line); len(line) > 0 {
+				item := reflect.New(itemType)
+				if err := json.Unmarshal(line, item.Interface()); err != nil {
+					return fmt.Errorf(`cannot decode NDJSON item: %w`, err)
+				}
+				if err := processItem(item.Elem()); err != nil {
+					return err
+				}
+			}
+
+			if readErr != nil {
+				return nil
+			}
+		}
+	})
+}
+
+// CSVCodec decodes CSV records.
+// The result definition must be a *[][]string or it must implement the CSVUnmarshaler interface.
+func CSVCodec() Codec {
+	return CodecFunc(func(body io.Reader, resultDef any) error {
+		records, err := csv.NewReader(body).ReadAll()
+		if err != nil {
+			return fmt.Errorf(`cannot decode CSV: %w`, err)
+		}
+		switch v := resultDef.(type) {
+		case *[][]string:
+			*v = records
+			return nil
+		case CSVUnmarshaler:
+			return v.UnmarshalCSV(records)
+		default:
+			return fmt.Errorf(`unsupported CSV result type "%T", expected *[][]string or CSVUnmarshaler`, resultDef)
+		}
+	})
+}
+
+// XMLCodec decodes XML by the encoding/xml package.
+func XMLCodec() Codec {
+	return CodecFunc(func(body io.Reader, resultDef any) error {
+		if err := xml.NewDecoder(body).Decode(resultDef); err != nil {
+			return fmt.Errorf(`cannot decode XML: %w`, err)
+		}
+		return nil
+	})
+}
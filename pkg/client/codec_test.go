@@ -0,0 +1,151 @@
+package client_test
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/jarcoal/httpmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	. "github.com/keboola/go-client/pkg/client"
+	"github.com/keboola/go-client/pkg/request"
+)
+
+type testCSV struct {
+	Header []string
+	Count  int
+}
+
+func (v *testCSV) UnmarshalCSV(records [][]string) error {
+	v.Header = records[0]
+	v.Count = len(records) - 1
+	return nil
+}
+
+type testXML struct {
+	Foo string `xml:"foo"`
+}
+
+func codecResponder(contentType, body string) httpmock.Responder {
+	return func(req *http.Request) (*http.Response, error) {
+		res := httpmock.NewStringResponse(http.StatusOK, body)
+		res.Header.Set("Content-Type", contentType)
+		return res, nil
+	}
+}
+
+func TestNDJSONCodec(t *testing.T) {
+	t.Parallel()
+
+	// Mocked response
+	transport := httpmock.NewMockTransport()
+	transport.RegisterResponder("GET", "https://example.com", codecResponder("application/x-ndjson", "{\"foo\":\"bar1\"}\n{\"foo\":\"bar2\"}\n"))
+	c := New().WithTransport(transport)
+	ctx := context.Background()
+
+	// Slice
+	var items []testStruct
+	_, _, err := request.NewHTTPRequest(c).WithGet("https://example.com").WithResult(&items).Send(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, []testStruct{{Foo: "bar1"}, {Foo: "bar2"}}, items)
+
+	// Callback
+	var values []string
+	callback := func(item testStruct) error {
+		values = append(values, item.Foo)
+		return nil
+	}
+	_, _, err = request.NewHTTPRequest(c).WithGet("https://example.com").WithResult(callback).Send(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"bar1", "bar2"}, values)
+
+	// Callback error
+	callback = func(item testStruct) error {
+		return errors.New("some error")
+	}
+	_, _, err = request.NewHTTPRequest(c).WithGet("https://example.com").WithResult(callback).Send(ctx)
+	assert.EqualError(t, err, `cannot process response body GET "https://example.com": some error`)
+}
+
+func TestCSVCodec(t *testing.T) {
+	t.Parallel()
+
+	// Mocked response
+	transport := httpmock.NewMockTransport()
+	transport.RegisterResponder("GET", "https://example.com", codecResponder("text/csv; charset=utf-8", "a,b\n1,2\n3,4\n"))
+	c := New().WithTransport(transport)
+	ctx := context.Background()
+
+	// Records
+	var records [][]string
+	_, _, err := request.NewHTTPRequest(c).WithGet("https://example.com").WithResult(&records).Send(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, [][]string{{"a", "b"}, {"1", "2"}, {"3", "4"}}, records)
+
+	// CSVUnmarshaler
+	out := &testCSV{}
+	_, _, err = request.NewHTTPRequest(c).WithGet("https://example.com").WithResult(out).Send(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, &testCSV{Header: []string{"a", "b"}, Count: 2}, out)
+}
+
+func TestXMLCodec(t *testing.T) {
+	t.Parallel()
+
+	// Mocked response
+	transport := httpmock.NewMockTransport()
+	transport.RegisterResponder("GET", "https://example.com", codecResponder("application/xml", "<root><foo>bar</foo></root>"))
+	c := New().WithTransport(transport)
+
+	out := &testXML{}
+	_, _, err := request.NewHTTPRequest(c).WithGet("https://example.com").WithResult(out).Send(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, &testXML{Foo: "bar"}, out)
+}
+
+func TestWithCodec(t *testing.T) {
+	t.Parallel()
+
+	// Mocked response
+	transport := httpmock.NewMockTransport()
+	transport.RegisterResponder("GET", "https://example.com", codecResponder("text/x-custom", "foo"))
+	c := New().WithTransport(transport)
+	ctx := context.Background()
+
+	// Content type without a codec is ignored
+	var out []string
+	_, _, err := request.NewHTTPRequest(c).WithGet("https://example.com").WithResult(&out).Send(ctx)
+	require.NoError(t, err)
+	assert.Empty(t, out)
+
+	// Result, which can be decoded only by a codec, cannot be decoded
+	var records [][]string
+	_, _, err = request.NewHTTPRequest(c).WithGet("https://example.com").WithResult(&records).Send(ctx)
+	if assert.Error(t, err) {
+		assert.Equal(t, `cannot process response body GET "https://example.com": cannot decode result "*[][]string": unsupported content type "text/x-custom", see Client.WithCodec and HTTPRequest.WithResponseMediaType`, err.Error())
+	}
+	assert.Empty(t, records)
+
+	// The media type is set by the request
+	_, _, err = request.NewHTTPRequest(c).WithGet("https://example.com").WithResponseMediaType(ContentTypeTextCSV).WithResult(&records).Send(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, [][]string{{"foo"}}, records)
+
+	// Custom codec
+	c = c.WithCodec("text/x-custom", CodecFunc(func(body io.Reader, resultDef any) error {
+		content, err := io.ReadAll(body)
+		if err != nil {
+			return err
+		}
+		*resultDef.(*[]string) = strings.Split(strings.ToUpper(string(content)), "")
+		return nil
+	}))
+	_, _, err = request.NewHTTPRequest(c).WithGet("https://example.com").WithResult(&out).Send(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"F", "O", "O"}, out)
+}
@@ -1,16 +1,14 @@
 package client
 
 import (
-	"regexp"
+	"github.com/keboola/go-client/pkg/request"
 )
 
 const (
 	ContentTypeApplicationJSON       = "application/json"
-	ContentTypeApplicationJSONRegexp = `^application/([a-zA-Z0-9\.\-]+\+)?json$`
+	ContentTypeApplicationJSONRegexp = request.ContentTypeApplicationJSONRegexp
 )
 
-var jsonContentTypeRegexp = regexp.MustCompile(ContentTypeApplicationJSONRegexp)
-
 func isJSONContentType(contentType string) bool {
-	return jsonContentTypeRegexp.MatchString(contentType)
+	return request.IsJSONContentType(contentType)
 }
@@ -1,26 +1,102 @@
+// Package decode decompresses response bodies according to the Content-Encoding header.
+//
+// The "gzip", "br", "zstd" and "deflate" encodings are supported by default, other decoders can be added by the Register function.
 package decode
 
 import (
+	"bufio"
+	"compress/flate"
 	"compress/gzip"
+	"compress/zlib"
 	"fmt"
 	"io"
 	"strings"
+	"sync"
 
 	"github.com/andybalholm/brotli"
+	"github.com/klauspost/compress/zstd"
 )
 
+// Decoder wraps the encoded body by a reader of the decoded content.
+// Closing of the returned reader must not close the encoded body, it is closed by the caller.
+type Decoder func(body io.Reader) (io.ReadCloser, error)
+
+//nolint:gochecknoglobals
+var (
+	lock     sync.RWMutex
+	decoders = map[string]Decoder{
+		"gzip":    decodeGzip,
+		"x-gzip":  decodeGzip,
+		"br":      decodeBrotli,
+		"zstd":    decodeZstd,
+		"deflate": decodeDeflate,
+	}
+)
+
+// Register adds the decoder of the content encoding, an existing decoder is replaced.
+func Register(contentEncoding string, decoder Decoder) {
+	lock.Lock()
+	defer lock.Unlock()
+	decoders[strings.ToLower(contentEncoding)] = decoder
+}
+
+// Decode returns a reader of the decoded body.
+//
+// The contentEncoding is a value of the Content-Encoding header, multiple encodings are separated by a comma.
+// The encodings are listed in the order in which they were applied, so they are decoded in the reverse order.
+// An unknown encoding results in an error.
 func Decode(body io.ReadCloser, contentEncoding string) (io.ReadCloser, error) {
-	contentEncoding = strings.ToLower(contentEncoding)
-	switch contentEncoding {
-	case "gzip":
-		if v, err := gzip.NewReader(body); err == nil {
-			return v, nil
-		} else {
-			return nil, fmt.Errorf("cannot decode gzip: %w", err)
+	encodings := strings.Split(contentEncoding, ",")
+	out := body
+	for i := len(encodings) - 1; i >= 0; i-- {
+		encoding := strings.ToLower(strings.TrimSpace(encodings[i]))
+		if encoding == "" || encoding == "identity" {
+			continue
 		}
-	case "br":
-		return io.NopCloser(brotli.NewReader(body)), nil
-	default:
-		return body, nil
+
+		lock.RLock()
+		decoder, found := decoders[encoding]
+		lock.RUnlock()
+		if !found {
+			return nil, fmt.Errorf(`unsupported content encoding "%s"`, encoding)
+		}
+
+		decoded, err := decoder(out)
+		if err != nil {
+			return nil, fmt.Errorf("cannot decode %s: %w", encoding, err)
+		}
+		out = decoded
+	}
+	return out, nil
+}
+
+func decodeGzip(body io.Reader) (io.ReadCloser, error) {
+	return gzip.NewReader(body)
+}
+
+func decodeBrotli(body io.Reader) (io.ReadCloser, error) {
+	return io.NopCloser(brotli.NewReader(body)), nil
+}
+
+func decodeZstd(body io.Reader) (io.ReadCloser, error) {
+	// Concurrency 1 - the decoding runs synchronously, without background goroutines
+	d, err := zstd.NewReader(body, zstd.WithDecoderConcurrency(1))
+	if err != nil {
+		return nil, err
 	}
+	return d.IOReadCloser(), nil
+}
+
+// decodeDeflate supports both, the zlib format defined by the HTTP specification, and the raw deflate sent by some servers.
+func decodeDeflate(body io.Reader) (io.ReadCloser, error) {
+	r := bufio.NewReader(body)
+	if header, err := r.Peek(2); err == nil && isZlibHeader(header) {
+		return zlib.NewReader(r)
+	}
+	return flate.NewReader(r), nil
+}
+
+// isZlibHeader checks the compression method and the checksum of the zlib header, see RFC 1950.
+func isZlibHeader(header []byte) bool {
+	return header[0]&0x0f == 8 && (uint16(header[0])<<8|uint16(header[1]))%31 == 0
 }
@@ -0,0 +1,119 @@
+package decode
+
+import (
+	"bytes"
+	"compress/flate"
+	"compress/gzip"
+	"compress/zlib"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/andybalholm/brotli"
+	"github.com/klauspost/compress/zstd"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDecode(t *testing.T) {
+	t.Parallel()
+
+	content := []byte(strings.Repeat("foo bar ", 100))
+	cases := []struct {
+		encoding string
+		body     []byte
+	}{
+		{encoding: "", body: content},
+		{encoding: "identity", body: content},
+		{encoding: "gzip", body: encodeGzip(t, content)},
+		{encoding: "GZIP", body: encodeGzip(t, content)},
+		{encoding: "br", body: encodeBrotli(t, content)},
+		{encoding: "zstd", body: encodeZstd(t, content)},
+		{encoding: "deflate", body: encodeZlib(t, content)},
+		{encoding: "deflate", body: encodeFlate(t, content)},
+		{encoding: "gzip, br", body: encodeBrotli(t, encodeGzip(t, content))},
+		{encoding: "zstd,gzip", body: encodeGzip(t, encodeZstd(t, content))},
+	}
+
+	for _, tc := range cases {
+		reader, err := Decode(io.NopCloser(bytes.NewReader(tc.body)), tc.encoding)
+		require.NoError(t, err, tc.encoding)
+		out, err := io.ReadAll(reader)
+		require.NoError(t, err, tc.encoding)
+		assert.Equal(t, string(content), string(out), tc.encoding)
+		assert.NoError(t, reader.Close(), tc.encoding)
+	}
+}
+
+func TestDecode_Unsupported(t *testing.T) {
+	t.Parallel()
+
+	_, err := Decode(io.NopCloser(strings.NewReader("foo")), "gzip, foo")
+	assert.EqualError(t, err, `unsupported content encoding "foo"`)
+}
+
+func TestRegister(t *testing.T) {
+	t.Parallel()
+
+	Register("x-upper", func(body io.Reader) (io.ReadCloser, error) {
+		content, err := io.ReadAll(body)
+		if err != nil {
+			return nil, err
+		}
+		return io.NopCloser(strings.NewReader(strings.ToLower(string(content)))), nil
+	})
+
+	reader, err := Decode(io.NopCloser(strings.NewReader("FOO")), "X-Upper")
+	require.NoError(t, err)
+	out, err := io.ReadAll(reader)
+	require.NoError(t, err)
+	assert.Equal(t, "foo", string(out))
+}
+
+func encodeGzip(t *testing.T, content []byte) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	w := gzip.NewWriter(&buf)
+	_, err := w.Write(content)
+	require.NoError(t, err)
+	require.NoError(t, w.Close())
+	return buf.Bytes()
+}
+
+func encodeBrotli(t *testing.T, content []byte) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	w := brotli.NewWriter(&buf)
+	_, err := w.Write(content)
+	require.NoError(t, err)
+	require.NoError(t, w.Close())
+	return buf.Bytes()
+}
+
+func encodeZstd(t *testing.T, content []byte) []byte {
+	t.Helper()
+	w, err := zstd.NewWriter(nil)
+	require.NoError(t, err)
+	return w.EncodeAll(content, nil)
+}
+
+func encodeZlib(t *testing.T, content []byte) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	w := zlib.NewWriter(&buf)
+	_, err := w.Write(content)
+	require.NoError(t, err)
+	require.NoError(t, w.Close())
+	return buf.Bytes()
+}
+
+func encodeFlate(t *testing.T, content []byte) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	w, err := flate.NewWriter(&buf, flate.DefaultCompression)
+	require.NoError(t, err)
+	_, err = w.Write(content)
+	require.NoError(t, err)
+	require.NoError(t, w.Close())
+	return buf.Bytes()
+}
@@ -0,0 +1,66 @@
+// Package encode compresses request bodies, it is a counterpart of the decode package.
+package encode
+
+import (
+	"compress/gzip"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/andybalholm/brotli"
+)
+
+// IsSupported returns true, if the content encoding is supported by the Encode function.
+func IsSupported(contentEncoding string) bool {
+	switch strings.ToLower(contentEncoding) {
+	case "gzip", "br":
+		return true
+	default:
+		return false
+	}
+}
+
+// Encode returns a stream of the compressed body, the body is compressed on the fly, as it is read.
+func Encode(body io.ReadCloser, contentEncoding string) (io.ReadCloser, error) {
+	var newWriter func(w io.Writer) io.WriteCloser
+	switch strings.ToLower(contentEncoding) {
+	case "gzip":
+		newWriter = func(w io.Writer) io.WriteCloser { return gzip.NewWriter(w) }
+	case "br":
+		newWriter = func(w io.Writer) io.WriteCloser { return brotli.NewWriter(w) }
+	default:
+		return nil, fmt.Errorf(`unsupported content encoding "%s"`, contentEncoding)
+	}
+
+	pr, pw := io.Pipe()
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		defer body.Close()
+		w := newWriter(pw)
+		if _, err := io.Copy(w, body); err != nil {
+			_ = pw.CloseWithError(fmt.Errorf("cannot encode %s: %w", contentEncoding, err))
+			return
+		}
+		if err := w.Close(); err != nil {
+			_ = pw.CloseWithError(fmt.Errorf("cannot encode %s: %w", contentEncoding, err))
+			return
+		}
+		_ = pw.Close()
+	}()
+	return &encoder{PipeReader: pr, done: done}, nil
+}
+
+// encoder is a stream of the compressed body.
+type encoder struct {
+	*io.PipeReader
+	done chan struct{}
+}
+
+// Close stops the compression and waits until the original body is closed,
+// so the body can be safely rewound and read again, for example by a retry.
+func (e *encoder) Close() error {
+	err := e.PipeReader.Close()
+	<-e.done
+	return err
+}
@@ -0,0 +1,41 @@
+package encode
+
+import (
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/keboola/go-client/pkg/client/decode"
+)
+
+func TestEncode(t *testing.T) {
+	t.Parallel()
+
+	content := strings.Repeat("foo bar ", 1000)
+	for _, encoding := range []string{"gzip", "br"} {
+		assert.True(t, IsSupported(encoding))
+
+		encoded, err := Encode(io.NopCloser(strings.NewReader(content)), encoding)
+		require.NoError(t, err, encoding)
+		compressed, err := io.ReadAll(encoded)
+		require.NoError(t, err, encoding)
+		assert.Less(t, len(compressed), len(content), encoding)
+
+		decoded, err := decode.Decode(io.NopCloser(strings.NewReader(string(compressed))), encoding)
+		require.NoError(t, err, encoding)
+		out, err := io.ReadAll(decoded)
+		require.NoError(t, err, encoding)
+		assert.Equal(t, content, string(out), encoding)
+	}
+}
+
+func TestEncode_Unsupported(t *testing.T) {
+	t.Parallel()
+
+	assert.False(t, IsSupported("foo"))
+	_, err := Encode(io.NopCloser(strings.NewReader("")), "foo")
+	assert.EqualError(t, err, `unsupported content encoding "foo"`)
+}
@@ -1,11 +1,15 @@
 package client
 
 import (
+	"errors"
+	"net"
 	"net/http"
+	"strconv"
 	"strings"
 	"time"
 
-	"github.com/cenkalti/backoff/v4"
+	"github.com/keboola/go-client/pkg/client/trace"
+	"github.com/keboola/go-client/pkg/request"
 )
 
 // RetriesCount - default retries count.
@@ -20,17 +24,14 @@ const RetryWaitTimeStart = 100 * time.Millisecond
 // RetryWaitTimeMax - default maximum retry interval.
 const RetryWaitTimeMax = 3 * time.Second
 
-// RetryConfig configures Client retries.
-type RetryConfig struct {
-	Condition           RetryCondition
-	Count               int
-	TotalRequestTimeout time.Duration
-	WaitTimeStart       time.Duration
-	WaitTimeMax         time.Duration
-}
+// minRateLimitResetTimestamp - a larger X-RateLimit-Reset value is an Unix timestamp, not a number of seconds.
+const minRateLimitResetTimestamp = 1_000_000_000
+
+// RetryConfig configures Client retries, see request.RetryConfig.
+type RetryConfig = request.RetryConfig
 
-// RetryCondition defines which responses should retry.
-type RetryCondition func(*http.Response, error) bool
+// RetryCondition defines which responses should retry, see request.RetryCondition.
+type RetryCondition = request.RetryCondition
 
 // TestingRetry - fast retry for use in tests.
 func TestingRetry() RetryConfig {
@@ -43,11 +44,12 @@ func TestingRetry() RetryConfig {
 // DefaultRetry returns a default RetryConfig.
 func DefaultRetry() RetryConfig {
 	return RetryConfig{
-		TotalRequestTimeout: RequestTimeout,
-		Count:               RetriesCount,
-		WaitTimeStart:       RetryWaitTimeStart,
-		WaitTimeMax:         RetryWaitTimeMax,
-		Condition:           DefaultRetryCondition(),
+		TotalRequestTimeout:    RequestTimeout,
+		Count:                  RetriesCount,
+		WaitTimeStart:          RetryWaitTimeStart,
+		WaitTimeMax:            RetryWaitTimeMax,
+		Condition:              DefaultRetryCondition(),
+		NonIdempotentCondition: ConnectionRetryCondition(),
 	}
 }
 
@@ -84,14 +86,94 @@ func DefaultRetryCondition() RetryCondition {
 	}
 }
 
-// NewBackoff returns an exponential backoff for HTTP retries.
-func (c RetryConfig) NewBackoff() backoff.BackOff {
-	b := backoff.NewExponentialBackOff()
-	b.InitialInterval = c.WaitTimeStart
-	b.MaxInterval = c.WaitTimeMax
-	b.MaxElapsedTime = c.TotalRequestTimeout
-	b.Multiplier = 2
-	b.RandomizationFactor = 0
-	b.Reset()
-	return b
+// NeverRetryCondition never retries, for example, to disable retries of non-idempotent requests, see RetryConfig.NonIdempotentCondition.
+func NeverRetryCondition() RetryCondition {
+	return func(*http.Response, error) bool {
+		return false
+	}
+}
+
+// ConnectionRetryCondition retries only if the request has not been processed by the server:
+// on connection-level failures, when the request has not been sent,
+// on the 429 Too Many Requests response and on the 503 Service Unavailable response with the Retry-After header.
+// It is used for non-idempotent requests by default, also if the RetryConfig.NonIdempotentCondition is nil.
+func ConnectionRetryCondition() RetryCondition {
+	return func(response *http.Response, err error) bool {
+		// The request has been rejected by the server
+		if response != nil {
+			switch {
+			case response.StatusCode == http.StatusTooManyRequests:
+				return true
+			case response.StatusCode == http.StatusServiceUnavailable && response.Header.Get("Retry-After") != "":
+				return true
+			}
+		}
+
+		if err == nil || (response != nil && response.StatusCode != 0) {
+			return false
+		}
+
+		// DNS errors - except hostname not found
+		var dnsErr *net.DNSError
+		if errors.As(err, &dnsErr) {
+			return !dnsErr.IsNotFound && (dnsErr.IsTemporary || dnsErr.IsTimeout)
+		}
+
+		// Connection has not been established
+		var opErr *net.OpError
+		return errors.As(err, &opErr) && opErr.Op == "dial"
+	}
+}
+
+// retryDelayFromHeader returns the retry delay requested by the server, if any.
+// The Retry-After header is used on 429 and 503 responses, both the seconds and the HTTP-date forms are supported.
+// The X-RateLimit-Reset header is used if the rate limit has been exceeded, see X-RateLimit-Remaining.
+// The reset value can be the number of seconds, an Unix timestamp or a HTTP-date.
+func retryDelayFromHeader(res *http.Response, now time.Time) (time.Duration, trace.RetryDelayReason, bool) {
+	if res == nil {
+		return 0, "", false
+	}
+
+	// Retry-After
+	if res.StatusCode == http.StatusTooManyRequests || res.StatusCode == http.StatusServiceUnavailable {
+		if delay, ok := parseRetryDelay(res.Header.Get("Retry-After"), now, false); ok {
+			return delay, trace.RetryDelayRetryAfter, true
+		}
+	}
+
+	// X-RateLimit-*
+	if res.StatusCode == http.StatusTooManyRequests || strings.TrimSpace(res.Header.Get("X-RateLimit-Remaining")) == "0" {
+		if delay, ok := parseRetryDelay(res.Header.Get("X-RateLimit-Reset"), now, true); ok {
+			return delay, trace.RetryDelayRateLimit, true
+		}
+	}
+
+	return 0, "", false
+}
+
+// parseRetryDelay parses the number of seconds or a HTTP-date.
+// If allowTimestamp is true, a large number is considered to be an Unix timestamp.
+func parseRetryDelay(value string, now time.Time, allowTimestamp bool) (time.Duration, bool) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return 0, false
+	}
+
+	// Seconds or Unix timestamp
+	if seconds, err := strconv.ParseInt(value, 10, 64); err == nil {
+		if seconds < 0 {
+			return 0, false
+		}
+		if allowTimestamp && seconds >= minRateLimitResetTimestamp {
+			return max(time.Unix(seconds, 0).Sub(now), 0), true
+		}
+		return time.Duration(seconds) * time.Second, true
+	}
+
+	// HTTP-date
+	if date, err := http.ParseTime(value); err == nil {
+		return max(date.Sub(now), 0), true
+	}
+
+	return 0, false
 }
@@ -1,9 +1,14 @@
 package client_test
 
 import (
+	"bytes"
 	"context"
+	"errors"
 	"io"
+	"net"
 	"net/http"
+	"strconv"
+	"strings"
 	"testing"
 	"time"
 
@@ -12,7 +17,7 @@ import (
 
 	. "github.com/keboola/go-client/pkg/client"
 	. "github.com/keboola/go-client/pkg/client/trace"
-	. "github.com/keboola/go-client/pkg/request"
+	"github.com/keboola/go-client/pkg/request"
 )
 
 func TestRetryCount(t *testing.T) {
@@ -36,18 +41,18 @@ func TestRetryCount(t *testing.T) {
 			WaitTimeStart: 1 * time.Microsecond,
 			WaitTimeMax:   20 * time.Microsecond,
 		}).
-		AndTrace(func(ctx context.Context, reqDef HTTPRequest) (context.Context, *ClientTrace) {
+		AndTrace(func(ctx context.Context, reqDef request.HTTPRequest) (context.Context, *ClientTrace) {
 			return ctx, &ClientTrace{
-				RetryDelay: func(_ int, delay time.Duration) {
+				RetryDelay: func(_ int, delay time.Duration, _ RetryDelayReason) {
 					delays = append(delays, delay)
 				},
 			}
 		})
 
 	// Get
-	_, _, err := NewHTTPRequest(c).
+	_, _, err := request.NewHTTPRequest(c).
 		WithGet("https://example.com").
-		WithOnComplete(func(ctx context.Context, response HTTPResponse, err error) error {
+		WithOnComplete(func(ctx context.Context, response request.HTTPResponse, err error) error {
 			// Check context
 			attempt, found := ContextRetryAttempt(response.RawRequest().Context())
 			assert.True(t, found)
@@ -81,7 +86,7 @@ func TestRetryBodyRewind(t *testing.T) {
 
 	// Mocked response
 	transport := httpmock.NewMockTransport()
-	transport.RegisterResponder("POST", `https://example.com`, func(req *http.Request) (*http.Response, error) {
+	transport.RegisterResponder("PUT", `https://example.com`, func(req *http.Request) (*http.Response, error) {
 		requestBody, err := io.ReadAll(req.Body)
 		assert.NoError(t, err)
 		// Each retry attempt must send same body
@@ -95,9 +100,45 @@ func TestRetryBodyRewind(t *testing.T) {
 		WithTransport(transport).
 		WithRetry(TestingRetry())
 
-	// Post
+	// Put
 	jsonBody := map[string]any{"foo": "bar"}
-	_, _, err := NewHTTPRequest(c).WithPost("https://example.com").WithJSONBody(jsonBody).Send(ctx)
+	_, _, err := request.NewHTTPRequest(c).WithPut("https://example.com").WithJSONBody(jsonBody).Send(ctx)
+	assert.Error(t, err)
+	assert.Equal(t, `request PUT "https://example.com" failed: 502 Bad Gateway`, err.Error())
+
+	// Check number of requests
+	assert.Equal(t, 1+5, transport.GetCallCountInfo()["PUT https://example.com"])
+}
+
+func TestRetryMultipartBodyRewind(t *testing.T) {
+	t.Parallel()
+
+	// Mocked response
+	transport := httpmock.NewMockTransport()
+	transport.RegisterResponder("POST", `https://example.com`, func(req *http.Request) (*http.Response, error) {
+		// Each retry attempt must send same body
+		assert.NoError(t, req.ParseMultipartForm(1024))
+		assert.Equal(t, "bar", req.FormValue("foo"))
+		file, header, err := req.FormFile("data")
+		if assert.NoError(t, err) {
+			content, err := io.ReadAll(file)
+			assert.NoError(t, err)
+			assert.Equal(t, "file content", string(content))
+			assert.Equal(t, "data.csv", header.Filename)
+			assert.Equal(t, "text/csv", header.Header.Get("Content-Type"))
+		}
+		return httpmock.NewStringResponse(502, "retry!"), nil
+	})
+
+	// Create client, enable retry of the non-idempotent request
+	ctx := context.Background()
+	retry := TestingRetry()
+	retry.NonIdempotentCondition = retry.Condition
+	c := New().WithTransport(transport).WithRetry(retry)
+
+	// Post
+	file := request.MultipartFile{FieldName: "data", FileName: "data.csv", ContentType: "text/csv", Reader: strings.NewReader("file content")}
+	_, _, err := request.NewHTTPRequest(c).WithPost("https://example.com").WithMultipartBody(map[string]string{"foo": "bar"}, file).Send(ctx)
 	assert.Error(t, err)
 	assert.Equal(t, `request POST "https://example.com" failed: 502 Bad Gateway`, err.Error())
 
@@ -105,6 +146,52 @@ func TestRetryBodyRewind(t *testing.T) {
 	assert.Equal(t, 1+5, transport.GetCallCountInfo()["POST https://example.com"])
 }
 
+func TestRetryMultipartBodyRewind_PartiallyRead(t *testing.T) {
+	t.Parallel()
+
+	// Mocked response, the body is read only partially, so the writer of the previous attempt is still running, run with -race
+	transport := httpmock.NewMockTransport()
+	transport.RegisterResponder("POST", `https://example.com`, func(req *http.Request) (*http.Response, error) {
+		_, err := io.ReadFull(req.Body, make([]byte, 1000))
+		assert.NoError(t, err)
+		assert.NoError(t, req.Body.Close())
+		return httpmock.NewStringResponse(502, "retry!"), nil
+	})
+
+	// Create client, enable retry of the non-idempotent request
+	retry := TestingRetry()
+	retry.NonIdempotentCondition = retry.Condition
+	c := New().WithTransport(transport).WithRetry(retry)
+
+	// Post
+	file := request.MultipartFile{FieldName: "data", Reader: bytes.NewReader(bytes.Repeat([]byte("file content"), 100_000))}
+	_, _, err := request.NewHTTPRequest(c).WithPost("https://example.com").WithMultipartBody(nil, file).Send(context.Background())
+	assert.Error(t, err)
+	assert.Equal(t, 1+5, transport.GetCallCountInfo()["POST https://example.com"])
+}
+
+func TestRetryMultipartBodyNotSeekable(t *testing.T) {
+	t.Parallel()
+
+	// Mocked response
+	transport := httpmock.NewMockTransport()
+	transport.RegisterResponder("POST", `https://example.com`, httpmock.NewStringResponder(502, "retry!"))
+
+	// Create client, enable retry of the non-idempotent request
+	ctx := context.Background()
+	retry := TestingRetry()
+	retry.NonIdempotentCondition = retry.Condition
+	c := New().WithTransport(transport).WithRetry(retry)
+
+	// The body cannot be rewound, the request is sent only once
+	file := request.MultipartFile{FieldName: "data", Reader: io.MultiReader(strings.NewReader("file content"))}
+	_, _, err := request.NewHTTPRequest(c).WithPost("https://example.com").WithMultipartBody(nil, file).Send(ctx)
+	if assert.Error(t, err) {
+		assert.Contains(t, err.Error(), `cannot rewind multipart file "data": the reader is not an io.Seeker`)
+	}
+	assert.Equal(t, 1, transport.GetCallCountInfo()["POST https://example.com"])
+}
+
 func TestDoNotRetry(t *testing.T) {
 	t.Parallel()
 
@@ -125,16 +212,16 @@ func TestDoNotRetry(t *testing.T) {
 			WaitTimeStart: 1 * time.Microsecond,
 			WaitTimeMax:   20 * time.Microsecond,
 		}).
-		AndTrace(func(ctx context.Context, reqDef HTTPRequest) (context.Context, *ClientTrace) {
+		AndTrace(func(ctx context.Context, reqDef request.HTTPRequest) (context.Context, *ClientTrace) {
 			return ctx, &ClientTrace{
-				RetryDelay: func(_ int, delay time.Duration) {
+				RetryDelay: func(_ int, delay time.Duration, _ RetryDelayReason) {
 					delays = append(delays, delay)
 				},
 			}
 		})
 
 	// Get
-	_, _, err := NewHTTPRequest(c).WithGet("https://example.com").Send(ctx)
+	_, _, err := request.NewHTTPRequest(c).WithGet("https://example.com").Send(ctx)
 	assert.Error(t, err)
 	assert.Equal(t, `request GET "https://example.com" failed: 403 Forbidden`, err.Error())
 
@@ -144,3 +231,409 @@ func TestDoNotRetry(t *testing.T) {
 	// Check delays
 	assert.Empty(t, delays)
 }
+
+func TestRequestRetryOverride(t *testing.T) {
+	t.Parallel()
+
+	// Mocked response
+	transport := httpmock.NewMockTransport()
+	transport.RegisterResponder("GET", "https://example.com", httpmock.NewStringResponder(504, "test"))
+
+	// Setup
+	var delays []time.Duration
+
+	// Create client, retries are disabled by default
+	ctx := context.Background()
+	c := New().
+		WithTransport(transport).
+		WithRetry(RetryConfig{Count: 0}).
+		AndTrace(func(ctx context.Context, reqDef request.HTTPRequest) (context.Context, *ClientTrace) {
+			return ctx, &ClientTrace{
+				RetryDelay: func(_ int, delay time.Duration, _ RetryDelayReason) {
+					delays = append(delays, delay)
+				},
+			}
+		})
+
+	// Get, retries are enabled for the request
+	_, _, err := request.NewHTTPRequest(c).
+		WithGet("https://example.com").
+		WithRetry(RetryConfig{
+			Condition:     DefaultRetryCondition(),
+			Count:         3,
+			WaitTimeStart: 1 * time.Microsecond,
+			WaitTimeMax:   20 * time.Microsecond,
+		}).
+		Send(ctx)
+	assert.Error(t, err)
+	assert.Equal(t, `request GET "https://example.com" failed: 504 Gateway Timeout`, err.Error())
+
+	// Check number of requests
+	assert.Equal(t, 1+3, transport.GetCallCountInfo()["GET https://example.com"])
+
+	// Check delays
+	assert.Equal(t, []time.Duration{
+		1 * time.Microsecond,
+		2 * time.Microsecond,
+		4 * time.Microsecond,
+	}, delays)
+}
+
+func TestRetryNonIdempotent(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		name        string
+		request     func(r request.HTTPRequest) request.HTTPRequest
+		err         error
+		response    *http.Response
+		expectedErr string
+		retried     bool
+	}{
+		{
+			name:        "POST: HTTP error",
+			request:     func(r request.HTTPRequest) request.HTTPRequest { return r },
+			expectedErr: `request POST "https://example.com" failed: 502 Bad Gateway`,
+			retried:     false,
+		},
+		{
+			name:        "POST: connection error",
+			request:     func(r request.HTTPRequest) request.HTTPRequest { return r },
+			err:         &net.OpError{Op: "dial", Net: "tcp", Err: errors.New("connection refused")},
+			expectedErr: `request POST "https://example.com" failed: dial tcp: connection refused`,
+			retried:     true,
+		},
+		{
+			name:        "POST: read error",
+			request:     func(r request.HTTPRequest) request.HTTPRequest { return r },
+			err:         &net.OpError{Op: "read", Net: "tcp", Err: errors.New("connection reset by peer")},
+			expectedErr: `request POST "https://example.com" failed: read tcp: connection reset by peer`,
+			retried:     false,
+		},
+		{
+			name:        "POST: too many requests",
+			request:     func(r request.HTTPRequest) request.HTTPRequest { return r },
+			response:    &http.Response{StatusCode: http.StatusTooManyRequests, Status: "429 Too Many Requests"},
+			expectedErr: `request POST "https://example.com" failed: 429 Too Many Requests`,
+			retried:     true,
+		},
+		{
+			name:        "POST: service unavailable with Retry-After",
+			request:     func(r request.HTTPRequest) request.HTTPRequest { return r },
+			response:    &http.Response{StatusCode: http.StatusServiceUnavailable, Status: "503 Service Unavailable", Header: http.Header{"Retry-After": []string{"0"}}},
+			expectedErr: `request POST "https://example.com" failed: 503 Service Unavailable`,
+			retried:     true,
+		},
+		{
+			name:        "POST: service unavailable",
+			request:     func(r request.HTTPRequest) request.HTTPRequest { return r },
+			response:    &http.Response{StatusCode: http.StatusServiceUnavailable, Status: "503 Service Unavailable"},
+			expectedErr: `request POST "https://example.com" failed: 503 Service Unavailable`,
+			retried:     false,
+		},
+		{
+			name: "POST: idempotency key",
+			request: func(r request.HTTPRequest) request.HTTPRequest {
+				return r.AndHeader(request.IdempotencyKeyHeader, "my-key")
+			},
+			expectedErr: `request POST "https://example.com" failed: 502 Bad Gateway`,
+			retried:     true,
+		},
+		{
+			name: "POST: explicitly idempotent",
+			request: func(r request.HTTPRequest) request.HTTPRequest {
+				return r.WithIdempotent(true)
+			},
+			expectedErr: `request POST "https://example.com" failed: 502 Bad Gateway`,
+			retried:     true,
+		},
+		{
+			name: "PUT: explicitly non-idempotent",
+			request: func(r request.HTTPRequest) request.HTTPRequest {
+				return r.WithMethod(http.MethodPut).WithIdempotent(false)
+			},
+			expectedErr: `request PUT "https://example.com" failed: 502 Bad Gateway`,
+			retried:     false,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			// Mocked response
+			transport := httpmock.NewMockTransport()
+			var responder httpmock.Responder
+			switch {
+			case tc.err != nil:
+				responder = httpmock.NewErrorResponder(tc.err)
+			case tc.response != nil:
+				responder = httpmock.ResponderFromResponse(tc.response)
+			default:
+				responder = httpmock.NewStringResponder(502, "retry!")
+			}
+			transport.RegisterResponder("POST", `https://example.com`, responder)
+			transport.RegisterResponder("PUT", `https://example.com`, responder)
+
+			// Create client
+			var attempts []int
+			ctx := context.Background()
+			c := New().
+				WithTransport(transport).
+				WithRetry(TestingRetry()).
+				AndTrace(func(ctx context.Context, reqDef request.HTTPRequest) (context.Context, *ClientTrace) {
+					return ctx, &ClientTrace{
+						MutationRetry: func(_ *http.Request, attempt int, _ *http.Response, _ error) {
+							attempts = append(attempts, attempt)
+						},
+					}
+				})
+
+			// Send
+			_, _, err := tc.request(request.NewHTTPRequest(c).WithPost("https://example.com")).Send(ctx)
+			if assert.Error(t, err) {
+				assert.Equal(t, tc.expectedErr, err.Error())
+			}
+
+			// Check number of requests and reported retries
+			if tc.retried {
+				assert.Equal(t, 1+5, transport.GetTotalCallCount())
+				assert.Equal(t, []int{1, 2, 3, 4, 5}, attempts)
+			} else {
+				assert.Equal(t, 1, transport.GetTotalCallCount())
+				assert.Empty(t, attempts)
+			}
+		})
+	}
+}
+
+func TestRetryNonIdempotent_Condition(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		name      string
+		condition RetryCondition
+		retried   bool
+	}{
+		{name: "nil: connection condition", condition: nil, retried: true},
+		{name: "never", condition: NeverRetryCondition(), retried: false},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			// Mocked connection error
+			transport := httpmock.NewMockTransport()
+			transport.RegisterResponder("POST", `https://example.com`, httpmock.NewErrorResponder(&net.OpError{Op: "dial", Net: "tcp", Err: errors.New("connection refused")}))
+
+			// Create client
+			retry := TestingRetry()
+			retry.NonIdempotentCondition = tc.condition
+			c := New().WithTransport(transport).WithRetry(retry)
+
+			// Send
+			_, _, err := request.NewHTTPRequest(c).WithPost("https://example.com").Send(context.Background())
+			assert.Error(t, err)
+
+			// Check number of requests
+			if tc.retried {
+				assert.Equal(t, 1+5, transport.GetTotalCallCount())
+			} else {
+				assert.Equal(t, 1, transport.GetTotalCallCount())
+			}
+		})
+	}
+}
+
+func TestRetryNonIdempotent_BackoffStop(t *testing.T) {
+	t.Parallel()
+
+	// Mocked connection error
+	transport := httpmock.NewMockTransport()
+	transport.RegisterResponder("POST", `https://example.com`, httpmock.NewErrorResponder(&net.OpError{Op: "dial", Net: "tcp", Err: errors.New("connection refused")}))
+
+	// Create client, the backoff stops before the first retry
+	var attempts []int
+	retry := TestingRetry()
+	retry.TotalRequestTimeout = time.Nanosecond
+	c := New().
+		WithTransport(transport).
+		WithRetry(retry).
+		AndTrace(func(ctx context.Context, reqDef request.HTTPRequest) (context.Context, *ClientTrace) {
+			return ctx, &ClientTrace{
+				MutationRetry: func(_ *http.Request, attempt int, _ *http.Response, _ error) {
+					attempts = append(attempts, attempt)
+				},
+			}
+		})
+
+	// The request is not retried, so no retry is reported
+	_, _, err := request.NewHTTPRequest(c).WithPost("https://example.com").Send(context.Background())
+	assert.Error(t, err)
+	assert.Equal(t, 1, transport.GetTotalCallCount())
+	assert.Empty(t, attempts)
+}
+
+func TestRetryDelayHeaders(t *testing.T) {
+	t.Parallel()
+
+	now := time.Now()
+	cases := []struct {
+		name           string
+		status         int
+		header         map[string]string
+		expectedDelay  time.Duration
+		expectedReason RetryDelayReason
+	}{
+		{
+			name:           "no header",
+			status:         http.StatusTooManyRequests,
+			expectedDelay:  1 * time.Millisecond,
+			expectedReason: RetryDelayBackoff,
+		},
+		{
+			name:           "Retry-After: seconds",
+			status:         http.StatusTooManyRequests,
+			header:         map[string]string{"Retry-After": "0"},
+			expectedDelay:  0,
+			expectedReason: RetryDelayRetryAfter,
+		},
+		{
+			name:           "Retry-After: seconds, capped by WaitTimeMax",
+			status:         http.StatusServiceUnavailable,
+			header:         map[string]string{"Retry-After": "120"},
+			expectedDelay:  5 * time.Millisecond,
+			expectedReason: RetryDelayRetryAfter,
+		},
+		{
+			name:           "Retry-After: HTTP-date, capped by WaitTimeMax",
+			status:         http.StatusTooManyRequests,
+			header:         map[string]string{"Retry-After": now.Add(time.Hour).UTC().Format(http.TimeFormat)},
+			expectedDelay:  5 * time.Millisecond,
+			expectedReason: RetryDelayRetryAfter,
+		},
+		{
+			name:           "Retry-After: HTTP-date in the past",
+			status:         http.StatusServiceUnavailable,
+			header:         map[string]string{"Retry-After": now.Add(-time.Hour).UTC().Format(http.TimeFormat)},
+			expectedDelay:  0,
+			expectedReason: RetryDelayRetryAfter,
+		},
+		{
+			name:           "Retry-After: invalid",
+			status:         http.StatusTooManyRequests,
+			header:         map[string]string{"Retry-After": "foo"},
+			expectedDelay:  1 * time.Millisecond,
+			expectedReason: RetryDelayBackoff,
+		},
+		{
+			name:           "Retry-After: ignored status code",
+			status:         http.StatusBadGateway,
+			header:         map[string]string{"Retry-After": "0"},
+			expectedDelay:  1 * time.Millisecond,
+			expectedReason: RetryDelayBackoff,
+		},
+		{
+			name:           "X-RateLimit-Reset: seconds",
+			status:         http.StatusTooManyRequests,
+			header:         map[string]string{"X-RateLimit-Reset": "0"},
+			expectedDelay:  0,
+			expectedReason: RetryDelayRateLimit,
+		},
+		{
+			name:           "X-RateLimit-Reset: timestamp, capped by WaitTimeMax",
+			status:         http.StatusServiceUnavailable,
+			header:         map[string]string{"X-RateLimit-Remaining": "0", "X-RateLimit-Reset": strconv.FormatInt(now.Add(time.Minute).Unix(), 10)},
+			expectedDelay:  5 * time.Millisecond,
+			expectedReason: RetryDelayRateLimit,
+		},
+		{
+			name:           "X-RateLimit-Reset: remaining requests",
+			status:         http.StatusServiceUnavailable,
+			header:         map[string]string{"X-RateLimit-Remaining": "10", "X-RateLimit-Reset": "0"},
+			expectedDelay:  1 * time.Millisecond,
+			expectedReason: RetryDelayBackoff,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			// Mocked response
+			transport := httpmock.NewMockTransport()
+			responder := httpmock.NewStringResponder(tc.status, "retry!")
+			for k, v := range tc.header {
+				responder = responder.HeaderSet(http.Header{k: []string{v}})
+			}
+			transport.RegisterResponder("GET", `https://example.com`, responder)
+
+			// Create client
+			var delays []time.Duration
+			var reasons []RetryDelayReason
+			ctx := context.Background()
+			c := New().
+				WithTransport(transport).
+				WithRetry(RetryConfig{
+					Condition:           DefaultRetryCondition(),
+					Count:               1,
+					TotalRequestTimeout: time.Minute,
+					WaitTimeStart:       1 * time.Millisecond,
+					WaitTimeMax:         5 * time.Millisecond,
+				}).
+				AndTrace(func(ctx context.Context, reqDef request.HTTPRequest) (context.Context, *ClientTrace) {
+					return ctx, &ClientTrace{
+						RetryDelay: func(_ int, delay time.Duration, reason RetryDelayReason) {
+							delays = append(delays, delay)
+							reasons = append(reasons, reason)
+						},
+					}
+				})
+
+			// Send
+			_, _, err := request.NewHTTPRequest(c).WithGet("https://example.com").Send(ctx)
+			assert.Error(t, err)
+
+			// Check delay and reason
+			assert.Equal(t, 2, transport.GetTotalCallCount())
+			assert.Equal(t, []time.Duration{tc.expectedDelay}, delays)
+			assert.Equal(t, []RetryDelayReason{tc.expectedReason}, reasons)
+		})
+	}
+}
+
+func TestRetryDelayHeaders_TotalTimeout(t *testing.T) {
+	t.Parallel()
+
+	// Mocked response
+	transport := httpmock.NewMockTransport()
+	transport.RegisterResponder("GET", `https://example.com`, httpmock.NewStringResponder(http.StatusTooManyRequests, "retry!").HeaderSet(http.Header{"Retry-After": []string{"3600"}}))
+
+	// Create client
+	var delays []time.Duration
+	ctx := context.Background()
+	c := New().
+		WithTransport(transport).
+		WithRetry(RetryConfig{
+			Condition:           DefaultRetryCondition(),
+			Count:               1,
+			TotalRequestTimeout: 50 * time.Millisecond,
+			WaitTimeStart:       1 * time.Millisecond,
+			WaitTimeMax:         time.Hour,
+		}).
+		AndTrace(func(ctx context.Context, reqDef request.HTTPRequest) (context.Context, *ClientTrace) {
+			return ctx, &ClientTrace{
+				RetryDelay: func(_ int, delay time.Duration, _ RetryDelayReason) {
+					delays = append(delays, delay)
+				},
+			}
+		})
+
+	// Send, the delay is capped by the remaining timeout
+	_, _, err := request.NewHTTPRequest(c).WithGet("https://example.com").Send(ctx)
+	assert.Error(t, err)
+	if assert.Len(t, delays, 1) {
+		assert.LessOrEqual(t, delays[0], 50*time.Millisecond)
+	}
+}
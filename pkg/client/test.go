@@ -14,12 +14,16 @@ var testTransport = DefaultTransport() //nolint:gochecknoglobals
 //
 // If the TEST_HTTP_CLIENT_VERBOSE environment variable is set to "true",
 // then all HTTP requests and responses are dumped to stdout.
+// If it is set to "failed", then only failed requests are dumped, see trace.DumpBuffer.
 //
 // Output may contain unmasked tokens, do not use it in production!
 func NewTestClient() Client {
 	c := New().WithTransport(testTransport)
-	if os.Getenv("TEST_HTTP_CLIENT_VERBOSE") == "true" { //nolint:forbidigo
+	switch os.Getenv("TEST_HTTP_CLIENT_VERBOSE") { //nolint:forbidigo
+	case "true":
 		c = c.AndTrace(trace.DumpTracer(os.Stdout))
+	case "failed":
+		c = c.AndTrace(trace.NewDumpBuffer(os.Stdout, trace.DumpFailed(), 0).Factory())
 	}
 	return c
 }
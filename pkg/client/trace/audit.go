@@ -0,0 +1,170 @@
+package trace
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/keboola/go-client/pkg/request"
+)
+
+// AuditRecord is one line of the AuditLog.
+type AuditRecord struct {
+	Time time.Time `json:"time"`
+	// DefinedIn is name of the function, where the APIRequest was defined, see request.ContextDefinedIn.
+	DefinedIn string `json:"definedIn,omitempty"`
+	Method    string `json:"method"`
+	// URL is the resolved URL, sensitive path and query parameter values are masked.
+	URL string `json:"url"`
+	// TokenID is ID of the token, the token itself is never recorded, see WithAuditTokenID.
+	TokenID string `json:"tokenId,omitempty"`
+	// Status code of the last attempt, it is 0, if no response has been received.
+	Status      int    `json:"status"`
+	ExceptionID string `json:"exceptionId,omitempty"`
+	// BodyDigest is the SHA-256 hash of the encoded request body, streams are not hashed.
+	BodyDigest string `json:"bodyDigest,omitempty"`
+	// Error is set, if no response has been received, for example because of a network error.
+	Error string `json:"error,omitempty"`
+}
+
+// WithAuditTokenID sets the function, which returns ID of the token from the request headers.
+// By default, the ID is parsed from the X-StorageApi-Token header.
+func WithAuditTokenID(fn func(header http.Header) string) Option {
+	return func(c *config) {
+		c.auditTokenID = fn
+	}
+}
+
+// WithAuditRedaction adds the function, which modifies each AuditRecord before it is written, for example to mask a field.
+func WithAuditRedaction(fn func(record *AuditRecord)) Option {
+	return func(c *config) {
+		c.auditRedactions = append(c.auditRedactions, fn)
+	}
+}
+
+// AuditLog writes a record of each mutating request (POST, PUT, PATCH, DELETE) to a writer as JSON Lines, see AuditLog.Factory.
+//
+// One record is written, when the request is processed, after all retries and redirects.
+// Sensitive values are masked by the same rules as in the SlogTracer, see request.Redactor, bodies are not recorded.
+type AuditLog struct {
+	lock       sync.Mutex
+	encoder    *json.Encoder
+	redactor   request.Redactor
+	tokenID    func(header http.Header) string
+	redactions []func(record *AuditRecord)
+	err        error
+}
+
+// NewAuditLog creates an AuditLog, which writes records to the writer.
+// The WithAuditTokenID, WithAuditRedaction and the redaction options are used, other options are ignored.
+func NewAuditLog(wr io.Writer, opts ...Option) *AuditLog {
+	cfg := config{auditTokenID: storageAPITokenID}
+	for _, o := range opts {
+		o(&cfg)
+	}
+	return &AuditLog{
+		encoder:    json.NewEncoder(wr),
+		redactor:   request.NewRedactor(cfg.redactOptions...),
+		tokenID:    cfg.auditTokenID,
+		redactions: cfg.auditRedactions,
+	}
+}
+
+// Factory returns the trace Factory, which writes records to the AuditLog.
+func (l *AuditLog) Factory() Factory {
+	return func(ctx context.Context, reqDef request.HTTPRequest) (context.Context, *ClientTrace) {
+		t := &ClientTrace{}
+		if request.IsSafeMethod(reqDef.Method()) {
+			return ctx, t
+		}
+
+		record := AuditRecord{Time: time.Now().UTC(), Method: reqDef.Method(), URL: l.redactor.URL(reqDef.URL()).String()}
+		record.DefinedIn, _ = request.ContextDefinedIn(ctx)
+		record.BodyDigest = bodyDigest(reqDef)
+
+		started := false
+		t.HTTPRequestStart = func(req *http.Request) {
+			// The first attempt is recorded, not a redirect
+			if !started {
+				started = true
+				record.URL = redactedRequestURL(l.redactor, reqDef, req).String()
+				record.TokenID = l.tokenID(req.Header)
+			}
+		}
+		t.HTTPResponse = func(res *http.Response, err error) {
+			if res != nil {
+				record.Status = res.StatusCode
+			}
+		}
+		t.RequestProcessed = func(result any, err error) {
+			var withExceptionID interface{ ErrorExceptionID() string }
+			if errors.As(err, &withExceptionID) {
+				record.ExceptionID = withExceptionID.ErrorExceptionID()
+			}
+			if err != nil && record.Status == 0 {
+				// The URL error contains unmasked URL
+				var urlErr *url.Error
+				if errors.As(err, &urlErr) {
+					err = urlErr.Err
+				}
+				record.Error = err.Error()
+			}
+			l.write(record)
+		}
+		return ctx, t
+	}
+}
+
+// Err returns the first error, which occurred when a record was written, the next records are not written.
+func (l *AuditLog) Err() error {
+	l.lock.Lock()
+	defer l.lock.Unlock()
+	return l.err
+}
+
+func (l *AuditLog) write(record AuditRecord) {
+	for _, fn := range l.redactions {
+		fn(&record)
+	}
+
+	l.lock.Lock()
+	defer l.lock.Unlock()
+	if l.err == nil {
+		l.err = l.encoder.Encode(record)
+	}
+}
+
+// bodyDigest returns the SHA-256 hash of the encoded request body, or an empty string, if the body is empty or a stream.
+func bodyDigest(reqDef request.HTTPRequest) string {
+	switch reqDef.RequestBody().(type) {
+	case nil, io.Reader, *request.MultipartBody:
+		return ""
+	}
+	body, err := request.ExportBody(reqDef)
+	if err != nil || len(body) == 0 {
+		return ""
+	}
+	hash := sha256.Sum256(body)
+	return "sha256:" + hex.EncodeToString(hash[:])
+}
+
+// storageAPITokenID returns ID of the Storage API token, the token has format "<projectId>-<tokenId>-<secret>".
+func storageAPITokenID(header http.Header) string {
+	parts := strings.SplitN(header.Get("X-StorageApi-Token"), "-", 3)
+	if len(parts) != 3 {
+		return ""
+	}
+	if _, err := strconv.Atoi(parts[1]); err != nil {
+		return ""
+	}
+	return parts[1]
+}
@@ -0,0 +1,101 @@
+package trace_test
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/jarcoal/httpmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/keboola/go-client/pkg/client"
+	"github.com/keboola/go-client/pkg/client/trace"
+	"github.com/keboola/go-client/pkg/request"
+)
+
+type auditTestError struct {
+	Message     string `json:"error"`
+	ExceptionID string `json:"exceptionId"`
+}
+
+func (e *auditTestError) Error() string {
+	return e.Message
+}
+
+func (e *auditTestError) ErrorExceptionID() string {
+	return e.ExceptionID
+}
+
+func createBucket(sender request.Sender, name string) request.APIRequest[request.NoResult] {
+	return request.NewAPIRequest(request.NoResult{}, request.NewHTTPRequest(sender).
+		WithPost("https://example.com/buckets").
+		AndQueryParam("signature", "secret-signature").
+		WithJSONBody(map[string]any{"name": name}).
+		WithError(&auditTestError{}))
+}
+
+func TestAuditLog(t *testing.T) {
+	t.Parallel()
+
+	// Mocked responses
+	transport := httpmock.NewMockTransport()
+	transport.RegisterResponder("GET", `=~^https://example.com/buckets`, httpmock.NewStringResponder(http.StatusOK, "[]"))
+	transport.RegisterResponder("POST", `=~^https://example.com/buckets`, httpmock.ResponderFromMultipleResponses([]*http.Response{
+		httpmock.NewStringResponse(http.StatusCreated, "{}"),
+		httpmock.NewJsonResponseOrPanic(http.StatusBadRequest, map[string]any{"error": "bucket already exists", "exceptionId": "exception-123"}),
+	}))
+
+	// Create client, the custom redaction modifies a field
+	var out bytes.Buffer
+	audit := trace.NewAuditLog(&out, trace.WithRedactedQueryParams("signature"), trace.WithAuditRedaction(func(record *trace.AuditRecord) {
+		record.DefinedIn = strings.ToUpper(record.DefinedIn)
+	}))
+	c := client.New().
+		WithTransport(transport).
+		WithHeader("X-StorageApi-Token", "123-456-secret-token").
+		AndTrace(audit.Factory())
+
+	// Send requests
+	ctx := context.Background()
+	_, _, err := request.NewHTTPRequest(c).WithGet("https://example.com/buckets").Send(ctx)
+	require.NoError(t, err)
+	require.NoError(t, createBucket(c, "foo").SendOrErr(ctx))
+	require.Error(t, createBucket(c, "foo").SendOrErr(ctx))
+	require.NoError(t, audit.Err())
+
+	// Secrets are not recorded
+	assert.NotContains(t, out.String(), "secret")
+
+	// Only mutating requests are recorded
+	var records []trace.AuditRecord
+	for _, line := range strings.Split(strings.TrimSpace(out.String()), "\n") {
+		var record trace.AuditRecord
+		require.NoError(t, json.Unmarshal([]byte(line), &record))
+		assert.False(t, record.Time.IsZero())
+		records = append(records, record)
+	}
+	require.Len(t, records, 2)
+
+	hash := sha256.Sum256([]byte(`{"name":"foo"}`))
+	expected := trace.AuditRecord{
+		DefinedIn:  "TRACE_TEST.CREATEBUCKET",
+		Method:     "POST",
+		URL:        "https://example.com/buckets?signature=%2A%2A%2A%2A",
+		TokenID:    "456",
+		Status:     http.StatusCreated,
+		BodyDigest: "sha256:" + hex.EncodeToString(hash[:]),
+	}
+	expected.Time = records[0].Time
+	assert.Equal(t, expected, records[0])
+
+	expected.Time = records[1].Time
+	expected.Status = http.StatusBadRequest
+	expected.ExceptionID = "exception-123"
+	assert.Equal(t, expected, records[1])
+}
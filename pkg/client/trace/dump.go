@@ -19,84 +19,99 @@ const dumpTraceMaxLength = 2000
 
 type dumpTrace struct {
 	ClientTrace
-	wr io.Writer
+	wr                 io.Writer
+	requestMethod      string
+	requestURI         string
+	responseStatusCode int
+	responseErr        error
+	retries            int
+	firstStartTime     time.Time
+	startTime          time.Time
+	headersTime        time.Time
 }
 
 // DumpTracer dumps HTTP request and response to a writer.
-// Output may contain unmasked tokens, do not use it in production!
+// Output may contain unmasked tokens, do not use it in production, use the SlogTracer instead!
+// See also the DumpBuffer, it dumps only failed or slow requests.
 func DumpTracer(wr io.Writer) Factory {
 	return func(ctx context.Context, reqDef request.HTTPRequest) (context.Context, *ClientTrace) {
-		var requestMethod, requestURI string
-		var responseStatusCode int
-		var requestDump []byte
-		var responseErr error
-		var startTime, headersTime time.Time
+		return ctx, &newDumpTrace(wr).ClientTrace
+	}
+}
 
-		t := &dumpTrace{wr: wr}
-		t.HTTPRequestStart = func(r *http.Request) {
-			startTime = time.Now()
-			requestMethod = r.Method
-			requestURI = r.URL.RequestURI()
-			requestDump, _ = httputil.DumpRequestOut(r, true)
+func newDumpTrace(wr io.Writer) *dumpTrace {
+	var requestDump []byte
+	t := &dumpTrace{wr: wr}
+	t.HTTPRequestStart = func(r *http.Request) {
+		t.startTime = time.Now()
+		if t.firstStartTime.IsZero() {
+			t.firstStartTime = t.startTime
+		}
+		t.requestMethod = r.Method
+		t.requestURI = r.URL.RequestURI()
+		requestDump, _ = httputil.DumpRequestOut(r, true)
+	}
+	t.HTTPResponse = func(r *http.Response, err error) {
+		// Response can be nil, for example, if some network error occurred
+		if r != nil {
+			t.responseStatusCode = r.StatusCode
+			t.responseErr = err
+			t.headersTime = time.Now()
+		}
+		if err != nil {
+			t.responseErr = err
 		}
-		t.HTTPResponse = func(r *http.Response, err error) {
-			// Response can be nil, for example, if some network error occurred
-			if r != nil {
-				responseStatusCode = r.StatusCode
-				responseErr = err
-				headersTime = time.Now()
-			}
-			if err != nil {
-				responseErr = err
-			}
 
-			// Dump request
-			t.log()
-			t.log(">>>>>> HTTP DUMP")
-			t.dump(string(requestDump))
+		// Dump request
+		t.log()
+		t.log(">>>>>> HTTP DUMP")
+		t.dump(string(requestDump))
 
-			// Dump response
-			t.log("------")
-			if err != nil {
-				t.log("ERROR: ", err)
+		// Dump response
+		t.log("------")
+		if err != nil {
+			t.log("ERROR: ", err)
+		} else {
+			// Dump response headers
+			if v, err := httputil.DumpResponse(r, false); err == nil {
+				t.log(strings.TrimSpace(string(v)))
 			} else {
-				// Dump response headers
-				if v, err := httputil.DumpResponse(r, false); err == nil {
-					t.log(strings.TrimSpace(string(v)))
-				} else {
-					t.log("cannot dump response headers: ", err)
+				t.log("cannot dump response headers: ", err)
+			}
+			// Dump response body
+			if r.Body != nil {
+				// Decode body and copy raw body to rawBody buffer
+				var rawBody bytes.Buffer
+				var decodedBody strings.Builder
+				teeReader := io.TeeReader(r.Body, &rawBody)
+				if bodyReader, err := decode.Decode(io.NopCloser(teeReader), r.Header.Get("Content-Encoding")); err != nil {
+					t.log("cannot read response body: ", err)
+				} else if _, err := io.Copy(&decodedBody, bodyReader); err != nil {
+					t.log("cannot read response body: ", err)
 				}
-				// Dump response body
-				if r.Body != nil {
-					// Decode body and copy raw body to rawBody buffer
-					var rawBody bytes.Buffer
-					var decodedBody strings.Builder
-					bodyReader, err := decode.Decode(io.NopCloser(io.TeeReader(r.Body, &rawBody)), r.Header.Get("Content-Encoding"))
-					if err != nil {
-						t.log("cannot read response body: ", err)
-					}
-					if _, err := io.Copy(&decodedBody, bodyReader); err != nil {
-						t.log("cannot read response body: ", err)
-					}
-					// Set buffered raw body back to the response
-					r.Body = io.NopCloser(bytes.NewReader(rawBody.Bytes()))
-					// Dump decoded response
-					t.log("------")
-					t.dump(decodedBody.String())
+				// Read the rest of the raw body, if the decoding has failed
+				if _, err := io.Copy(io.Discard, teeReader); err != nil {
+					t.log("cannot read response body: ", err)
 				}
+				// Set buffered raw body back to the response
+				r.Body = io.NopCloser(bytes.NewReader(rawBody.Bytes()))
+				// Dump decoded response
+				t.log("------")
+				t.dump(decodedBody.String())
 			}
-			t.log("<<<<<< HTTP DUMP END")
-		}
-		t.RetryDelay = func(attempt int, delay time.Duration) {
-			t.log()
-			t.log(">>>>>> HTTP RETRY", "| ATTEMPT:", attempt, "| DELAY:", delay, "| ", requestMethod, requestURI, responseStatusCode, "| ERROR:", responseErr)
 		}
-		t.RequestProcessed = func(result any, err error) {
-			t.log()
-			t.log(">>>>>> HTTP REQUEST PROCESSED", "| ", requestMethod, requestURI, responseStatusCode, "| ERROR:", responseErr, "| HEADERS AT:", headersTime.Sub(startTime), "| DONE AT:", time.Since(startTime))
-		}
-		return ctx, &t.ClientTrace
+		t.log("<<<<<< HTTP DUMP END")
+	}
+	t.RetryDelay = func(attempt int, delay time.Duration, reason RetryDelayReason) {
+		t.retries = attempt
+		t.log()
+		t.log(">>>>>> HTTP RETRY", "| ATTEMPT:", attempt, "| DELAY:", delay, "| REASON:", reason, "| ", t.requestMethod, t.requestURI, t.responseStatusCode, "| ERROR:", t.responseErr)
+	}
+	t.RequestProcessed = func(result any, err error) {
+		t.log()
+		t.log(">>>>>> HTTP REQUEST PROCESSED", "| ", t.requestMethod, t.requestURI, t.responseStatusCode, "| ERROR:", t.responseErr, "| HEADERS AT:", t.headersTime.Sub(t.startTime), "| DONE AT:", time.Since(t.startTime))
 	}
+	return t
 }
 
 func (t *dumpTrace) dump(body string) {
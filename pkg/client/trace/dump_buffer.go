@@ -0,0 +1,155 @@
+package trace
+
+import (
+	"context"
+	"io"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/keboola/go-client/pkg/request"
+)
+
+// DumpCondition decides, whether the dump of a processed request is written, see DumpBuffer.
+type DumpCondition func(info DumpInfo) bool
+
+// DumpInfo describes a processed request, see DumpCondition.
+type DumpInfo struct {
+	Method string
+	URI    string
+	// StatusCode of the last attempt, it is 0, if no response has been received.
+	StatusCode int
+	// Err is an error of the last attempt, for example a network error.
+	Err error
+	// Retries is the number of retries, 0 means that the request has been sent only once.
+	Retries int
+	// Duration from the start of the first attempt.
+	Duration time.Duration
+}
+
+// DumpBuffer dumps HTTP requests and responses in the same format as the DumpTracer,
+// but the dump of a request is held in a buffer and written only if the condition matches, see DumpBuffer.Factory.
+//
+// The last dumps are kept in a ring buffer, regardless of the condition, see DumpBuffer.Dumps,
+// for example to print them when a test fails.
+// Output may contain unmasked tokens, do not use it in production!
+type DumpBuffer struct {
+	lock      sync.Mutex
+	wr        io.Writer
+	condition DumpCondition
+	ring      []string
+	next      int
+}
+
+// NewDumpBuffer creates a DumpBuffer, which writes dumps matching the condition to the writer.
+// The size is the number of the last dumps kept in the ring buffer, 0 disables the ring buffer.
+// The writer may be nil, if only the ring buffer is used.
+func NewDumpBuffer(wr io.Writer, condition DumpCondition, size int) *DumpBuffer {
+	return &DumpBuffer{wr: wr, condition: condition, ring: make([]string, 0, size)}
+}
+
+// DumpFailed matches a request with an error response or a transport error.
+func DumpFailed() DumpCondition {
+	return DumpAny(DumpStatusAtLeast(400), DumpError())
+}
+
+// DumpStatusAtLeast matches a request, if the status code of the last attempt is equal or greater than the code.
+func DumpStatusAtLeast(code int) DumpCondition {
+	return func(info DumpInfo) bool {
+		return info.StatusCode >= code
+	}
+}
+
+// DumpError matches a request, if the last attempt failed with an error, for example a network error.
+func DumpError() DumpCondition {
+	return func(info DumpInfo) bool {
+		return info.Err != nil
+	}
+}
+
+// DumpRetried matches a request, which has been retried at least once.
+func DumpRetried() DumpCondition {
+	return func(info DumpInfo) bool {
+		return info.Retries > 0
+	}
+}
+
+// DumpSlowerThan matches a request, if the duration of all attempts exceeds the threshold.
+func DumpSlowerThan(threshold time.Duration) DumpCondition {
+	return func(info DumpInfo) bool {
+		return info.Duration > threshold
+	}
+}
+
+// DumpAny matches a request, if any of the conditions matches.
+func DumpAny(conditions ...DumpCondition) DumpCondition {
+	return func(info DumpInfo) bool {
+		for _, c := range conditions {
+			if c(info) {
+				return true
+			}
+		}
+		return false
+	}
+}
+
+// Factory returns the trace Factory, which dumps requests to the DumpBuffer.
+func (b *DumpBuffer) Factory() Factory {
+	return func(ctx context.Context, reqDef request.HTTPRequest) (context.Context, *ClientTrace) {
+		var buf strings.Builder
+		t := newDumpTrace(&buf)
+		processed := t.RequestProcessed
+		t.RequestProcessed = func(result any, err error) {
+			processed(result, err)
+			info := DumpInfo{
+				Method:     t.requestMethod,
+				URI:        t.requestURI,
+				StatusCode: t.responseStatusCode,
+				Err:        t.responseErr,
+				Retries:    t.retries,
+			}
+			if !t.firstStartTime.IsZero() {
+				info.Duration = time.Since(t.firstStartTime)
+			}
+			b.add(buf.String(), info)
+		}
+		return ctx, &t.ClientTrace
+	}
+}
+
+// Dumps returns the last dumps from the ring buffer, the oldest first.
+func (b *DumpBuffer) Dumps() []string {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+	out := make([]string, 0, len(b.ring))
+	out = append(out, b.ring[b.next:]...)
+	out = append(out, b.ring[:b.next]...)
+	return out
+}
+
+// WriteTo writes the last dumps from the ring buffer, it implements io.WriterTo.
+func (b *DumpBuffer) WriteTo(w io.Writer) (int64, error) {
+	n, err := io.WriteString(w, strings.Join(b.Dumps(), ""))
+	return int64(n), err
+}
+
+func (b *DumpBuffer) add(dump string, info DumpInfo) {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+
+	// The whole dump is written at once, so dumps of concurrent requests are not mixed
+	if b.wr != nil && b.condition != nil && b.condition(info) {
+		_, _ = io.WriteString(b.wr, dump)
+	}
+
+	// Ring buffer
+	switch {
+	case cap(b.ring) == 0:
+		// The ring buffer is disabled
+	case len(b.ring) < cap(b.ring):
+		b.ring = append(b.ring, dump)
+	default:
+		b.ring[b.next] = dump
+		b.next = (b.next + 1) % len(b.ring)
+	}
+}
@@ -0,0 +1,77 @@
+package trace_test
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/jarcoal/httpmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/keboola/go-client/pkg/client"
+	"github.com/keboola/go-client/pkg/client/trace"
+	"github.com/keboola/go-client/pkg/request"
+)
+
+func TestDumpBuffer(t *testing.T) {
+	t.Parallel()
+
+	// Mocked responses
+	transport := httpmock.NewMockTransport()
+	transport.RegisterResponder("GET", `https://example.com/ok`, httpmock.NewStringResponder(http.StatusOK, "OK"))
+	transport.RegisterResponder("GET", `https://example.com/not-found`, httpmock.NewStringResponder(http.StatusNotFound, "NOT FOUND"))
+	transport.RegisterResponder("GET", `https://example.com/retry`, httpmock.ResponderFromMultipleResponses([]*http.Response{
+		{StatusCode: http.StatusServiceUnavailable},
+		{StatusCode: http.StatusOK, Body: io.NopCloser(strings.NewReader("RETRIED"))},
+	}))
+
+	// Create client
+	var out strings.Builder
+	dumps := trace.NewDumpBuffer(&out, trace.DumpAny(trace.DumpFailed(), trace.DumpRetried()), 2)
+	c := client.New().
+		WithTransport(transport).
+		WithRetry(client.TestingRetry()).
+		AndTrace(dumps.Factory())
+
+	// Send requests
+	ctx := context.Background()
+	_, _, err := request.NewHTTPRequest(c).WithGet("https://example.com/ok").Send(ctx)
+	require.NoError(t, err)
+	_, _, err = request.NewHTTPRequest(c).WithGet("https://example.com/not-found").Send(ctx)
+	require.Error(t, err)
+	_, _, err = request.NewHTTPRequest(c).WithGet("https://example.com/retry").Send(ctx)
+	require.NoError(t, err)
+
+	// Only the failed and the retried requests are written
+	assert.NotContains(t, out.String(), "GET /ok")
+	assert.Contains(t, out.String(), "GET /not-found")
+	assert.Contains(t, out.String(), "NOT FOUND")
+	assert.Contains(t, out.String(), ">>>>>> HTTP RETRY | ATTEMPT: 1")
+	assert.Contains(t, out.String(), "RETRIED")
+
+	// The ring buffer contains the last 2 dumps, regardless of the condition
+	last := dumps.Dumps()
+	require.Len(t, last, 2)
+	assert.Contains(t, last[0], "GET /not-found")
+	assert.Contains(t, last[1], "GET /retry")
+	var buf strings.Builder
+	_, err = dumps.WriteTo(&buf)
+	require.NoError(t, err)
+	assert.Equal(t, last[0]+last[1], buf.String())
+}
+
+func TestDumpConditions(t *testing.T) {
+	t.Parallel()
+
+	assert.False(t, trace.DumpFailed()(trace.DumpInfo{StatusCode: http.StatusOK}))
+	assert.True(t, trace.DumpFailed()(trace.DumpInfo{StatusCode: http.StatusBadRequest}))
+	assert.True(t, trace.DumpFailed()(trace.DumpInfo{Err: io.ErrUnexpectedEOF}))
+	assert.False(t, trace.DumpRetried()(trace.DumpInfo{}))
+	assert.True(t, trace.DumpRetried()(trace.DumpInfo{Retries: 1}))
+	assert.False(t, trace.DumpSlowerThan(time.Second)(trace.DumpInfo{Duration: time.Second}))
+	assert.True(t, trace.DumpSlowerThan(time.Second)(trace.DumpInfo{Duration: 2 * time.Second}))
+}
@@ -49,7 +49,7 @@ HTTP/0.0 423 Locked
 Content-Length: 0
 <<<<<< HTTP DUMP END
 
->>>>>> HTTP RETRY | ATTEMPT: 1 | DELAY: 1ms |  GET / 423 | ERROR: <nil>
+>>>>>> HTTP RETRY | ATTEMPT: 1 | DELAY: 1ms | REASON: backoff |  GET / 423 | ERROR: <nil>
 
 >>>>>> HTTP DUMP
 GET / HTTP/1.1
@@ -61,7 +61,7 @@ HTTP/0.0 429 Too Many Requests
 Content-Length: 0
 <<<<<< HTTP DUMP END
 
->>>>>> HTTP RETRY | ATTEMPT: 2 | DELAY: 1ms |  GET / 429 | ERROR: <nil>
+>>>>>> HTTP RETRY | ATTEMPT: 2 | DELAY: 1ms | REASON: backoff |  GET / 429 | ERROR: <nil>
 
 >>>>>> HTTP DUMP
 GET / HTTP/1.1
@@ -0,0 +1,516 @@
+package trace
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptrace"
+	"runtime/debug"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+	"unicode/utf8"
+
+	"github.com/keboola/go-client/pkg/client/decode"
+	"github.com/keboola/go-client/pkg/request"
+)
+
+const (
+	harVersion            = "1.2"
+	harCreatorName        = "keboola-go-client"
+	harCreatorModule      = "github.com/keboola/go-client"
+	harDateTimeFormat     = "2006-01-02T15:04:05.000Z07:00"
+	harDefaultMaxBodySize = 64 * 1024
+)
+
+// WithMaxBodySize sets maximum size of a body recorded by the HARRecorder, default is 64 KiB.
+// The rest of the body is cut off, the content is marked by a comment.
+func WithMaxBodySize(size int) Option {
+	return func(c *config) {
+		c.maxBodySize = size
+	}
+}
+
+// HARRecorder records each attempt of each request to an HTTP Archive (HAR 1.2) document, see HARRecorder.Factory.
+//
+// Retries and redirects are recorded as separate entries.
+// The document can be opened in browser devtools or attached to a support ticket.
+// Sensitive values are masked by the same rules as in the SlogTracer, see request.Redactor.
+// Bodies are decoded and cut off at the maximum size, see WithMaxBodySize.
+// An entry is recorded when the response body is closed, or when the attempt fails.
+type HARRecorder struct {
+	lock        sync.Mutex
+	redactor    request.Redactor
+	maxBodySize int
+	entries     []harEntry
+}
+
+// HAR is an HTTP Archive document.
+type HAR struct {
+	Log HARLog `json:"log"`
+}
+
+// HARLog is the root of the HAR document.
+type HARLog struct {
+	Version string     `json:"version"`
+	Creator HARCreator `json:"creator"`
+	Entries []HAREntry `json:"entries"`
+}
+
+// HARCreator identifies the application that created the HAR document.
+type HARCreator struct {
+	Name    string `json:"name"`
+	Version string `json:"version"`
+}
+
+// HAREntry is one attempt of a request.
+type HAREntry struct {
+	StartedDateTime string      `json:"startedDateTime"`
+	Time            float64     `json:"time"`
+	Request         HARRequest  `json:"request"`
+	Response        HARResponse `json:"response"`
+	Cache           struct{}    `json:"cache"`
+	Timings         HARTimings  `json:"timings"`
+	ServerIPAddress string      `json:"serverIPAddress,omitempty"`
+	// Error of the attempt, the response is empty, if the error occurred before the response was received.
+	Error string `json:"_error,omitempty"`
+}
+
+// HARRequest is the request of a HAREntry.
+type HARRequest struct {
+	Method      string         `json:"method"`
+	URL         string         `json:"url"`
+	HTTPVersion string         `json:"httpVersion"`
+	Cookies     []HARNameValue `json:"cookies"`
+	Headers     []HARNameValue `json:"headers"`
+	QueryString []HARNameValue `json:"queryString"`
+	PostData    *HARPostData   `json:"postData,omitempty"`
+	HeadersSize int64          `json:"headersSize"`
+	BodySize    int64          `json:"bodySize"`
+}
+
+// HARResponse is the response of a HAREntry.
+type HARResponse struct {
+	Status      int            `json:"status"`
+	StatusText  string         `json:"statusText"`
+	HTTPVersion string         `json:"httpVersion"`
+	Cookies     []HARNameValue `json:"cookies"`
+	Headers     []HARNameValue `json:"headers"`
+	Content     HARContent     `json:"content"`
+	RedirectURL string         `json:"redirectURL"`
+	HeadersSize int64          `json:"headersSize"`
+	BodySize    int64          `json:"bodySize"`
+}
+
+// HARNameValue is a header, a query parameter or a cookie.
+type HARNameValue struct {
+	Name  string `json:"name"`
+	Value string `json:"value"`
+}
+
+// HARPostData is the body of a HARRequest.
+type HARPostData struct {
+	MimeType string `json:"mimeType"`
+	Text     string `json:"text"`
+	Comment  string `json:"comment,omitempty"`
+}
+
+// HARContent is the decoded body of a HARResponse.
+type HARContent struct {
+	Size        int64  `json:"size"`
+	Compression int64  `json:"compression,omitempty"`
+	MimeType    string `json:"mimeType"`
+	Text        string `json:"text,omitempty"`
+	Encoding    string `json:"encoding,omitempty"`
+	Comment     string `json:"comment,omitempty"`
+}
+
+// HARTimings are durations of the attempt phases in milliseconds, -1 means that the phase does not apply.
+// The Connect time includes the SSL time.
+type HARTimings struct {
+	Blocked float64 `json:"blocked"`
+	DNS     float64 `json:"dns"`
+	Connect float64 `json:"connect"`
+	Send    float64 `json:"send"`
+	Wait    float64 `json:"wait"`
+	Receive float64 `json:"receive"`
+	SSL     float64 `json:"ssl"`
+}
+
+type harEntry struct {
+	HAREntry
+	startedAt time.Time
+}
+
+// NewHARRecorder creates an empty HARRecorder, the request.DefaultRedactedHeaders, including the X-StorageApi-Token, are always masked.
+// The WithMaxBodySize and the redaction options are used, other options are ignored.
+func NewHARRecorder(opts ...Option) *HARRecorder {
+	cfg := config{maxBodySize: harDefaultMaxBodySize}
+	for _, o := range opts {
+		o(&cfg)
+	}
+	return &HARRecorder{redactor: request.NewRedactor(cfg.redactOptions...), maxBodySize: cfg.maxBodySize}
+}
+
+// Factory returns the trace Factory, which records requests to the HARRecorder.
+func (r *HARRecorder) Factory() Factory {
+	return func(ctx context.Context, reqDef request.HTTPRequest) (context.Context, *ClientTrace) {
+		var attempt *harAttempt
+		var postData *HARPostData
+		t := &ClientTrace{}
+		t.HTTPRequestStart = func(req *http.Request) {
+			attempt = &harAttempt{recorder: r, reqDef: reqDef, req: req, start: time.Now()}
+			// The body is the same for all attempts, a redirect may be sent without the body
+			if req.Body != nil && req.Body != http.NoBody {
+				if postData == nil {
+					postData = r.postData(reqDef, req)
+				}
+				attempt.postData = postData
+			}
+		}
+		t.DNSStart = func(httptrace.DNSStartInfo) {
+			attempt.set(&attempt.dnsStart)
+		}
+		t.DNSDone = func(httptrace.DNSDoneInfo) {
+			attempt.set(&attempt.dnsDone)
+		}
+		t.ConnectStart = func(network, addr string) {
+			// Multiple connections may be dialed in parallel, the first start and the last done are used
+			attempt.lock.Lock()
+			defer attempt.lock.Unlock()
+			if attempt.connectStart.IsZero() {
+				attempt.connectStart = time.Now()
+			}
+		}
+		t.ConnectDone = func(network, addr string, err error) {
+			attempt.set(&attempt.connectDone)
+		}
+		t.TLSHandshakeStart = func() {
+			attempt.set(&attempt.tlsStart)
+		}
+		t.TLSHandshakeDone = func(tls.ConnectionState, error) {
+			attempt.set(&attempt.tlsDone)
+		}
+		t.GotConn = func(info httptrace.GotConnInfo) {
+			attempt.set(&attempt.gotConn)
+			if info.Conn != nil {
+				if host, _, err := net.SplitHostPort(info.Conn.RemoteAddr().String()); err == nil {
+					attempt.lock.Lock()
+					attempt.serverIP = host
+					attempt.lock.Unlock()
+				}
+			}
+		}
+		t.WroteRequest = func(httptrace.WroteRequestInfo) {
+			attempt.set(&attempt.wroteRequest)
+		}
+		t.GotFirstResponseByte = func() {
+			attempt.set(&attempt.firstByte)
+		}
+		t.HTTPResponse = func(res *http.Response, err error) {
+			if res != nil && res.Body != nil && res.Body != http.NoBody {
+				// Capture the beginning of the raw body, while it is read by the caller
+				attempt.body = &harBodyCapture{ReadCloser: res.Body, limit: r.maxBodySize}
+				res.Body = attempt.body
+			}
+		}
+		t.HTTPRequestDone = func(res *http.Response, send, received int64, err error) {
+			r.add(attempt.entry(res, send, received, err))
+		}
+		return ctx, t
+	}
+}
+
+// HAR returns the HAR document with all recorded entries, sorted by the start time.
+func (r *HARRecorder) HAR() HAR {
+	r.lock.Lock()
+	entries := make([]harEntry, len(r.entries))
+	copy(entries, r.entries)
+	r.lock.Unlock()
+
+	sort.SliceStable(entries, func(i, j int) bool {
+		return entries[i].startedAt.Before(entries[j].startedAt)
+	})
+
+	out := HAR{Log: HARLog{
+		Version: harVersion,
+		Creator: HARCreator{Name: harCreatorName, Version: harCreatorVersion()},
+		Entries: make([]HAREntry, 0, len(entries)),
+	}}
+	for _, e := range entries {
+		out.Log.Entries = append(out.Log.Entries, e.HAREntry)
+	}
+	return out
+}
+
+// MarshalJSON implements json.Marshaler, the recorder is encoded as the HAR document.
+func (r *HARRecorder) MarshalJSON() ([]byte, error) {
+	return json.Marshal(r.HAR())
+}
+
+// WriteTo writes the HAR document as an indented JSON, it implements io.WriterTo.
+func (r *HARRecorder) WriteTo(w io.Writer) (int64, error) {
+	content, err := json.MarshalIndent(r.HAR(), "", "  ")
+	if err != nil {
+		return 0, err
+	}
+	n, err := w.Write(append(content, '\n'))
+	return int64(n), err
+}
+
+func (r *HARRecorder) add(entry harEntry) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	r.entries = append(r.entries, entry)
+}
+
+func (r *HARRecorder) postData(reqDef request.HTTPRequest, req *http.Request) *HARPostData {
+	out := &HARPostData{MimeType: req.Header.Get("Content-Type")}
+	body, err := request.ExportBody(reqDef)
+	if err != nil {
+		out.Comment = err.Error()
+		return out
+	}
+	var truncated bool
+	out.Text, truncated = r.cutBody(body, false)
+	if truncated {
+		out.Comment = fmt.Sprintf("body truncated to %d bytes, full size %d bytes", len(out.Text), len(body))
+	}
+	return out
+}
+
+// cutBody returns the body as a string, cut off at the maximum size.
+// The truncated flag marks a body, which has been already cut off.
+func (r *HARRecorder) cutBody(body []byte, truncated bool) (string, bool) {
+	if len(body) > r.maxBodySize {
+		body = body[:r.maxBodySize]
+		truncated = true
+	}
+	if !truncated {
+		return string(body), false
+	}
+	// Do not cut a multibyte character
+	for i := 1; i < utf8.UTFMax && len(body) > 0; i++ {
+		if v, _ := utf8.DecodeLastRune(body); v != utf8.RuneError {
+			break
+		}
+		body = body[:len(body)-1]
+	}
+	return string(body), true
+}
+
+// harAttempt collects data of one attempt, the httptrace hooks may be called from different goroutines.
+type harAttempt struct {
+	lock     sync.Mutex
+	recorder *HARRecorder
+	reqDef   request.HTTPRequest
+	req      *http.Request
+	postData *HARPostData
+	body     *harBodyCapture
+	serverIP string
+
+	start        time.Time
+	dnsStart     time.Time
+	dnsDone      time.Time
+	connectStart time.Time
+	connectDone  time.Time
+	tlsStart     time.Time
+	tlsDone      time.Time
+	gotConn      time.Time
+	wroteRequest time.Time
+	firstByte    time.Time
+}
+
+func (a *harAttempt) set(t *time.Time) {
+	a.lock.Lock()
+	defer a.lock.Unlock()
+	*t = time.Now()
+}
+
+func (a *harAttempt) entry(res *http.Response, send, received int64, err error) harEntry {
+	a.lock.Lock()
+	defer a.lock.Unlock()
+	r := a.recorder
+	done := time.Now()
+
+	// Request
+	reqURL := redactedRequestURL(r.redactor, a.reqDef, a.req)
+	out := harEntry{startedAt: a.start}
+	out.StartedDateTime = a.start.Format(harDateTimeFormat)
+	out.Time = milliseconds(a.start, done)
+	out.ServerIPAddress = a.serverIP
+	out.Request = HARRequest{
+		Method:      a.req.Method,
+		URL:         reqURL.String(),
+		HTTPVersion: "HTTP/1.1",
+		Cookies:     []HARNameValue{},
+		Headers:     harHeaders(r.redactor.Header(a.req.Header)),
+		QueryString: []HARNameValue{},
+		PostData:    a.postData,
+		HeadersSize: -1,
+		BodySize:    send,
+	}
+	if a.req.Proto != "" {
+		// Proto is empty in a redirect request
+		out.Request.HTTPVersion = a.req.Proto
+	}
+	query := reqURL.Query()
+	for _, k := range sortedKeys(query) {
+		for _, v := range query[k] {
+			out.Request.QueryString = append(out.Request.QueryString, HARNameValue{Name: k, Value: v})
+		}
+	}
+
+	// Response
+	out.Response = HARResponse{
+		Cookies:     []HARNameValue{},
+		Headers:     []HARNameValue{},
+		HeadersSize: -1,
+		BodySize:    -1,
+	}
+	if res != nil {
+		if res.Proto != "" {
+			out.Request.HTTPVersion = res.Proto
+		}
+		out.Response.Status = res.StatusCode
+		out.Response.StatusText = strings.TrimSpace(strings.TrimPrefix(res.Status, strconv.Itoa(res.StatusCode)))
+		out.Response.HTTPVersion = out.Request.HTTPVersion
+		out.Response.Headers = harHeaders(r.redactor.Header(res.Header))
+		out.Response.RedirectURL = res.Header.Get("Location")
+		out.Response.BodySize = received
+		out.Response.Content = r.content(res, a.body, received)
+	}
+	if err != nil {
+		out.Error = err.Error()
+	}
+
+	// Timings
+	out.Timings = HARTimings{
+		Blocked: -1,
+		DNS:     milliseconds(a.dnsStart, a.dnsDone),
+		Connect: milliseconds(a.connectStart, a.connectDone),
+		Send:    milliseconds(a.gotConn, a.wroteRequest),
+		Wait:    milliseconds(a.wroteRequest, a.firstByte),
+		Receive: -1,
+		SSL:     milliseconds(a.tlsStart, a.tlsDone),
+	}
+	if out.Timings.SSL >= 0 {
+		out.Timings.Connect = milliseconds(a.connectStart, a.tlsDone)
+	}
+	if !a.gotConn.IsZero() {
+		// Time spent in the queue, waiting for a connection, without the DNS and connect phases
+		out.Timings.Blocked = milliseconds(a.start, a.gotConn) - max(out.Timings.DNS, 0) - max(out.Timings.Connect, 0)
+		out.Timings.Blocked = max(out.Timings.Blocked, 0)
+	}
+	if !a.firstByte.IsZero() && err == nil {
+		out.Timings.Receive = milliseconds(a.firstByte, done)
+	}
+	return out
+}
+
+func (r *HARRecorder) content(res *http.Response, body *harBodyCapture, received int64) HARContent {
+	out := HARContent{MimeType: res.Header.Get("Content-Type")}
+	if body == nil || body.buf.Len() == 0 {
+		return out
+	}
+
+	// Decode the captured part of the body, decoding of a cut off body ends with an error
+	var decoded bytes.Buffer
+	reader, err := decode.Decode(io.NopCloser(bytes.NewReader(body.buf.Bytes())), res.Header.Get("Content-Encoding"))
+	if err == nil {
+		_, err = io.Copy(&decoded, io.LimitReader(reader, int64(r.maxBodySize)+1))
+	}
+	if err != nil && !body.truncated {
+		out.Comment = "cannot decode body: " + err.Error()
+		return out
+	}
+
+	text, truncated := r.cutBody(decoded.Bytes(), body.truncated)
+	if utf8.ValidString(text) {
+		out.Text = text
+	} else {
+		out.Text = base64.StdEncoding.EncodeToString([]byte(text))
+		out.Encoding = "base64"
+	}
+	out.Size = int64(len(text))
+	if truncated {
+		out.Comment = fmt.Sprintf("body truncated to %d bytes, received %d bytes", len(text), received)
+	} else if compression := out.Size - received; compression > 0 {
+		out.Compression = compression
+	}
+	return out
+}
+
+// harBodyCapture copies the beginning of the body to a buffer, while the body is read.
+type harBodyCapture struct {
+	io.ReadCloser
+	lock      sync.Mutex
+	buf       bytes.Buffer
+	limit     int
+	truncated bool
+}
+
+func (c *harBodyCapture) Read(p []byte) (int, error) {
+	n, err := c.ReadCloser.Read(p)
+	if n > 0 {
+		c.lock.Lock()
+		if free := c.limit - c.buf.Len(); free < n {
+			c.buf.Write(p[:max(free, 0)])
+			c.truncated = true
+		} else {
+			c.buf.Write(p[:n])
+		}
+		c.lock.Unlock()
+	}
+	return n, err
+}
+
+func harHeaders(header http.Header) []HARNameValue {
+	out := []HARNameValue{}
+	for _, name := range sortedKeys(header) {
+		for _, value := range header[name] {
+			out = append(out, HARNameValue{Name: name, Value: value})
+		}
+	}
+	return out
+}
+
+// harCreatorVersion returns version of the module, if it is available in the build info.
+func harCreatorVersion() string {
+	if info, ok := debug.ReadBuildInfo(); ok {
+		if info.Main.Path == harCreatorModule && info.Main.Version != "" {
+			return info.Main.Version
+		}
+		for _, dep := range info.Deps {
+			if dep.Path == harCreatorModule {
+				return dep.Version
+			}
+		}
+	}
+	return "(devel)"
+}
+
+// milliseconds returns the duration between start and end, or -1, if any of the times is missing.
+func milliseconds(start, end time.Time) float64 {
+	if start.IsZero() || end.IsZero() {
+		return -1
+	}
+	return float64(end.Sub(start).Microseconds()) / 1000
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,181 @@
+package trace_test
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/jarcoal/httpmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/keboola/go-client/pkg/client"
+	"github.com/keboola/go-client/pkg/client/trace"
+	"github.com/keboola/go-client/pkg/request"
+)
+
+func TestHARRecorder(t *testing.T) {
+	t.Parallel()
+
+	// Gzipped response body
+	var gzipped bytes.Buffer
+	gzipWriter := gzip.NewWriter(&gzipped)
+	_, err := gzipWriter.Write([]byte(`{"id":"123","name":"foo bar baz"}`))
+	require.NoError(t, err)
+	require.NoError(t, gzipWriter.Close())
+
+	// Mocked responses: retry, redirect and a compressed body
+	transport := httpmock.NewMockTransport()
+	transport.RegisterResponder("PUT", `https://example.com/items?token=secret-query`, httpmock.ResponderFromMultipleResponses([]*http.Response{
+		{StatusCode: http.StatusTooManyRequests, Status: "429 Too Many Requests", Header: http.Header{"Set-Cookie": []string{"secret-cookie"}}},
+		{StatusCode: http.StatusFound, Status: "302 Found", Header: http.Header{"Location": []string{"https://example.com/items/123"}}},
+	}))
+	transport.RegisterResponder("GET", `https://example.com/items/123`, func(req *http.Request) (*http.Response, error) {
+		return &http.Response{
+			StatusCode: http.StatusOK,
+			Status:     "200 OK",
+			Header:     http.Header{"Content-Type": []string{"application/json"}, "Content-Encoding": []string{"gzip"}},
+			Body:       io.NopCloser(bytes.NewReader(gzipped.Bytes())),
+		}, nil
+	})
+
+	// Create client
+	recorder := trace.NewHARRecorder(trace.WithRedactedQueryParams("token"))
+	c := client.New().
+		WithTransport(transport).
+		WithRetry(client.TestingRetry()).
+		AndTrace(recorder.Factory())
+
+	// Send request
+	result := make(map[string]any)
+	_, _, err = request.NewHTTPRequest(c).
+		WithPut("https://example.com/items").
+		AndQueryParam("token", "secret-query").
+		AndHeader("X-StorageApi-Token", "secret-token").
+		WithJSONBody(map[string]any{"name": "foo"}).
+		WithResult(&result).
+		Send(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, map[string]any{"id": "123", "name": "foo bar baz"}, result)
+
+	// Secrets are not recorded
+	var out bytes.Buffer
+	_, err = recorder.WriteTo(&out)
+	require.NoError(t, err)
+	assert.NotContains(t, out.String(), "secret")
+
+	// The document is a valid JSON
+	doc := make(map[string]any)
+	require.NoError(t, json.Unmarshal(out.Bytes(), &doc))
+	assert.Equal(t, "1.2", doc["log"].(map[string]any)["version"])
+
+	// Each attempt is an entry
+	har := recorder.HAR()
+	require.Len(t, har.Log.Entries, 3)
+	assert.Equal(t, "keboola-go-client", har.Log.Creator.Name)
+
+	// Retry
+	entry := har.Log.Entries[0]
+	assert.Equal(t, "PUT", entry.Request.Method)
+	assert.Equal(t, "https://example.com/items?token=%2A%2A%2A%2A", entry.Request.URL)
+	assert.Equal(t, []trace.HARNameValue{{Name: "token", Value: "****"}}, entry.Request.QueryString)
+	assert.Contains(t, entry.Request.Headers, trace.HARNameValue{Name: "X-Storageapi-Token", Value: "****"})
+	assert.Equal(t, &trace.HARPostData{MimeType: "application/json", Text: `{"name":"foo"}`}, entry.Request.PostData)
+	assert.Equal(t, 429, entry.Response.Status)
+	assert.Equal(t, "Too Many Requests", entry.Response.StatusText)
+	assert.Contains(t, entry.Response.Headers, trace.HARNameValue{Name: "Set-Cookie", Value: "****"})
+
+	// Redirect
+	entry = har.Log.Entries[1]
+	assert.Equal(t, "PUT", entry.Request.Method)
+	assert.Equal(t, 302, entry.Response.Status)
+	assert.Equal(t, "https://example.com/items/123", entry.Response.RedirectURL)
+
+	// Final response, the body is decoded
+	entry = har.Log.Entries[2]
+	assert.Equal(t, "GET", entry.Request.Method)
+	assert.Equal(t, "https://example.com/items/123", entry.Request.URL)
+	assert.Contains(t, entry.Request.Headers, trace.HARNameValue{Name: "Referer", Value: "https://example.com/items?token=%2A%2A%2A%2A"})
+	assert.Nil(t, entry.Request.PostData)
+	assert.Equal(t, 200, entry.Response.Status)
+	assert.Equal(t, int64(gzipped.Len()), entry.Response.BodySize)
+	assert.Equal(t, "application/json", entry.Response.Content.MimeType)
+	assert.Equal(t, `{"id":"123","name":"foo bar baz"}`, entry.Response.Content.Text)
+	assert.Equal(t, int64(33), entry.Response.Content.Size)
+	assert.Equal(t, "HTTP/1.1", entry.Response.HTTPVersion)
+	assert.Empty(t, entry.Response.Content.Comment)
+
+	// Timings of phases, which have not occurred with the mocked transport, are -1
+	assert.Equal(t, float64(-1), entry.Timings.DNS)
+	assert.Equal(t, float64(-1), entry.Timings.Connect)
+	assert.Equal(t, float64(-1), entry.Timings.SSL)
+	assert.GreaterOrEqual(t, entry.Time, float64(0))
+}
+
+func TestHARRecorder_MaxBodySize(t *testing.T) {
+	t.Parallel()
+
+	// Mocked response
+	transport := httpmock.NewMockTransport()
+	transport.RegisterResponder("PUT", `https://example.com`, httpmock.NewStringResponder(http.StatusOK, "ěščřžýáíé"))
+
+	// Create client
+	recorder := trace.NewHARRecorder(trace.WithMaxBodySize(5))
+	c := client.New().WithTransport(transport).AndTrace(recorder.Factory())
+
+	// Send request
+	var result string
+	_, _, err := request.NewHTTPRequest(c).
+		WithPut("https://example.com").
+		WithFormBody(map[string]string{"key": "value"}).
+		WithResult(&result).
+		Send(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, "ěščřžýáíé", result)
+
+	// Bodies are cut off, a multibyte character is not cut
+	har := recorder.HAR()
+	require.Len(t, har.Log.Entries, 1)
+	entry := har.Log.Entries[0]
+	assert.Equal(t, &trace.HARPostData{
+		MimeType: "application/x-www-form-urlencoded",
+		Text:     "key=v",
+		Comment:  "body truncated to 5 bytes, full size 9 bytes",
+	}, entry.Request.PostData)
+	assert.Equal(t, "ěš", entry.Response.Content.Text)
+	assert.True(t, strings.HasPrefix(entry.Response.Content.Comment, "body truncated to 4 bytes"))
+}
+
+func TestHARRecorder_DefaultRedaction(t *testing.T) {
+	t.Parallel()
+
+	// Mocked response
+	transport := httpmock.NewMockTransport()
+	transport.RegisterResponder("GET", `https://example.com`, httpmock.NewStringResponder(http.StatusOK, "OK"))
+
+	// Create client, without options
+	recorder := trace.NewHARRecorder()
+	c := client.New().WithTransport(transport).AndTrace(recorder.Factory())
+
+	// Send request
+	_, _, err := request.NewHTTPRequest(c).
+		WithGet("https://example.com").
+		AndHeader("X-StorageApi-Token", "secret-token").
+		AndHeader("Authorization", "secret-authorization").
+		Send(context.Background())
+	require.NoError(t, err)
+
+	// The token is masked by default
+	var out bytes.Buffer
+	_, err = recorder.WriteTo(&out)
+	require.NoError(t, err)
+	assert.NotContains(t, out.String(), "secret")
+	har := recorder.HAR()
+	require.Len(t, har.Log.Entries, 1)
+	assert.Contains(t, har.Log.Entries[0].Request.Headers, trace.HARNameValue{Name: "X-Storageapi-Token", Value: "****"})
+}
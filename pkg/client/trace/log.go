@@ -60,8 +60,8 @@ func LogTracer(wr io.Writer) Factory {
 			}
 			t.log(requestID, fmt.Sprintf(`DONE  %s "%s" | %d | send=%vB | received=%vB | %s%s`, req.Method, req.URL.String(), statusCode, send, received, doneTime.Sub(startTime).String(), errorStr))
 		}
-		t.RetryDelay = func(attempt int, delay time.Duration) {
-			t.log(requestID, fmt.Sprintf(`RETRY %s "%s" | %dx | %s`, req.Method, req.URL.String(), attempt, delay))
+		t.RetryDelay = func(attempt int, delay time.Duration, reason RetryDelayReason) {
+			t.log(requestID, fmt.Sprintf(`RETRY %s "%s" | %dx | %s | %s`, req.Method, req.URL.String(), attempt, delay, reason))
 		}
 		t.RequestProcessed = func(result any, err error) {
 			var errorStr string
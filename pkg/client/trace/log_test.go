@@ -42,10 +42,10 @@ func TestLogTracer(t *testing.T) {
 	expected := `
 HTTP_REQUEST[0001] START GET "https://example.com"
 HTTP_REQUEST[0001] DONE  GET "https://example.com" | 423 | send=0B | received=0B | %s
-HTTP_REQUEST[0001] RETRY GET "https://example.com" | 1x | 1ms
+HTTP_REQUEST[0001] RETRY GET "https://example.com" | 1x | 1ms | backoff
 HTTP_REQUEST[0001] START GET "https://example.com"
 HTTP_REQUEST[0001] DONE  GET "https://example.com" | 429 | send=0B | received=0B | %s
-HTTP_REQUEST[0001] RETRY GET "https://example.com" | 2x | 1ms
+HTTP_REQUEST[0001] RETRY GET "https://example.com" | 2x | 1ms | backoff
 HTTP_REQUEST[0001] START GET "https://example.com"
 HTTP_REQUEST[0001] DONE  GET "https://example.com" | 200 | send=0B | received=3B | %s
 HTTP_REQUEST[0001] BODY  GET "https://example.com" | %s
@@ -58,7 +58,7 @@ func newAttributes(cfg config, reqDef request.HTTPRequest) *attributes {
 	{
 		for k, v := range reqDef.RequestHeader() {
 			value := strings.Join(v, ";")
-			if _, found := cfg.redactedHeaders[strings.ToLower(k)]; found {
+			if cfg.redactor.IsRedactedHeader(k) {
 				value = maskedAttrValue
 			}
 			headerAttrs = append(headerAttrs, attribute.String(attrDefHeader+k, value))
@@ -74,7 +74,7 @@ func newAttributes(cfg config, reqDef request.HTTPRequest) *attributes {
 	{
 		for key, values := range reqDef.QueryParams() {
 			value := strings.Join(values, ";")
-			if _, found := cfg.redactedQueryParams[strings.ToLower(key)]; found {
+			if cfg.redactor.IsRedactedQueryParam(key) {
 				value = maskedAttrValue
 			}
 			queryAttrs = append(queryAttrs, attribute.String(attrDefQueryParam+key, value))
@@ -89,7 +89,7 @@ func newAttributes(cfg config, reqDef request.HTTPRequest) *attributes {
 	{
 		for key, value := range reqDef.PathParams() {
 			out.redactedPathValues = append(out.redactedPathValues, value)
-			if _, found := cfg.redactedPathParams[strings.ToLower(key)]; found {
+			if cfg.redactor.IsRedactedPathParam(key) {
 				value = maskedAttrValue
 				reqURL.Path = strings.ReplaceAll(reqURL.Path, value, maskedURLPart)
 			}
@@ -154,7 +154,7 @@ func (v *attributes) SetFromRequest(reqOriginal *http.Request) {
 		for key, values := range query {
 			// Mask redacted params
 			value := strings.Join(values, ";")
-			if _, found := v.config.redactedQueryParams[strings.ToLower(key)]; found {
+			if v.config.redactor.IsRedactedQueryParam(key) {
 				value = maskedAttrValue
 			}
 			queryAttrs = append(queryAttrs, attribute.String(attrQueryParam+key, value))
@@ -183,7 +183,7 @@ func (v *attributes) SetFromRequest(reqOriginal *http.Request) {
 				// Skip, it is already present from httpconv
 				continue
 			}
-			if _, found := v.config.redactedHeaders[key]; found {
+			if v.config.redactor.IsRedactedHeader(key) {
 				value = maskedAttrValue
 			}
 			headerAttrs = append(headerAttrs, attribute.String(attrRequestHeader+key, value))
@@ -230,7 +230,7 @@ func (v *attributes) SetFromResponse(res *http.Response, err error) {
 			for key, values := range res.Header {
 				key = strings.ToLower(key)
 				value := strings.Join(values, ";")
-				if _, found := v.config.redactedHeaders[key]; found {
+				if v.config.redactor.IsRedactedHeader(key) {
 					value = maskedAttrValue
 				}
 				headerAttrs = append(headerAttrs, attribute.String(attrResponseHeader+key, value))
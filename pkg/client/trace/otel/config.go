@@ -1,16 +1,15 @@
 package otel
 
 import (
-	"strings"
-
 	"go.opentelemetry.io/otel/propagation"
+
+	"github.com/keboola/go-client/pkg/request"
 )
 
 type config struct {
-	propagators         propagation.TextMapPropagator
-	redactedPathParams  map[string]struct{}
-	redactedQueryParams map[string]struct{}
-	redactedHeaders     map[string]struct{}
+	propagators   propagation.TextMapPropagator
+	redactOptions []request.RedactOption
+	redactor      request.Redactor
 }
 
 type Option func(*config)
@@ -23,44 +22,29 @@ func WithPropagators(v propagation.TextMapPropagator) Option {
 
 func WithRedactedPathParam(params ...string) Option {
 	return func(c *config) {
-		for _, p := range params {
-			c.redactedPathParams[strings.ToLower(p)] = struct{}{}
-		}
+		c.redactOptions = append(c.redactOptions, request.WithRedactedPathParams(params...))
 	}
 }
 
 func WithRedactedQueryParam(params ...string) Option {
 	return func(c *config) {
-		for _, p := range params {
-			c.redactedQueryParams[strings.ToLower(p)] = struct{}{}
-		}
+		c.redactOptions = append(c.redactOptions, request.WithRedactedQueryParams(params...))
 	}
 }
 
+// WithRedactedHeaders masks values of the headers, in addition to the request.DefaultRedactedHeaders.
 func WithRedactedHeaders(headers ...string) Option {
 	return func(c *config) {
-		for _, h := range headers {
-			c.redactedHeaders[strings.ToLower(h)] = struct{}{}
-		}
+		c.redactOptions = append(c.redactOptions, request.WithRedactedHeaders(headers...))
 	}
 }
 
 func newConfig(opts []Option) config {
-	cfg := config{
-		redactedPathParams:  make(map[string]struct{}),
-		redactedQueryParams: make(map[string]struct{}),
-		// Same as in the otelhttptrace
-		redactedHeaders: map[string]struct{}{
-			"authorization":       {},
-			"www-authenticate":    {},
-			"proxy-authenticate":  {},
-			"proxy-authorization": {},
-			"cookie":              {},
-			"set-cookie":          {},
-		},
-	}
+	cfg := config{}
 	for _, o := range opts {
 		o(&cfg)
 	}
+	// Redaction rules are shared with the request.ToCurl and the trace.SlogTracer
+	cfg.redactor = request.NewRedactor(cfg.redactOptions...)
 	return cfg
 }
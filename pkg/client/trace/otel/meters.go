@@ -19,6 +19,10 @@ type clientMeters struct {
 	duration      otelMetric.Float64Histogram
 	parseInFlight otelMetric.Int64UpDownCounter
 	parseDuration otelMetric.Float64Histogram
+	circuitChange otelMetric.Int64Counter
+	retry         otelMetric.Int64Counter
+	failure       otelMetric.Int64Counter
+	backoff       otelMetric.Float64Histogram
 }
 
 type httpMeters struct {
@@ -35,6 +39,10 @@ func newMeters(meter otelMetric.Meter) *allMeters {
 			duration:      histogram(meter, clientMeterPrefix+"request.duration", "HTTP client: requests duration.", "ms"),
 			parseInFlight: upDownCounter(meter, clientMeterPrefix+"request.parse.in_flight", "HTTP client: in flight request parsing.", ""),
 			parseDuration: histogram(meter, clientMeterPrefix+"request.parse.duration", "HTTP client: request parse duration.", "ms"),
+			circuitChange: counter(meter, clientMeterPrefix+"circuit_breaker.state_change", "HTTP client: circuit breaker state changes.", ""),
+			retry:         counter(meter, clientMeterPrefix+"request.retry", "HTTP client: retry attempts by reason.", ""),
+			failure:       counter(meter, clientMeterPrefix+"request.failure", "HTTP client: failed requests by error class.", ""),
+			backoff:       histogram(meter, clientMeterPrefix+"request.backoff.duration", "HTTP client: total time spent in backoff by a retried request.", "ms"),
 		},
 		http: httpMeters{
 			inFlight:              upDownCounter(meter, httpMeterPrefix+"request.in_flight", "HTTP request: in flight requests.", ""),
@@ -18,8 +18,10 @@
 //   - Main span "keboola.go.http.client.request" wraps all redirects and retries together.
 //   - Span "keboola.go.http.client.request.body.parse" tracks response receiving and parsing (as a stream).
 //   - Span "keboola.go.http.client.retry.delay" tracks delay before retry.
+//   - Event "keboola.go.client.circuit_breaker.state_change" is added to the main span, if the circuit breaker state changes.
 //   - Metrics names start with "keboola.go.http.client" (clientMeterPrefix const).
 //   - For full list of metrics see the clientMeters and parseMeters structs.
+//   - Retries are counted by reason and failed requests by error class, see the retryReason and errorClass functions.
 //
 // [otelhttp]: https://pkg.go.dev/go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp
 // [otelhttptrace]: https://pkg.go.dev/go.opentelemetry.io/contrib/instrumentation/net/http/httptrace/otelhttptrace
@@ -76,6 +78,13 @@ const (
 	clientRequestSpanName    = clientSpanPrefix + "request"
 	clientBodyParseSpanName  = httpSpanPrefix + "request.body.parse"
 	clientRetryDelaySpanName = clientSpanPrefix + "retry.delay"
+	clientCircuitEventName   = clientSpanPrefix + "circuit_breaker.state_change"
+	attrCircuitHost          = attribute.Key("circuit_breaker.host")
+	attrCircuitFrom          = attribute.Key("circuit_breaker.from")
+	attrCircuitTo            = attribute.Key("circuit_breaker.to")
+	attrRetryReason          = attribute.Key("api.request.retry.reason")
+	attrErrorClass           = attribute.Key("api.request.error.class")
+	attrErrorCode            = attribute.Key("api.request.error.code")
 	// Extra attributes for DataDog.
 	attrSpanKind            = attribute.Key("span.kind")
 	attrSpanKindValueClient = "client"
@@ -99,6 +108,11 @@ func NewTrace(tracerProvider otelTrace.TracerProvider, meterProvider otelMetric.
 		attrs := newAttributes(cfg, reqDef)
 		var retryDelaySpan otelTrace.Span
 
+		// Last attempt and retries, for the retry and failure metrics
+		var lastStatusCode int
+		var lastErr error
+		var backoffTotal time.Duration
+
 		// Create root span and metrics, it may contain multiple HTTP requests (redirects, retries, ...).
 		{
 			var rootSpan otelTrace.Span
@@ -127,6 +141,16 @@ func NewTrace(tracerProvider otelTrace.TracerProvider, meterProvider otelMetric.
 				meterAttrs := append(attrs.definition, attrs.httpResponse...)
 				meters.client.inFlight.Add(rootCtx, -1, otelMetric.WithAttributes(attrs.definition...)) // same attributes/dimensions as above (+1)!
 				meters.client.duration.Record(rootCtx, elapsedTime, otelMetric.WithAttributes(meterAttrs...))
+				if backoffTotal > 0 {
+					meters.client.backoff.Record(rootCtx, float64(backoffTotal)/float64(time.Millisecond), otelMetric.WithAttributes(attrs.definition...))
+				}
+				if err != nil {
+					errAttrs := []attribute.KeyValue{attrErrorClass.String(errorClass(lastStatusCode, err))}
+					if code := apiErrorCode(err); code != "" {
+						errAttrs = append(errAttrs, attrErrorCode.String(code))
+					}
+					meters.client.failure.Add(rootCtx, 1, otelMetric.WithAttributes(attrs.definition...), otelMetric.WithAttributes(errAttrs...))
+				}
 
 				// Tracing
 				if rootSpan != nil {
@@ -205,6 +229,11 @@ func NewTrace(tracerProvider otelTrace.TracerProvider, meterProvider otelMetric.
 				)
 			}
 			tc.HTTPResponse = func(res *http.Response, err error) {
+				lastStatusCode = 0
+				if res != nil {
+					lastStatusCode = res.StatusCode
+				}
+				lastErr = err
 				attrs.SetFromResponse(res, err)
 				httpRequestSpan.SetAttributes(attrs.httpResponse...)
 				httpRequestSpan.SetAttributes(attrs.httpResponseExtra...)
@@ -321,7 +350,17 @@ func NewTrace(tracerProvider otelTrace.TracerProvider, meterProvider otelMetric.
 		}
 
 		// Handle retry
-		tc.RetryDelay = func(attempt int, delay time.Duration) {
+		tc.RetryDelay = func(attempt int, delay time.Duration, reason trace.RetryDelayReason) {
+			// Metrics
+			backoffTotal += delay
+			meters.client.retry.Add(
+				rootCtx,
+				1,
+				otelMetric.WithAttributes(attrs.definition...),
+				otelMetric.WithAttributes(attrRetryReason.String(retryReason(lastStatusCode, lastErr))),
+			)
+
+			// Tracing
 			// retryDelaySpan is ended by HTTPRequest hook or RequestProcessed hook (if an error occurred, e.g., request timeout).
 			_, retryDelaySpan = tracer.Start(
 				rootCtx,
@@ -333,10 +372,26 @@ func NewTrace(tracerProvider otelTrace.TracerProvider, meterProvider otelMetric.
 					attribute.Int("api.request.retry.attempt", attempt),
 					attribute.Int64("api.request.retry.delay_ms", delay.Milliseconds()),
 					attribute.String("api.request.retry.delay_string", delay.String()),
+					attribute.String("api.request.retry.delay_reason", string(reason)),
 				),
 			)
 		}
 
+		// Handle circuit breaker state change
+		tc.CircuitStateChange = func(host string, from, to trace.CircuitState) {
+			circuitAttrs := []attribute.KeyValue{
+				attrCircuitHost.String(host),
+				attrCircuitFrom.String(string(from)),
+				attrCircuitTo.String(string(to)),
+			}
+
+			// Metrics
+			meters.client.circuitChange.Add(rootCtx, 1, otelMetric.WithAttributes(circuitAttrs...))
+
+			// Tracing
+			otelTrace.SpanFromContext(rootCtx).AddEvent(clientCircuitEventName, otelTrace.WithAttributes(circuitAttrs...))
+		}
+
 		// Register low-level tracing.
 		// "otelhttptrace" pkg from the opentelemetry-contrib module is buggy, does not end spans:
 		// https://github.com/open-telemetry/opentelemetry-go-contrib/issues/399
@@ -329,11 +329,19 @@ func cleanAndSortMetrics(metrics []metricdata.Metrics) {
 		"423:https://connection.keboola.com/index":                                6,
 		"429:https://connection.keboola.com/index":                                7,
 		"200:https://connection.keboola.com/index":                                8,
+		// Retries, the key contains the retry reason
+		"0:https://connection.keboola.com/{secret1}/redirect1:network": 9,
+		"0:https://connection.keboola.com/{secret1}/redirect1:423":     10,
+		"0:https://connection.keboola.com/{secret1}/redirect1:429":     11,
 	}
 	dataPointKey := func(attrs attribute.Set) string {
 		status, _ := attrs.Value("http.status_code")
 		url, _ := attrs.Value("http.url")
-		return fmt.Sprintf("%d:%s", status.AsInt64(), url.AsString())
+		key := fmt.Sprintf("%d:%s", status.AsInt64(), url.AsString())
+		if reason, found := attrs.Value("api.request.retry.reason"); found {
+			key += ":" + reason.AsString()
+		}
+		return key
 	}
 	dataPointOrder := func(attrs attribute.Set) int {
 		key := dataPointKey(attrs)
@@ -614,6 +622,7 @@ func getExpectedSpans() tracetest.SpanStubs {
 				attribute.Int("api.request.retry.attempt", 1),
 				attribute.Int("api.request.retry.delay_ms", 1),
 				attribute.String("api.request.retry.delay_string", "1ms"),
+				attribute.String("api.request.retry.delay_reason", "backoff"),
 			},
 		},
 		// HTTP Error Code 423
@@ -689,6 +698,7 @@ func getExpectedSpans() tracetest.SpanStubs {
 				attribute.Int("api.request.retry.attempt", 2),
 				attribute.Int("api.request.retry.delay_ms", 2),
 				attribute.String("api.request.retry.delay_string", "2ms"),
+				attribute.String("api.request.retry.delay_reason", "backoff"),
 			},
 		},
 		// HTTP Error Code 429
@@ -764,6 +774,7 @@ func getExpectedSpans() tracetest.SpanStubs {
 				attribute.Int("api.request.retry.attempt", 3),
 				attribute.Int("api.request.retry.delay_ms", 4),
 				attribute.String("api.request.retry.delay_string", "4ms"),
+				attribute.String("api.request.retry.delay_reason", "backoff"),
 			},
 		},
 		// HTTP OK
@@ -847,6 +858,9 @@ func getExpectedMetrics() []metricdata.Metrics {
 		attribute.String("http.url_details.host_suffix", "keboola.com"),
 		attribute.Int("http.status_code", 200),
 	)
+	retryAttrs := func(reason string) attribute.Set {
+		return attribute.NewSet(append(attrsRequestDefinition.ToSlice(), attribute.String("api.request.retry.reason", reason))...)
+	}
 	attrsRedirect1Status301 := attribute.NewSet(
 		attribute.String("http.method", "POST"),
 		attribute.String("http.flavor", "1.1"),
@@ -1012,6 +1026,31 @@ func getExpectedMetrics() []metricdata.Metrics {
 				},
 			},
 		},
+		// Retry metrics
+		{
+			Name:        "keboola.go.client.request.retry",
+			Description: "HTTP client: retry attempts by reason.",
+			Data: metricdata.Sum[int64]{
+				Temporality: 1,
+				IsMonotonic: true, // counter
+				DataPoints: []metricdata.DataPoint[int64]{
+					{Value: 1, Attributes: retryAttrs("network")},
+					{Value: 1, Attributes: retryAttrs("423")},
+					{Value: 1, Attributes: retryAttrs("429")},
+				},
+			},
+		},
+		{
+			Name:        "keboola.go.client.request.backoff.duration",
+			Description: "HTTP client: total time spent in backoff by a retried request.",
+			Unit:        "ms",
+			Data: metricdata.Histogram[float64]{
+				Temporality: 1,
+				DataPoints: []metricdata.HistogramDataPoint[float64]{
+					{Count: 1, Bounds: histBounds, Attributes: attrsRequestDefinition},
+				},
+			},
+		},
 		// Low-level metrics keboola.go.http.request.*
 		{
 			Name:        "keboola.go.http.request.in_flight",
@@ -1105,3 +1144,99 @@ func toSpanID(in uint16) otelTrace.SpanID {
 	binary.BigEndian.PutUint16(tmp, in)
 	return *(*[8]byte)(tmp)
 }
+
+func TestCircuitBreakerStateChange(t *testing.T) {
+	t.Parallel()
+	ctx := t.Context()
+
+	// Mocked response
+	transport := httpmock.NewMockTransport()
+	transport.RegisterResponder("GET", `https://connection.keboola.com/index`, httpmock.NewStringResponder(http.StatusServiceUnavailable, "down"))
+
+	// Setup metrics
+	reader := metric.NewManualReader()
+	meterProvider := metric.NewMeterProvider(metric.WithReader(reader))
+
+	// Create client, the circuit is opened by the first failure
+	c := client.New().
+		WithTransport(transport).
+		WithRetry(client.RetryConfig{}).
+		WithCircuitBreaker(client.NewCircuitBreaker(client.CircuitBreakerConfig{FailureThreshold: 1, CoolDown: time.Hour})).
+		WithTelemetry(nil, meterProvider)
+	_, _, err := request.NewHTTPRequest(c).WithGet("https://connection.keboola.com/index").Send(ctx)
+	assert.Error(t, err)
+
+	// Check metric
+	all := &metricdata.ResourceMetrics{}
+	assert.NoError(t, reader.Collect(ctx, all))
+	var found bool
+	for _, m := range all.ScopeMetrics[0].Metrics {
+		if m.Name != "keboola.go.client.circuit_breaker.state_change" {
+			continue
+		}
+		found = true
+		data := m.Data.(metricdata.Sum[int64])
+		if assert.Len(t, data.DataPoints, 1) {
+			assert.Equal(t, int64(1), data.DataPoints[0].Value)
+			assert.Equal(t, attribute.NewSet(
+				attribute.String("circuit_breaker.host", "connection.keboola.com"),
+				attribute.String("circuit_breaker.from", "closed"),
+				attribute.String("circuit_breaker.to", "open"),
+			), data.DataPoints[0].Attributes)
+		}
+	}
+	assert.True(t, found)
+}
+
+type testAPIError struct {
+	Message string `json:"error"`
+	Code    string `json:"code"`
+}
+
+func (e *testAPIError) Error() string {
+	return e.Message
+}
+
+func (e *testAPIError) ErrorName() string {
+	return e.Code
+}
+
+func TestRequestFailureMetric(t *testing.T) {
+	t.Parallel()
+	ctx := t.Context()
+
+	// Mocked responses
+	transport := httpmock.NewMockTransport()
+	transport.RegisterResponder("GET", `https://connection.keboola.com/api`, httpmock.NewJsonResponderOrPanic(http.StatusBadRequest, map[string]any{"error": "bucket already exists", "code": "storage.buckets.alreadyExists"}))
+	transport.RegisterResponder("GET", `https://connection.keboola.com/down`, httpmock.NewStringResponder(http.StatusServiceUnavailable, "down"))
+
+	// Setup metrics
+	reader := metric.NewManualReader()
+	meterProvider := metric.NewMeterProvider(metric.WithReader(reader))
+
+	// Send requests
+	c := client.New().WithTransport(transport).WithRetry(client.RetryConfig{}).WithTelemetry(nil, meterProvider)
+	_, _, err := request.NewHTTPRequest(c).WithGet("https://connection.keboola.com/api").WithError(&testAPIError{}).Send(ctx)
+	assert.Error(t, err)
+	_, _, err = request.NewHTTPRequest(c).WithGet("https://connection.keboola.com/down").Send(ctx)
+	assert.Error(t, err)
+
+	// Check metric
+	all := &metricdata.ResourceMetrics{}
+	assert.NoError(t, reader.Collect(ctx, all))
+	failures := make(map[string]int64)
+	for _, m := range all.ScopeMetrics[0].Metrics {
+		if m.Name != "keboola.go.client.request.failure" {
+			continue
+		}
+		for _, point := range m.Data.(metricdata.Sum[int64]).DataPoints {
+			class, _ := point.Attributes.Value("api.request.error.class")
+			code, _ := point.Attributes.Value("api.request.error.code")
+			failures[class.AsString()+":"+code.AsString()] += point.Value
+		}
+	}
+	assert.Equal(t, map[string]int64{
+		"http_4xx:storage.buckets.alreadyExists": 1,
+		"http_5xx:":                              1,
+	}, failures)
+}
@@ -2,9 +2,13 @@ package otel
 
 import (
 	"context"
+	"crypto/tls"
+	"crypto/x509"
 	"errors"
 	"net"
 	"net/http"
+	"strconv"
+	"strings"
 )
 
 func errorType(res *http.Response, err error) string {
@@ -30,6 +34,73 @@ func errorType(res *http.Response, err error) string {
 	}
 }
 
+// retryReason returns the cause of a retry for the retry counter: the status code, "timeout" or "network".
+func retryReason(statusCode int, err error) string {
+	var netErr net.Error
+	errors.As(err, &netErr)
+	switch {
+	case statusCode > 0:
+		return strconv.Itoa(statusCode)
+	case errors.Is(err, context.DeadlineExceeded), netErr != nil && netErr.Timeout():
+		return "timeout"
+	default:
+		return "network"
+	}
+}
+
+// errorClass returns the class of a failed request for the failure counter.
+// The statusCode is the status code of the last response, or 0, if no response has been received.
+func errorClass(statusCode int, err error) string {
+	var netErr net.Error
+	var dnsErr *net.DNSError
+	errors.As(err, &netErr)
+	switch {
+	case err == nil:
+		return ""
+	case errors.Is(err, context.Canceled):
+		return "canceled"
+	case errors.As(err, &dnsErr):
+		return "dns"
+	case isTLSError(err):
+		return "tls"
+	case errors.Is(err, context.DeadlineExceeded), netErr != nil && netErr.Timeout():
+		return "timeout"
+	case statusCode >= http.StatusInternalServerError:
+		return "http_5xx"
+	case statusCode >= http.StatusBadRequest:
+		return "http_4xx"
+	case netErr != nil:
+		return "network"
+	default:
+		return "other"
+	}
+}
+
+// apiErrorCode returns the code of an API error, for example keboola.StorageError.ErrCode, if any.
+func apiErrorCode(err error) string {
+	var apiErr interface{ ErrorName() string }
+	if errors.As(err, &apiErr) {
+		return apiErr.ErrorName()
+	}
+	return ""
+}
+
+func isTLSError(err error) bool {
+	var recordErr tls.RecordHeaderError
+	var alertErr tls.AlertError
+	var verificationErr *tls.CertificateVerificationError
+	var authorityErr x509.UnknownAuthorityError
+	var hostnameErr x509.HostnameError
+	var invalidErr x509.CertificateInvalidError
+	return errors.As(err, &recordErr) ||
+		errors.As(err, &alertErr) ||
+		errors.As(err, &verificationErr) ||
+		errors.As(err, &authorityErr) ||
+		errors.As(err, &hostnameErr) ||
+		errors.As(err, &invalidErr) ||
+		strings.Contains(err.Error(), "tls: ") // handshake errors are not typed
+}
+
 func isRedirection(r *http.Response) bool {
 	return r != nil && r.StatusCode >= http.StatusMultipleChoices && r.StatusCode < http.StatusBadRequest
 }
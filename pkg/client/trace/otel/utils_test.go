@@ -2,6 +2,7 @@ package otel
 
 import (
 	"context"
+	"crypto/x509"
 	"errors"
 	"fmt"
 	"net"
@@ -26,6 +27,28 @@ func TestErrorType(t *testing.T) {
 	assert.Equal(t, "", errorType(&http.Response{StatusCode: http.StatusOK}, nil))
 }
 
+func TestRetryReason(t *testing.T) {
+	t.Parallel()
+	assert.Equal(t, "503", retryReason(http.StatusServiceUnavailable, nil))
+	assert.Equal(t, "network", retryReason(0, &net.DNSError{}))
+	assert.Equal(t, "timeout", retryReason(0, &net.DNSError{IsTimeout: true}))
+	assert.Equal(t, "timeout", retryReason(0, fmt.Errorf(`some error: %w`, context.DeadlineExceeded)))
+}
+
+func TestErrorClass(t *testing.T) {
+	t.Parallel()
+	assert.Equal(t, "", errorClass(http.StatusOK, nil))
+	assert.Equal(t, "other", errorClass(http.StatusOK, errors.New("some error")))
+	assert.Equal(t, "canceled", errorClass(0, fmt.Errorf(`some error: %w`, context.Canceled)))
+	assert.Equal(t, "timeout", errorClass(0, fmt.Errorf(`some error: %w`, context.DeadlineExceeded)))
+	assert.Equal(t, "dns", errorClass(0, &net.DNSError{IsTimeout: true}))
+	assert.Equal(t, "tls", errorClass(0, fmt.Errorf(`some error: %w`, x509.UnknownAuthorityError{})))
+	assert.Equal(t, "tls", errorClass(0, errors.New("tls: handshake failure")))
+	assert.Equal(t, "network", errorClass(0, &net.OpError{Op: "dial", Err: errors.New("connection refused")}))
+	assert.Equal(t, "http_4xx", errorClass(http.StatusNotFound, errors.New("some error")))
+	assert.Equal(t, "http_5xx", errorClass(http.StatusBadGateway, errors.New("some error")))
+}
+
 func TestIsRedirection(t *testing.T) {
 	t.Parallel()
 	assert.False(t, isRedirection(nil))
@@ -0,0 +1,204 @@
+package trace
+
+import (
+	"context"
+	"log/slog"
+	"net/http"
+	"net/http/httptrace"
+	"net/url"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/keboola/go-client/pkg/request"
+)
+
+// Option configures the SlogTracer, the HARRecorder and the AuditLog.
+type Option func(c *config)
+
+type config struct {
+	level           slog.Level
+	headers         bool
+	maxBodySize     int
+	redactOptions   []request.RedactOption
+	auditTokenID    func(header http.Header) string
+	auditRedactions []func(record *AuditRecord)
+}
+
+// WithLevel sets level of the SlogTracer records, records of failed requests have always the slog.LevelError, default is slog.LevelDebug.
+func WithLevel(level slog.Level) Option {
+	return func(c *config) {
+		c.level = level
+	}
+}
+
+// WithHeaders adds request and response headers to the SlogTracer records, sensitive values are masked.
+// The HARRecorder always records headers.
+func WithHeaders() Option {
+	return func(c *config) {
+		c.headers = true
+	}
+}
+
+// WithRedactedHeaders masks values of the headers, in addition to the request.DefaultRedactedHeaders.
+func WithRedactedHeaders(headers ...string) Option {
+	return func(c *config) {
+		c.redactOptions = append(c.redactOptions, request.WithRedactedHeaders(headers...))
+	}
+}
+
+// WithRedactedQueryParams masks values of the query parameters.
+func WithRedactedQueryParams(params ...string) Option {
+	return func(c *config) {
+		c.redactOptions = append(c.redactOptions, request.WithRedactedQueryParams(params...))
+	}
+}
+
+// WithRedactedPathParams masks values of the path parameters.
+func WithRedactedPathParams(params ...string) Option {
+	return func(c *config) {
+		c.redactOptions = append(c.redactOptions, request.WithRedactedPathParams(params...))
+	}
+}
+
+// SlogTracer logs structured records of connect, start, retry, done and body parse events.
+//
+// Unlike the DumpTracer, the tracer is safe to use in production,
+// sensitive values, including the X-StorageApi-Token header, are masked by the same rules as in the otel package, see request.Redactor.
+// Bodies are never logged.
+func SlogTracer(logger *slog.Logger, opts ...Option) Factory {
+	cfg := config{level: slog.LevelDebug}
+	for _, o := range opts {
+		o(&cfg)
+	}
+	redactor := request.NewRedactor(cfg.redactOptions...)
+
+	var idGenerator uint64
+	return func(ctx context.Context, reqDef request.HTTPRequest) (context.Context, *ClientTrace) {
+		requestID := atomic.AddUint64(&idGenerator, 1)
+
+		// URL template contains path params placeholders, see request.HTTPRequest.AndPathParam
+		urlTemplate := unescapeURL(redactor.URL(reqDef.URL()).String())
+
+		var req *http.Request
+		var connStartTime time.Time
+		var startTime time.Time
+		var parseStartTime time.Time
+		attempt := 0
+
+		log := func(level slog.Level, msg string, attrs ...slog.Attr) {
+			base := []slog.Attr{slog.Uint64("request_id", requestID)}
+			if req != nil {
+				base = append(base,
+					slog.String("method", req.Method),
+					slog.String("url", unescapeURL(redactedRequestURL(redactor, reqDef, req).String())),
+				)
+			} else {
+				base = append(base, slog.String("method", reqDef.Method()))
+			}
+			base = append(base, slog.String("url_template", urlTemplate))
+			logger.LogAttrs(ctx, level, msg, append(base, attrs...)...)
+		}
+		levelFor := func(err error) slog.Level {
+			if err != nil {
+				return slog.LevelError
+			}
+			return cfg.level
+		}
+
+		t := &ClientTrace{}
+		t.ConnectStart = func(network, addr string) {
+			connStartTime = time.Now()
+		}
+		t.GotConn = func(info httptrace.GotConnInfo) {
+			attrs := []slog.Attr{slog.Bool("conn_reused", info.Reused)}
+			if info.Reused {
+				attrs = append(attrs, slog.Bool("conn_was_idle", info.WasIdle), slog.Duration("conn_idle_time", info.IdleTime))
+			} else if !connStartTime.IsZero() {
+				attrs = append(attrs, slog.Duration("duration", time.Since(connStartTime)))
+			}
+			log(cfg.level, "http request connect", attrs...)
+		}
+		t.HTTPRequestStart = func(r *http.Request) {
+			req = r
+			attempt++
+			startTime = time.Now()
+			attrs := []slog.Attr{slog.Int("attempt", attempt)}
+			if cfg.headers {
+				attrs = append(attrs, headerAttr("request_header", redactor.Header(r.Header)))
+			}
+			log(cfg.level, "http request start", attrs...)
+		}
+		t.HTTPRequestDone = func(r *http.Response, send, received int64, err error) {
+			attrs := []slog.Attr{
+				slog.Int("attempt", attempt),
+				slog.Int64("bytes_sent", send),
+				slog.Int64("bytes_received", received),
+				slog.Duration("duration", time.Since(startTime)),
+			}
+			if r != nil {
+				attrs = append(attrs, slog.Int("status", r.StatusCode))
+				if cfg.headers {
+					attrs = append(attrs, headerAttr("response_header", redactor.Header(r.Header)))
+				}
+			}
+			if err != nil {
+				attrs = append(attrs, slog.String("error", err.Error()))
+			}
+			log(levelFor(err), "http request done", attrs...)
+		}
+		t.RetryDelay = func(retryAttempt int, delay time.Duration, reason RetryDelayReason) {
+			log(cfg.level, "http request retry",
+				slog.Int("attempt", retryAttempt),
+				slog.Duration("delay", delay),
+				slog.String("reason", string(reason)),
+			)
+		}
+		t.BodyParseStart = func(response *http.Response) {
+			parseStartTime = time.Now()
+		}
+		t.BodyParseDone = func(response *http.Response, result any, err error, parseError error) {
+			attrs := []slog.Attr{slog.Duration("duration", time.Since(parseStartTime))}
+			if response != nil {
+				attrs = append(attrs, slog.Int("status", response.StatusCode))
+			}
+			if err != nil {
+				attrs = append(attrs, slog.String("error", err.Error()))
+			}
+			if parseError != nil {
+				attrs = append(attrs, slog.String("parse_error", parseError.Error()))
+				err = parseError
+			}
+			log(levelFor(err), "http request body parsed", attrs...)
+		}
+		return ctx, t
+	}
+}
+
+// redactedRequestURL returns URL of the request with sensitive path and query parameter values masked.
+func redactedRequestURL(redactor request.Redactor, reqDef request.HTTPRequest, req *http.Request) *url.URL {
+	out := redactor.URL(req.URL)
+	for k, v := range reqDef.PathParams() {
+		if v != "" && redactor.IsRedactedPathParam(k) {
+			out.Path = strings.ReplaceAll(out.Path, v, request.RedactedValue)
+			out.RawPath = ""
+		}
+	}
+	return out
+}
+
+// unescapeURL makes the URL more readable in logs, for example path params placeholders are not escaped.
+func unescapeURL(v string) string {
+	if unescaped, err := url.PathUnescape(v); err == nil {
+		return unescaped
+	}
+	return v
+}
+
+func headerAttr(key string, header http.Header) slog.Attr {
+	attrs := make([]any, 0, len(header))
+	for name, values := range header {
+		attrs = append(attrs, slog.String(strings.ToLower(name), strings.Join(values, ";")))
+	}
+	return slog.Group(key, attrs...)
+}
@@ -0,0 +1,87 @@
+package trace_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"log/slog"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/jarcoal/httpmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/keboola/go-client/pkg/client"
+	"github.com/keboola/go-client/pkg/client/trace"
+	"github.com/keboola/go-client/pkg/request"
+)
+
+func TestSlogTracer(t *testing.T) {
+	t.Parallel()
+
+	// Mocked response
+	transport := httpmock.NewMockTransport()
+	transport.RegisterResponder("GET", `=~^https://example.com/branch/123/files/`, httpmock.ResponderFromMultipleResponses([]*http.Response{
+		{StatusCode: http.StatusTooManyRequests, Header: http.Header{"Set-Cookie": []string{"secret"}}},
+		{StatusCode: http.StatusOK, Header: http.Header{"Content-Type": []string{"text/plain"}}, Body: io.NopCloser(strings.NewReader("OK"))},
+	}))
+
+	// Logger, time and durations are removed
+	var out bytes.Buffer
+	logger := slog.New(slog.NewJSONHandler(&out, &slog.HandlerOptions{
+		Level: slog.LevelDebug,
+		ReplaceAttr: func(groups []string, a slog.Attr) slog.Attr {
+			if a.Key == slog.TimeKey || a.Key == "duration" {
+				return slog.Attr{}
+			}
+			return a
+		},
+	}))
+
+	// Create client
+	c := client.New().
+		WithTransport(transport).
+		WithRetry(client.TestingRetry()).
+		AndTrace(trace.SlogTracer(logger, trace.WithHeaders(), trace.WithRedactedPathParams("fileId"), trace.WithRedactedQueryParams("signature")))
+
+	// Send request
+	var result string
+	_, _, err := request.NewHTTPRequest(c).
+		WithGet("https://example.com/branch/{branchId}/files/{fileId}").
+		AndPathParam("branchId", "123").
+		AndPathParam("fileId", "secret-file").
+		AndQueryParam("signature", "secret-signature").
+		AndHeader("X-StorageApi-Token", "secret-token").
+		WithResult(&result).
+		Send(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, "OK", result)
+
+	// Secrets are not logged
+	assert.NotContains(t, out.String(), "secret")
+
+	// Decode records
+	var records []map[string]any
+	for _, line := range strings.Split(strings.TrimSpace(out.String()), "\n") {
+		record := make(map[string]any)
+		require.NoError(t, json.Unmarshal([]byte(line), &record))
+		records = append(records, record)
+	}
+
+	const (
+		url         = "https://example.com/branch/123/files/****?signature=****"
+		urlTemplate = "https://example.com/branch/{branchId}/files/{fileId}"
+	)
+	requestHeader := map[string]any{"x-storageapi-token": "****", "user-agent": "keboola-go-client", "accept-encoding": "gzip, br"}
+	assert.Equal(t, []map[string]any{
+		{"level": "DEBUG", "msg": "http request start", "request_id": 1.0, "method": "GET", "url": url, "url_template": urlTemplate, "attempt": 1.0, "request_header": requestHeader},
+		{"level": "DEBUG", "msg": "http request done", "request_id": 1.0, "method": "GET", "url": url, "url_template": urlTemplate, "attempt": 1.0, "bytes_sent": 0.0, "bytes_received": 0.0, "status": 429.0, "response_header": map[string]any{"set-cookie": "****"}},
+		{"level": "DEBUG", "msg": "http request retry", "request_id": 1.0, "method": "GET", "url": url, "url_template": urlTemplate, "attempt": 1.0, "delay": 1000000.0, "reason": "backoff"},
+		{"level": "DEBUG", "msg": "http request start", "request_id": 1.0, "method": "GET", "url": url, "url_template": urlTemplate, "attempt": 2.0, "request_header": requestHeader},
+		{"level": "DEBUG", "msg": "http request done", "request_id": 1.0, "method": "GET", "url": url, "url_template": urlTemplate, "attempt": 2.0, "bytes_sent": 0.0, "bytes_received": 2.0, "status": 200.0, "response_header": map[string]any{"content-type": "text/plain"}},
+		{"level": "DEBUG", "msg": "http request body parsed", "request_id": 1.0, "method": "GET", "url": url, "url_template": urlTemplate, "status": 200.0},
+	}, records)
+}
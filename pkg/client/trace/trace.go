@@ -15,6 +15,30 @@ import (
 // Factory creates ClientTrace hooks for a request.
 type Factory func(ctx context.Context, request request.HTTPRequest) (context.Context, *ClientTrace)
 
+// RetryDelayReason describes how a retry delay has been determined, see ClientTrace.RetryDelay.
+type RetryDelayReason string
+
+const (
+	// RetryDelayBackoff - the delay is the next exponential backoff interval.
+	RetryDelayBackoff RetryDelayReason = "backoff"
+	// RetryDelayRetryAfter - the delay is defined by the Retry-After response header.
+	RetryDelayRetryAfter RetryDelayReason = "retry-after"
+	// RetryDelayRateLimit - the delay is defined by the X-RateLimit-Reset response header.
+	RetryDelayRateLimit RetryDelayReason = "rate-limit"
+)
+
+// CircuitState is a state of the circuit breaker of a host, see ClientTrace.CircuitStateChange.
+type CircuitState string
+
+const (
+	// CircuitClosed - requests are sent.
+	CircuitClosed CircuitState = "closed"
+	// CircuitOpen - requests are rejected, until the cool-down period elapses.
+	CircuitOpen CircuitState = "open"
+	// CircuitHalfOpen - one probe request is sent, it decides whether the circuit is closed or opened again.
+	CircuitHalfOpen CircuitState = "half-open"
+)
+
 // ClientTrace is a set of hooks to run at various stages of an outgoing HTTPRequest.
 type ClientTrace struct {
 	httptrace.ClientTrace // native, low level trace
@@ -29,7 +53,14 @@ type ClientTrace struct {
 	// It is invoked one or more times, because it includes redirects and retries.
 	HTTPRequestDone func(response *http.Response, send, received int64, err error)
 	// RetryDelay is called before retry delay.
-	RetryDelay func(attempt int, delay time.Duration)
+	// The reason describes how the delay has been determined, for example by the Retry-After header.
+	RetryDelay func(attempt int, delay time.Duration, reason RetryDelayReason)
+	// MutationRetry is called before a retry of a mutating request, for example POST, PUT, PATCH or DELETE.
+	// The previous attempt may have been processed by the server, so the caller may need to reconcile the state.
+	// The response is nil, if the previous attempt failed with an error, its body must not be read.
+	MutationRetry func(request *http.Request, attempt int, response *http.Response, err error)
+	// CircuitStateChange is called when the circuit breaker of the host changes its state, as a result of the request.
+	CircuitStateChange func(host string, from, to CircuitState)
 	// BodyParseStart is called when the body parsing begins.
 	BodyParseStart func(response *http.Response)
 	// BodyParseDone is called when the body parsing completes.
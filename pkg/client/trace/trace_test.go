@@ -15,7 +15,7 @@ import (
 
 	. "github.com/keboola/go-client/pkg/client"
 	. "github.com/keboola/go-client/pkg/client/trace"
-	. "github.com/keboola/go-client/pkg/request"
+	"github.com/keboola/go-client/pkg/request"
 )
 
 func TestTrace(t *testing.T) {
@@ -60,7 +60,7 @@ func TestTrace(t *testing.T) {
 			WaitTimeStart: 1 * time.Microsecond,
 			WaitTimeMax:   20 * time.Microsecond,
 		}).
-		AndTrace(func(ctx context.Context, reqDef HTTPRequest) (context.Context, *ClientTrace) {
+		AndTrace(func(ctx context.Context, reqDef request.HTTPRequest) (context.Context, *ClientTrace) {
 			s := spew.NewDefaultConfig()
 			s.DisablePointerAddresses = true
 			s.DisableCapacities = true
@@ -78,8 +78,8 @@ func TestTrace(t *testing.T) {
 						response.StatusCode, http.StatusText(response.StatusCode), send, received, err),
 					)
 				},
-				RetryDelay: func(attempt int, delay time.Duration) {
-					logs.WriteString(fmt.Sprintf("HttpRequestRetry  attempt=%d delay=%s\n", attempt, delay))
+				RetryDelay: func(attempt int, delay time.Duration, reason RetryDelayReason) {
+					logs.WriteString(fmt.Sprintf("HttpRequestRetry  attempt=%d delay=%s reason=%s\n", attempt, delay, reason))
 				},
 				BodyParseStart: func(response *http.Response) {
 					logs.WriteString("BodyParseStart\n")
@@ -99,10 +99,10 @@ HTTPRequestStart  GET https://example.com/redirect2
 HttpRequestDone   301 Moved Permanently send=0B received=0B err=<nil>
 HTTPRequestStart  GET https://example.com/index
 HttpRequestDone   423 Locked send=0B received=0B err=<nil>
-HttpRequestRetry  attempt=1 delay=1µs
+HttpRequestRetry  attempt=1 delay=1µs reason=backoff
 HTTPRequestStart  GET https://example.com/index
 HttpRequestDone   429 Too Many Requests send=0B received=0B err=<nil>
-HttpRequestRetry  attempt=2 delay=2µs
+HttpRequestRetry  attempt=2 delay=2µs reason=backoff
 HTTPRequestStart  GET https://example.com/index
 BodyParseStart
 HttpRequestDone   200 OK send=0B received=2B err=<nil>
@@ -112,7 +112,7 @@ RequestProcessed  result=(*string)((len=2) "OK") err=<nil>
 
 	// Test
 	str := ""
-	_, result, err := NewHTTPRequest(c).WithPost("https://example.com/redirect1").WithBody("my-body").WithResult(&str).Send(ctx)
+	_, result, err := request.NewHTTPRequest(c).WithPost("https://example.com/redirect1").WithBody("my-body").WithResult(&str).Send(ctx)
 	assert.NoError(t, err)
 	assert.Equal(t, "OK", *result.(*string))
 	assert.Equal(t, strings.TrimLeft(expected, "\n"), logs.String())
@@ -133,7 +133,7 @@ func TestTrace_Multiple(t *testing.T) {
 	c := New().
 		WithTransport(transport).
 		WithRetry(TestingRetry()).
-		AndTrace(func(ctx context.Context, reqDef HTTPRequest) (context.Context, *ClientTrace) {
+		AndTrace(func(ctx context.Context, reqDef request.HTTPRequest) (context.Context, *ClientTrace) {
 			logs.WriteString(fmt.Sprintf("1: GotRequest        %s %s\n", reqDef.Method(), reqDef.URL()))
 			return ctx, &ClientTrace{
 				RequestProcessed: func(result any, err error) {
@@ -153,7 +153,7 @@ func TestTrace_Multiple(t *testing.T) {
 				},
 			}
 		}).
-		AndTrace(func(ctx context.Context, reqDef HTTPRequest) (context.Context, *ClientTrace) {
+		AndTrace(func(ctx context.Context, reqDef request.HTTPRequest) (context.Context, *ClientTrace) {
 			logs.WriteString(fmt.Sprintf("2: GotRequest        %s %s\n", reqDef.Method(), reqDef.URL()))
 			return ctx, &ClientTrace{
 				HTTPRequestStart: func(request *http.Request) {
@@ -167,7 +167,7 @@ func TestTrace_Multiple(t *testing.T) {
 				},
 			}
 		}).
-		AndTrace(func(ctx context.Context, _ HTTPRequest) (context.Context, *ClientTrace) {
+		AndTrace(func(ctx context.Context, _ request.HTTPRequest) (context.Context, *ClientTrace) {
 			return ctx, &ClientTrace{
 				RequestProcessed: func(result any, err error) {
 					s := spew.NewDefaultConfig()
@@ -203,7 +203,7 @@ func TestTrace_Multiple(t *testing.T) {
 
 	// Test
 	str := ""
-	_, result, err := NewHTTPRequest(c).WithGet("https://example.com").WithResult(&str).Send(ctx)
+	_, result, err := request.NewHTTPRequest(c).WithGet("https://example.com").WithResult(&str).Send(ctx)
 	assert.NoError(t, err)
 	assert.Equal(t, "OK", *result.(*string))
 	assert.Equal(t, strings.TrimLeft(expected, "\n"), logs.String())
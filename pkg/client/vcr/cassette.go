@@ -0,0 +1,118 @@
+package vcr
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"unicode/utf8"
+)
+
+// CassetteVersion is the version of the cassette file format, a cassette with another version cannot be loaded.
+const CassetteVersion = 1
+
+const base64Encoding = "base64"
+
+// Cassette is a list of recorded HTTP interactions, it is stored as a JSON file.
+type Cassette struct {
+	Version      int            `json:"version"`
+	Interactions []*Interaction `json:"interactions"`
+}
+
+// Interaction is one recorded request/response pair.
+type Interaction struct {
+	Request  Request  `json:"request"`
+	Response Response `json:"response"`
+}
+
+// Request is a recorded HTTP request.
+// The PathTemplate is the path before the path params are replaced, for example "branch/{branchId}/buckets".
+type Request struct {
+	Method       string      `json:"method"`
+	URL          string      `json:"url"`
+	PathTemplate string      `json:"pathTemplate,omitempty"`
+	Header       http.Header `json:"header,omitempty"`
+	Body         Body        `json:"body,omitzero"`
+}
+
+// Response is a recorded HTTP response, the body is stored decoded, without the Content-Encoding.
+type Response struct {
+	StatusCode int         `json:"statusCode"`
+	Header     http.Header `json:"header,omitempty"`
+	Body       Body        `json:"body,omitzero"`
+}
+
+// Body is a recorded body, a text body is stored as it is, a binary body is encoded by base64.
+type Body struct {
+	Encoding string `json:"encoding,omitempty"`
+	Data     string `json:"data"`
+}
+
+func newBody(data []byte) Body {
+	if utf8.Valid(data) {
+		return Body{Data: string(data)}
+	}
+	return Body{Encoding: base64Encoding, Data: base64.StdEncoding.EncodeToString(data)}
+}
+
+// Bytes returns the decoded body.
+func (b Body) Bytes() ([]byte, error) {
+	switch b.Encoding {
+	case "":
+		return []byte(b.Data), nil
+	case base64Encoding:
+		return base64.StdEncoding.DecodeString(b.Data)
+	default:
+		return nil, fmt.Errorf(`unexpected body encoding "%s"`, b.Encoding)
+	}
+}
+
+// LoadCassette loads the cassette from the file.
+func LoadCassette(path string) (*Cassette, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf(`cannot read cassette "%s": %w`, path, err)
+	}
+
+	cassette := &Cassette{}
+	if err := json.Unmarshal(content, cassette); err != nil {
+		return nil, fmt.Errorf(`cannot decode cassette "%s": %w`, path, err)
+	}
+	if cassette.Version != CassetteVersion {
+		return nil, fmt.Errorf(`cassette "%s" has version %d, expected %d`, path, cassette.Version, CassetteVersion)
+	}
+	return cassette, nil
+}
+
+// Save writes the cassette to the file, the directory is created, if it doesn't exist.
+func (c *Cassette) Save(path string) error {
+	if c.Version == 0 {
+		c.Version = CassetteVersion
+	}
+	if c.Interactions == nil {
+		c.Interactions = []*Interaction{}
+	}
+
+	content, err := json.MarshalIndent(c, "", "  ")
+	if err != nil {
+		return fmt.Errorf(`cannot encode cassette "%s": %w`, path, err)
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return fmt.Errorf(`cannot create cassette dir "%s": %w`, filepath.Dir(path), err)
+	}
+	if err := os.WriteFile(path, append(content, '\n'), 0o644); err != nil { //nolint:gosec // the cassette is a test fixture
+		return fmt.Errorf(`cannot write cassette "%s": %w`, path, err)
+	}
+	return nil
+}
+
+func cassetteExists(path string) (bool, error) {
+	_, err := os.Stat(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return false, nil
+	}
+	return err == nil, err
+}
@@ -0,0 +1,316 @@
+// Package vcr provides recording and replaying of HTTP interactions for deterministic offline tests.
+//
+// In the ModeRecord, requests are sent by the real transport and the request/response pairs are written to a cassette file.
+// In the ModeReplay, responses are served from the cassette, no request leaves the process.
+// The ModeAuto replays the cassette if it exists, otherwise it records a new one.
+//
+// Sensitive headers are redacted before they are written to the cassette, by the same rules as in the trace package,
+// see WithRedactedHeaders and request.Redactor.
+//
+// Example:
+//
+//	rec, err := vcr.New("testdata/cassette.json", vcr.ModeAuto)
+//	...
+//	defer rec.Save()
+//	c := rec.Client(client.NewTestClient())
+package vcr
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/keboola/go-client/pkg/client"
+	"github.com/keboola/go-client/pkg/client/decode"
+	"github.com/keboola/go-client/pkg/request"
+)
+
+// Mode of the Recorder.
+type Mode int
+
+const (
+	// ModeAuto replays the cassette, if it exists, otherwise it records a new one.
+	ModeAuto Mode = iota
+	// ModeRecord sends requests by the real transport and records them.
+	ModeRecord
+	// ModeReplay serves responses from the cassette.
+	ModeReplay
+)
+
+// Match defines which parts of the request must be equal to the recorded request, in the ModeReplay.
+// The MatchBody ignores the random boundary of a multipart body.
+type Match int
+
+const (
+	MatchMethod Match = 1 << iota
+	MatchPath         // the path template, if it is known, otherwise the URL path
+	MatchQuery
+	MatchBody
+	MatchAll = MatchMethod | MatchPath | MatchQuery | MatchBody
+)
+
+type contextKey string
+
+const pathTemplateContextKey = contextKey("vcrPathTemplate")
+
+// boundaryPlaceholder replaces the random boundary of a multipart body, when the bodies are matched.
+const boundaryPlaceholder = "<boundary>"
+
+type config struct {
+	match         Match
+	transport     http.RoundTripper
+	redactOptions []request.RedactOption
+}
+
+type Option func(c *config)
+
+// WithMatch sets which parts of the request are matched in the ModeReplay, default is MatchAll.
+func WithMatch(v Match) Option {
+	return func(c *config) {
+		c.match = v
+	}
+}
+
+// WithTransport sets the real transport used in the ModeRecord, default is client.DefaultTransport.
+func WithTransport(v http.RoundTripper) Option {
+	return func(c *config) {
+		c.transport = v
+	}
+}
+
+// WithRedactedHeaders adds request and response headers, which values are masked in the cassette,
+// in addition to the request.DefaultRedactedHeaders.
+func WithRedactedHeaders(headers ...string) Option {
+	return func(c *config) {
+		c.redactOptions = append(c.redactOptions, request.WithRedactedHeaders(headers...))
+	}
+}
+
+// Recorder records or replays HTTP interactions, it implements the http.RoundTripper interface.
+// Use the Recorder.Client method to set the Recorder to a client.Client.
+type Recorder struct {
+	config   config
+	redactor request.Redactor
+	path     string
+	mode     Mode
+	lock     sync.Mutex
+	cassette *Cassette
+	used     []bool // used interactions in the ModeReplay
+}
+
+// New creates a Recorder of the cassette file.
+// In the ModeReplay, the cassette must exist, in the ModeAuto, the mode is determined by existence of the cassette.
+func New(path string, mode Mode, opts ...Option) (*Recorder, error) {
+	cfg := config{match: MatchAll}
+	for _, o := range opts {
+		o(&cfg)
+	}
+	if cfg.transport == nil {
+		cfg.transport = client.DefaultTransport()
+	}
+
+	if mode == ModeAuto {
+		if exists, err := cassetteExists(path); err != nil {
+			return nil, fmt.Errorf(`cannot check cassette "%s": %w`, path, err)
+		} else if exists {
+			mode = ModeReplay
+		} else {
+			mode = ModeRecord
+		}
+	}
+
+	r := &Recorder{config: cfg, redactor: request.NewRedactor(cfg.redactOptions...), path: path, mode: mode}
+	if mode == ModeReplay {
+		cassette, err := LoadCassette(path)
+		if err != nil {
+			return nil, err
+		}
+		r.cassette = cassette
+		r.used = make([]bool, len(cassette.Interactions))
+	} else {
+		r.cassette = &Cassette{Version: CassetteVersion}
+	}
+	return r, nil
+}
+
+// Mode returns the actual mode, ModeRecord or ModeReplay.
+func (r *Recorder) Mode() Mode {
+	return r.mode
+}
+
+// Client returns a clone of the client with the Recorder set as the transport.
+// A middleware is added to the client, to record the path template of each request, see MatchPath.
+func (r *Recorder) Client(c client.Client) client.Client {
+	return c.WithTransport(r).WithMiddleware(r.Middleware())
+}
+
+// Middleware stores the path template of the request to the context, so it can be recorded by the Recorder.
+func (r *Recorder) Middleware() request.Middleware {
+	return func(next request.Sender) request.Sender {
+		return request.SenderFunc(func(ctx context.Context, req request.HTTPRequest) (*http.Response, any, error) {
+			return next.Send(context.WithValue(ctx, pathTemplateContextKey, req.URL().Path), req)
+		})
+	}
+}
+
+// Save writes the recorded interactions to the cassette file, it does nothing in the ModeReplay.
+func (r *Recorder) Save() error {
+	if r.mode != ModeRecord {
+		return nil
+	}
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	return r.cassette.Save(r.path)
+}
+
+func (r *Recorder) RoundTrip(req *http.Request) (*http.Response, error) {
+	// Read request body, it is recorded or matched
+	var reqBody []byte
+	if req.Body != nil && req.Body != http.NoBody {
+		var err error
+		reqBody, err = io.ReadAll(req.Body)
+		_ = req.Body.Close()
+		if err != nil {
+			return nil, fmt.Errorf("cannot read request body: %w", err)
+		}
+		req.Body = io.NopCloser(bytes.NewReader(reqBody))
+	}
+
+	recorded := Request{
+		Method:       req.Method,
+		URL:          req.URL.String(),
+		PathTemplate: pathTemplate(req.Context()),
+		Header:       r.redactor.Header(req.Header),
+		Body:         newBody(reqBody),
+	}
+
+	if r.mode == ModeReplay {
+		return r.replay(req, recorded)
+	}
+	return r.record(req, recorded)
+}
+
+func (r *Recorder) record(req *http.Request, recorded Request) (*http.Response, error) {
+	res, err := r.config.transport.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+
+	// Read and decode the response body, so the cassette is readable
+	body, err := decode.Decode(res.Body, res.Header.Get("Content-Encoding"))
+	if err != nil {
+		_ = res.Body.Close()
+		return nil, err
+	}
+	resBody, err := io.ReadAll(body)
+	_ = res.Body.Close()
+	if err != nil {
+		return nil, fmt.Errorf("cannot read response body: %w", err)
+	}
+	res.Header.Del("Content-Encoding")
+	res.Header.Del("Content-Length")
+	res.ContentLength = int64(len(resBody))
+	res.Uncompressed = true
+	res.Body = io.NopCloser(bytes.NewReader(resBody))
+
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	r.cassette.Interactions = append(r.cassette.Interactions, &Interaction{
+		Request:  recorded,
+		Response: Response{StatusCode: res.StatusCode, Header: r.redactor.Header(res.Header), Body: newBody(resBody)},
+	})
+	return res, nil
+}
+
+func (r *Recorder) replay(req *http.Request, actual Request) (*http.Response, error) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	// Find the first unused matching interaction
+	for i, interaction := range r.cassette.Interactions {
+		if r.used[i] || !r.matches(interaction.Request, actual) {
+			continue
+		}
+		r.used[i] = true
+
+		body, err := interaction.Response.Body.Bytes()
+		if err != nil {
+			return nil, fmt.Errorf(`cannot replay request %s "%s": %w`, req.Method, req.URL.String(), err)
+		}
+		return &http.Response{
+			Status:        strconv.Itoa(interaction.Response.StatusCode) + " " + http.StatusText(interaction.Response.StatusCode),
+			StatusCode:    interaction.Response.StatusCode,
+			Proto:         "HTTP/1.1",
+			ProtoMajor:    1,
+			ProtoMinor:    1,
+			Header:        interaction.Response.Header.Clone(),
+			Body:          io.NopCloser(bytes.NewReader(body)),
+			ContentLength: int64(len(body)),
+			Request:       req,
+		}, nil
+	}
+
+	return nil, fmt.Errorf(`no recorded interaction found for request %s "%s"`, req.Method, req.URL.String())
+}
+
+func (r *Recorder) matches(recorded, actual Request) bool {
+	if r.config.match&MatchMethod != 0 && recorded.Method != actual.Method {
+		return false
+	}
+	recordedURL, err := url.Parse(recorded.URL)
+	if err != nil {
+		return false
+	}
+	actualURL, err := url.Parse(actual.URL)
+	if err != nil {
+		return false
+	}
+	if r.config.match&MatchPath != 0 {
+		if recorded.PathTemplate != "" && actual.PathTemplate != "" {
+			if recorded.PathTemplate != actual.PathTemplate {
+				return false
+			}
+		} else if recordedURL.Path != actualURL.Path {
+			return false
+		}
+	}
+	if r.config.match&MatchQuery != 0 && recordedURL.Query().Encode() != actualURL.Query().Encode() {
+		return false
+	}
+	if r.config.match&MatchBody != 0 {
+		recordedBody, err := matchedBody(recorded)
+		if err != nil {
+			return false
+		}
+		actualBody, err := matchedBody(actual)
+		if err != nil || !bytes.Equal(recordedBody, actualBody) {
+			return false
+		}
+	}
+	return true
+}
+
+// matchedBody returns the request body for the MatchBody, the random boundary of a multipart body is replaced by a placeholder.
+func matchedBody(req Request) ([]byte, error) {
+	body, err := req.Body.Bytes()
+	if err != nil {
+		return nil, err
+	}
+	mediaType, params, err := mime.ParseMediaType(req.Header.Get("Content-Type"))
+	if err == nil && strings.HasPrefix(mediaType, "multipart/") && params["boundary"] != "" {
+		body = bytes.ReplaceAll(body, []byte(params["boundary"]), []byte(boundaryPlaceholder))
+	}
+	return body, nil
+}
+
+func pathTemplate(ctx context.Context) string {
+	v, _ := ctx.Value(pathTemplateContextKey).(string)
+	return v
+}
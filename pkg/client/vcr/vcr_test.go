@@ -0,0 +1,140 @@
+package vcr_test
+
+import (
+	"context"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/jarcoal/httpmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/keboola/go-client/pkg/client"
+	"github.com/keboola/go-client/pkg/client/vcr"
+	"github.com/keboola/go-client/pkg/request"
+)
+
+func TestRecorder_RecordReplay(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	path := filepath.Join(t.TempDir(), "cassette.json")
+
+	// Mocked real API
+	transport := httpmock.NewMockTransport()
+	transport.RegisterResponder(http.MethodGet, `https://connection.keboola.mock/v2/storage/branch/123/buckets`, httpmock.NewJsonResponderOrPanic(http.StatusOK, []map[string]any{{"id": "in.c-bucket"}}))
+	transport.RegisterResponder(http.MethodPost, `https://connection.keboola.mock/v2/storage/branch/123/buckets`, httpmock.NewJsonResponderOrPanic(http.StatusCreated, map[string]any{"id": "in.c-new"}))
+
+	// Record
+	rec, err := vcr.New(path, vcr.ModeAuto, vcr.WithTransport(transport))
+	require.NoError(t, err)
+	assert.Equal(t, vcr.ModeRecord, rec.Mode())
+	c := rec.Client(client.New().WithBaseURL("https://connection.keboola.mock/v2/storage").WithHeader("X-StorageApi-Token", "my-secret"))
+	sendRequests(t, ctx, c)
+	require.NoError(t, rec.Save())
+
+	// Sensitive header is redacted
+	content, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.NotContains(t, string(content), "my-secret")
+	assert.Contains(t, string(content), `"pathTemplate": "branch/{branchId}/buckets"`)
+
+	// Replay, the real API is not called
+	rec, err = vcr.New(path, vcr.ModeAuto)
+	require.NoError(t, err)
+	assert.Equal(t, vcr.ModeReplay, rec.Mode())
+	c = rec.Client(client.New().WithBaseURL("https://connection.keboola.mock/v2/storage").WithRetry(client.TestingRetry()))
+	sendRequests(t, ctx, c)
+	assert.Equal(t, 2, transport.GetTotalCallCount())
+
+	// Each interaction is replayed only once
+	_, _, err = request.NewHTTPRequest(c).WithGet("branch/{branchId}/buckets").AndPathParam("branchId", "123").Send(ctx)
+	if assert.Error(t, err) {
+		assert.Contains(t, err.Error(), `no recorded interaction found for request GET "https://connection.keboola.mock/v2/storage/branch/123/buckets"`)
+	}
+}
+
+func TestRecorder_Match(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	path := filepath.Join(t.TempDir(), "cassette.json")
+
+	// Record
+	transport := httpmock.NewMockTransport()
+	transport.RegisterResponder(http.MethodPost, `https://example.com/foo`, httpmock.NewStringResponder(http.StatusOK, "OK"))
+	rec, err := vcr.New(path, vcr.ModeRecord, vcr.WithTransport(transport))
+	require.NoError(t, err)
+	_, _, err = request.NewHTTPRequest(rec.Client(client.New())).WithPost("https://example.com/foo").AndQueryParam("a", "1").WithJSONBody(map[string]any{"key": "value"}).Send(ctx)
+	require.NoError(t, err)
+	require.NoError(t, rec.Save())
+
+	// The body doesn't match
+	rec, err = vcr.New(path, vcr.ModeReplay)
+	require.NoError(t, err)
+	c := rec.Client(client.New().WithRetry(client.TestingRetry()))
+	_, _, err = request.NewHTTPRequest(c).WithPost("https://example.com/foo").AndQueryParam("a", "1").WithJSONBody(map[string]any{"key": "other"}).Send(ctx)
+	assert.Error(t, err)
+
+	// The body and the query are ignored
+	rec, err = vcr.New(path, vcr.ModeReplay, vcr.WithMatch(vcr.MatchMethod|vcr.MatchPath))
+	require.NoError(t, err)
+	c = rec.Client(client.New().WithRetry(client.TestingRetry()))
+	var out string
+	_, _, err = request.NewHTTPRequest(c).WithPost("https://example.com/foo").WithResult(&out).WithJSONBody(map[string]any{"key": "other"}).Send(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, "OK", out)
+}
+
+func TestRecorder_ReplayMultipart(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	path := filepath.Join(t.TempDir(), "cassette.json")
+	send := func(c client.Client, content string) error {
+		file := request.MultipartFile{FieldName: "data", FileName: "data.csv", Reader: strings.NewReader(content)}
+		return request.NewHTTPRequest(c).WithPost("https://example.com/upload").WithMultipartBody(map[string]string{"name": "foo"}, file).SendOrErr(ctx)
+	}
+
+	// Record
+	transport := httpmock.NewMockTransport()
+	transport.RegisterResponder(http.MethodPost, `https://example.com/upload`, httpmock.NewStringResponder(http.StatusOK, "OK"))
+	rec, err := vcr.New(path, vcr.ModeRecord, vcr.WithTransport(transport))
+	require.NoError(t, err)
+	require.NoError(t, send(rec.Client(client.New()), "a,b\n"))
+	require.NoError(t, rec.Save())
+
+	// Replay, each multipart body has a new random boundary
+	rec, err = vcr.New(path, vcr.ModeReplay)
+	require.NoError(t, err)
+	c := rec.Client(client.New().WithRetry(client.TestingRetry()))
+	assert.Error(t, send(c, "c,d\n"))
+	require.NoError(t, send(c, "a,b\n"))
+	assert.Equal(t, 1, transport.GetTotalCallCount())
+}
+
+func TestRecorder_ReplayMissingCassette(t *testing.T) {
+	t.Parallel()
+
+	_, err := vcr.New(filepath.Join(t.TempDir(), "missing.json"), vcr.ModeReplay)
+	if assert.Error(t, err) {
+		assert.Contains(t, err.Error(), "cannot read cassette")
+	}
+}
+
+func sendRequests(t *testing.T, ctx context.Context, c client.Client) {
+	t.Helper()
+
+	var list []map[string]any
+	_, _, err := request.NewHTTPRequest(c).WithGet("branch/{branchId}/buckets").AndPathParam("branchId", "123").WithResult(&list).Send(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, []map[string]any{{"id": "in.c-bucket"}}, list)
+
+	created := map[string]any{}
+	_, _, err = request.NewHTTPRequest(c).WithPost("branch/{branchId}/buckets").AndPathParam("branchId", "123").WithJSONBody(map[string]any{"name": "new"}).WithResult(&created).Send(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, map[string]any{"id": "in.c-new"}, created)
+}
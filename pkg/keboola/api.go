@@ -45,13 +45,13 @@ type AuthorizedAPI struct {
 
 func APIIndex(ctx context.Context, host string, opts ...APIOption) (*Index, error) {
 	cfg := newAPIConfig(opts)
-	c := newClient(host, cfg)
+	c := newSender(host, cfg)
 	return newPublicAPI(c, nil).IndexRequest().Send(ctx)
 }
 
 func APIIndexWithComponents(ctx context.Context, host string, opts ...APIOption) (*IndexComponents, error) {
 	cfg := newAPIConfig(opts)
-	c := newClient(host, cfg)
+	c := newSender(host, cfg)
 	return newPublicAPI(c, nil).IndexComponentsRequest().Send(ctx)
 }
 
@@ -80,7 +80,7 @@ func NewPublicAPI(ctx context.Context, host string, opts ...APIOption) (*PublicA
 
 func NewPublicAPIFromIndex(host string, index *Index, opts ...APIOption) *PublicAPI {
 	cfg := newAPIConfig(opts)
-	c := newClient(host, cfg)
+	c := newSender(host, cfg)
 	return newPublicAPI(c, index)
 }
 
@@ -88,6 +88,23 @@ func newPublicAPI(sender request.Sender, index *Index) *PublicAPI {
 	return &PublicAPI{sender: sender, index: index}
 }
 
+// newSender creates the client, requests are throttled by the rate limiter and deduplicated by the deduplicator, if any.
+func newSender(host string, cfg apiConfig) request.Sender {
+	var sender request.Sender = newClient(host, cfg)
+	if cfg.plan != nil {
+		// The plan wraps the client directly, to resolve relative URLs
+		sender = cfg.plan.Sender(sender)
+	}
+	if cfg.rateLimiter != nil {
+		sender = cfg.rateLimiter.Sender(sender)
+	}
+	if cfg.deduplicator != nil {
+		// Deduplicated requests don't consume the rate limit
+		sender = cfg.deduplicator.Sender(sender)
+	}
+	return sender
+}
+
 func newClient(host string, cfg apiConfig) client.Client {
 	if host == "" {
 		panic(errors.New("host must be specified"))
@@ -108,6 +125,16 @@ func newClient(host string, cfg apiConfig) client.Client {
 	// Set host
 	c = c.WithBaseURL(host)
 
+	// Add middlewares
+	if len(cfg.middlewares) > 0 {
+		c = c.WithMiddleware(cfg.middlewares...)
+	}
+
+	// Set response cache
+	if cfg.cache != nil {
+		c = c.WithCache(cfg.cache)
+	}
+
 	// Enable telemetry
 	if cfg.tracerProvider != nil || cfg.meterProvider != nil {
 		c = c.WithTelemetry(cfg.tracerProvider, cfg.meterProvider, otel.WithRedactedHeaders(storageAPITokenHeader))
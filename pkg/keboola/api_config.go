@@ -7,6 +7,8 @@ import (
 	otelTrace "go.opentelemetry.io/otel/trace"
 
 	"github.com/keboola/go-client/pkg/client"
+	"github.com/keboola/go-client/pkg/client/cache"
+	"github.com/keboola/go-client/pkg/request"
 )
 
 type apiConfig struct {
@@ -14,6 +16,11 @@ type apiConfig struct {
 	onSuccessTimeout time.Duration
 	tracerProvider   otelTrace.TracerProvider
 	meterProvider    otelMetric.MeterProvider
+	rateLimiter      *request.RateLimiter
+	deduplicator     *request.Deduplicator
+	plan             *request.Plan
+	middlewares      []request.Middleware
+	cache            cache.Store
 }
 
 type APIOption func(c *apiConfig)
@@ -49,3 +56,46 @@ func WithMeterProvider(v otelMetric.MeterProvider) APIOption {
 		c.meterProvider = v
 	}
 }
+
+// WithMiddleware adds the middlewares to the client, see client.Client.WithMiddleware.
+func WithMiddleware(middlewares ...request.Middleware) APIOption {
+	return func(c *apiConfig) {
+		c.middlewares = append(c.middlewares, middlewares...)
+	}
+}
+
+// WithCache caches responses of the index requests in the store, see client.Client.WithCache.
+// For example, a cache.DiskStore can be used to share the index between short-lived CLI runs.
+func WithCache(store cache.Store) APIOption {
+	return func(c *apiConfig) {
+		c.cache = store
+	}
+}
+
+// WithRateLimiter throttles all API requests by the limiter.
+// The limiter can be shared by multiple API instances, see ServiceRateLimit for per-service limits.
+func WithRateLimiter(v *request.RateLimiter) APIOption {
+	return func(c *apiConfig) {
+		c.rateLimiter = v
+	}
+}
+
+// WithDeduplicator collapses concurrent identical GET requests into one, see request.Deduplicator.
+func WithDeduplicator(v *request.Deduplicator) APIOption {
+	return func(c *apiConfig) {
+		c.deduplicator = v
+	}
+}
+
+// WithPlan enables the dry run, mutating requests are only recorded to the plan, see request.Plan.
+// GET requests are sent, so flows such as CleanProjectRequest can be previewed before running.
+func WithPlan(v *request.Plan) APIOption {
+	return func(c *apiConfig) {
+		c.plan = v
+	}
+}
+
+// ServiceRateLimit sets the rate limit of the service, for example, a separate budget for the QueueAPI and the StorageAPI.
+func ServiceRateLimit(s ServiceType, limit request.RateLimit) request.RateLimiterOption {
+	return request.WithServiceRateLimit(string(s), limit)
+}
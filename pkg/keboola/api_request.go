@@ -15,7 +15,7 @@ func (a *AuthorizedAPI) newRequest(s ServiceType) request.HTTPRequest {
 // newRequest Creates request, sets base URL and default error type.
 func (a *PublicAPI) newRequest(s ServiceType) request.HTTPRequest {
 	// Set request base URL according to the ServiceType
-	r := request.NewHTTPRequest(a.sender).WithBaseURL(a.baseURLForService(s))
+	r := request.NewHTTPRequest(a.sender).WithBaseURL(a.baseURLForService(s)).WithService(string(s))
 
 	// Set error schema
 	switch s {
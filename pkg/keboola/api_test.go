@@ -2,15 +2,21 @@ package keboola_test
 
 import (
 	"context"
+	"errors"
 	"net/http"
+	"strconv"
+	"strings"
 	"testing"
 	"time"
 
 	"github.com/jarcoal/httpmock"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 
 	"github.com/keboola/go-client/pkg/client"
+	"github.com/keboola/go-client/pkg/client/cache"
 	"github.com/keboola/go-client/pkg/keboola"
+	"github.com/keboola/go-client/pkg/request"
 )
 
 func TestNewAPI_WithoutIndex(t *testing.T) {
@@ -90,3 +96,356 @@ func mockedClient() (client.Client, *httpmock.MockTransport) {
 	}))
 	return c, transport
 }
+
+func TestAPI_WithRateLimiter(t *testing.T) {
+	t.Parallel()
+
+	// Setup, only the queue API is limited
+	c, transport := mockedClient()
+	transport.RegisterResponder(http.MethodGet, `=~^https://queue.keboola.mock/`, httpmock.NewStringResponder(http.StatusOK, "{}"))
+	transport.RegisterResponder(http.MethodGet, "/v2/storage/branch/123/buckets", httpmock.NewStringResponder(http.StatusOK, "[]"))
+	limiter := request.NewRateLimiter(keboola.ServiceRateLimit(keboola.QueueAPI, request.RateLimit{Rate: 0.1, Burst: 1}))
+	ctx := context.Background()
+	api, err := keboola.NewAuthorizedAPI(ctx, "https://connection.keboola.mock", "my-token", keboola.WithClient(&c), keboola.WithRateLimiter(limiter))
+	require.NoError(t, err)
+
+	// The first queue request consumes the burst, the next one must wait, longer than the context deadline
+	ctxTimeout, cancel := context.WithTimeout(ctx, 50*time.Millisecond)
+	defer cancel()
+	require.NoError(t, api.GetQueueJobRequest(keboola.JobKey{ID: "123"}).SendOrErr(ctxTimeout))
+	err = api.GetQueueJobRequest(keboola.JobKey{ID: "456"}).SendOrErr(ctxTimeout)
+	if assert.Error(t, err) {
+		assert.Contains(t, err.Error(), `rate limit of the service "queue"`)
+	}
+
+	// The storage API has own budget
+	require.NoError(t, api.ListBucketsRequest(123).SendOrErr(ctxTimeout))
+	require.NoError(t, api.ListBucketsRequest(123).SendOrErr(ctxTimeout))
+}
+
+func TestAPI_WithMiddleware(t *testing.T) {
+	t.Parallel()
+
+	// Setup, the middleware injects a header
+	c, transport := mockedClient()
+	transport.RegisterResponder(http.MethodGet, "/v2/storage/branch/123/buckets", func(req *http.Request) (*http.Response, error) {
+		assert.Equal(t, "my-value", req.Header.Get("X-Foo"))
+		return httpmock.NewStringResponse(http.StatusOK, "[]"), nil
+	})
+	middleware := func(next request.Sender) request.Sender {
+		return request.SenderFunc(func(ctx context.Context, req request.HTTPRequest) (*http.Response, any, error) {
+			return next.Send(ctx, req.AndHeader("X-Foo", "my-value"))
+		})
+	}
+	ctx := context.Background()
+	api, err := keboola.NewAuthorizedAPI(ctx, "https://connection.keboola.mock", "my-token", keboola.WithClient(&c), keboola.WithMiddleware(middleware))
+	require.NoError(t, err)
+
+	require.NoError(t, api.ListBucketsRequest(123).SendOrErr(ctx))
+	assert.Equal(t, 1, transport.GetCallCountInfo()["GET /v2/storage/branch/123/buckets"])
+}
+
+func TestAPI_WithCache(t *testing.T) {
+	t.Parallel()
+
+	// Setup, the store is shared by both API instances
+	c, transport := mockedClient()
+	store := cache.NewMemoryStore()
+	ctx := context.Background()
+
+	// The index is loaded only once
+	for range 2 {
+		api, err := keboola.NewPublicAPI(ctx, "https://connection.keboola.mock", keboola.WithClient(&c), keboola.WithCache(store))
+		require.NoError(t, err)
+		assert.Equal(t, keboola.Features{"dynamic-backend-size"}, api.Index().Features)
+	}
+	assert.Equal(t, 1, transport.GetCallCountInfo()["GET /v2/storage/?exclude=components"])
+}
+
+func TestAPI_StreamTables(t *testing.T) {
+	t.Parallel()
+
+	// Setup
+	c, transport := mockedClient()
+	transport.RegisterResponder(http.MethodGet, "/v2/storage/branch/123/tables", httpmock.NewJsonResponderOrPanic(http.StatusOK, []map[string]any{
+		{"id": "in.c-bucket.table1", "name": "table1"},
+		{"id": "in.c-bucket.table2", "name": "table2"},
+	}))
+	ctx := context.Background()
+	api, err := keboola.NewAuthorizedAPI(ctx, "https://connection.keboola.mock", "my-token", keboola.WithClient(&c))
+	require.NoError(t, err)
+
+	// Tables are passed to the callback one by one
+	var names []string
+	err = api.StreamTablesRequest(123, func(table *keboola.Table) error {
+		assert.Equal(t, keboola.BranchID(123), table.BranchID)
+		names = append(names, table.Name)
+		return nil
+	}).SendOrErr(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"table1", "table2"}, names)
+}
+
+func TestAPI_StreamConfigsAndRows(t *testing.T) {
+	t.Parallel()
+
+	// Setup
+	c, transport := mockedClient()
+	transport.RegisterResponder(http.MethodGet, "/v2/storage/branch/123/components?include=configuration%2Crows", httpmock.NewJsonResponderOrPanic(http.StatusOK, []map[string]any{
+		{"id": "foo.bar", "configurations": []map[string]any{{"id": "456", "rows": []map[string]any{{"id": "789"}}}}},
+		{"id": "foo.baz"},
+	}))
+	ctx := context.Background()
+	api, err := keboola.NewAuthorizedAPI(ctx, "https://connection.keboola.mock", "my-token", keboola.WithClient(&c))
+	require.NoError(t, err)
+
+	// Components are passed to the callback one by one, with the missing values
+	var ids []keboola.ComponentID
+	err = api.StreamConfigsAndRowsFrom(keboola.BranchKey{ID: 123}, func(component *keboola.ComponentWithConfigs) error {
+		assert.Equal(t, keboola.BranchID(123), component.BranchID)
+		for _, config := range component.Configs {
+			assert.Equal(t, keboola.ConfigKey{BranchID: 123, ComponentID: "foo.bar", ID: "456"}, config.ConfigKey)
+			assert.Equal(t, keboola.ConfigID("456"), config.Rows[0].ConfigID)
+		}
+		ids = append(ids, component.ID)
+		return nil
+	}).SendOrErr(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, []keboola.ComponentID{"foo.bar", "foo.baz"}, ids)
+}
+
+func TestAPI_StreamIndexComponents(t *testing.T) {
+	t.Parallel()
+
+	// Setup
+	c, transport := mockedClient()
+	transport.RegisterResponder(http.MethodGet, "/v2/storage/", httpmock.NewJsonResponderOrPanic(http.StatusOK, map[string]any{
+		"api":        "storage",
+		"services":   []map[string]any{{"id": "queue", "url": "https://queue.keboola.mock"}},
+		"features":   []string{"foo"},
+		"components": []map[string]any{{"id": "foo.bar", "name": "Foo Bar"}, {"id": "foo.baz", "name": "Foo Baz"}},
+	}))
+	ctx := context.Background()
+	api, err := keboola.NewPublicAPI(ctx, "https://connection.keboola.mock", keboola.WithClient(&c))
+	require.NoError(t, err)
+
+	// Components are passed to the callback one by one, the rest of the index is the result
+	var names []string
+	index, err := api.StreamIndexComponentsRequest(func(component *keboola.Component) error {
+		names = append(names, component.Name)
+		return nil
+	}).Send(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"Foo Bar", "Foo Baz"}, names)
+	assert.Equal(t, keboola.Features{"foo"}, index.Features)
+	assert.Equal(t, keboola.Services{{ID: "queue", URL: "https://queue.keboola.mock"}}, index.Services)
+
+	// An error of the callback stops the decoding
+	err = api.StreamIndexComponentsRequest(func(component *keboola.Component) error {
+		return errors.New("some error")
+	}).SendOrErr(ctx)
+	if assert.Error(t, err) {
+		assert.Contains(t, err.Error(), "some error")
+	}
+}
+
+func TestAPI_Paginators(t *testing.T) {
+	t.Parallel()
+
+	// Mocked list endpoints, the server returns at most 2 items per page
+	c, transport := mockedClient()
+	listResponder := func(items []map[string]any) httpmock.Responder {
+		return func(req *http.Request) (*http.Response, error) {
+			offset, _ := strconv.Atoi(req.URL.Query().Get("offset"))
+			limit, _ := strconv.Atoi(req.URL.Query().Get("limit"))
+			end := min(len(items), offset+min(limit, 2))
+			return httpmock.NewJsonResponse(http.StatusOK, items[min(offset, end):end])
+		}
+	}
+	transport.RegisterResponder(http.MethodGet, `=~^https://connection.keboola.mock/v2/storage/branch/123/events`, listResponder([]map[string]any{
+		{"id": "1", "message": "a"}, {"id": "2", "message": "b"}, {"id": "3", "message": "c"},
+	}))
+	transport.RegisterResponder(http.MethodGet, `=~^https://queue.keboola.mock/jobs`, listResponder([]map[string]any{
+		{"id": "1"}, {"id": "2"}, {"id": "3"},
+	}))
+	ctx := context.Background()
+	api, err := keboola.NewAuthorizedAPI(ctx, "https://connection.keboola.mock", "my-token", keboola.WithClient(&c))
+	require.NoError(t, err)
+
+	// Events, all pages are loaded: 2 + 1 + 0
+	events, err := api.ListEventsPaginator(123, request.WithPageSize(5)).Send(ctx)
+	require.NoError(t, err)
+	if assert.Len(t, events, 3) {
+		assert.Equal(t, keboola.EventID("3"), events[2].ID)
+	}
+	assert.Equal(t, 1, transport.GetCallCountInfo()["GET https://connection.keboola.mock/v2/storage/branch/123/events?limit=5&offset=2"])
+
+	// Queue jobs of the configuration, limited by the max items
+	jobs, err := api.ListQueueJobsPaginator("foo.bar", "456", request.WithMaxItems(2)).Send(ctx)
+	require.NoError(t, err)
+	if assert.Len(t, jobs, 2) {
+		assert.Equal(t, keboola.JobID("2"), jobs[1].ID)
+	}
+	assert.Equal(t, 1, transport.GetCallCountInfo()["GET https://queue.keboola.mock/jobs?componentId=foo.bar&configId=456&limit=2&offset=0"])
+}
+
+func TestAPI_WaitForQueueJob_SendError(t *testing.T) {
+	t.Parallel()
+
+	// Setup
+	c, transport := mockedClient()
+	transport.RegisterResponder(http.MethodGet, "https://queue.keboola.mock/jobs/123", httpmock.NewStringResponder(http.StatusNotFound, "not found"))
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+	api, err := keboola.NewAuthorizedAPI(ctx, "https://connection.keboola.mock", "my-token", keboola.WithClient(&c))
+	require.NoError(t, err)
+
+	// The error of the job request is not wrapped
+	err = api.WaitForQueueJob(ctx, "123")
+	if assert.Error(t, err) {
+		assert.Equal(t, `request GET "https://queue.keboola.mock/jobs/123" failed: 404 Not Found`, err.Error())
+	}
+}
+
+func TestAPI_WaitForStorageJob_ClientRetry(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		name          string
+		retry         func(v request.RetryConfig) request.RetryConfig
+		expectedCalls int
+	}{
+		{
+			// The poll request retries more aggressively than the client default
+			name:          "raised",
+			retry:         func(v request.RetryConfig) request.RetryConfig { return v },
+			expectedCalls: 11,
+		},
+		{
+			// The retry condition of the client is kept
+			name: "disabled",
+			retry: func(v request.RetryConfig) request.RetryConfig {
+				v.Condition = client.NeverRetryCondition()
+				return v
+			},
+			expectedCalls: 1,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			// Setup
+			c, transport := mockedClient()
+			c = c.WithRetry(tc.retry(client.TestingRetry()))
+			transport.RegisterResponder(http.MethodGet, "/v2/storage/jobs/123", httpmock.NewStringResponder(http.StatusServiceUnavailable, "unavailable"))
+			ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+			defer cancel()
+			api, err := keboola.NewAuthorizedAPI(ctx, "https://connection.keboola.mock", "my-token", keboola.WithClient(&c))
+			require.NoError(t, err)
+
+			// Assert
+			err = api.WaitForStorageJob(ctx, &keboola.StorageJob{StorageJobKey: keboola.StorageJobKey{ID: 123}})
+			if assert.Error(t, err) {
+				assert.Contains(t, err.Error(), "503 Service Unavailable")
+			}
+			assert.Equal(t, tc.expectedCalls, transport.GetCallCountInfo()["GET /v2/storage/jobs/123"])
+		})
+	}
+}
+
+func TestAPI_WithPlan(t *testing.T) {
+	t.Parallel()
+
+	// Setup, only GET requests are mocked
+	c, transport := mockedClient()
+	transport.RegisterResponder(http.MethodGet, "/v2/storage/branch/123/components/foo.bar/configs/456/rows", httpmock.NewJsonResponderOrPanic(http.StatusOK, []map[string]any{
+		{"id": "old-row", "name": "Old Row"},
+	}))
+	plan := request.NewPlan()
+	ctx := context.Background()
+	api, err := keboola.NewAuthorizedAPI(ctx, "https://connection.keboola.mock", "my-token", keboola.WithClient(&c), keboola.WithPlan(plan))
+	require.NoError(t, err)
+
+	// Update config, rows are synchronized
+	config := &keboola.ConfigWithRows{
+		Config: &keboola.Config{ConfigKey: keboola.ConfigKey{BranchID: 123, ComponentID: "foo.bar", ID: "456"}, Name: "Config"},
+		Rows:   []*keboola.ConfigRow{{Name: "New Row"}},
+	}
+	_, err = api.UpdateConfigRequest(config, []string{"name"}).Send(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, "Config", config.Name)
+
+	// Delete bucket, the storage job is not polled
+	bucketKey := keboola.BucketKey{BranchID: 123, BucketID: keboola.BucketID{Stage: keboola.BucketStageIn, BucketName: "c-bucket"}}
+	require.NoError(t, api.DeleteBucketRequest(bucketKey, keboola.WithForce()).SendOrErr(ctx))
+
+	// No mutating request has been sent
+	for key := range transport.GetCallCountInfo() {
+		if transport.GetCallCountInfo()[key] > 0 {
+			assert.True(t, strings.HasPrefix(key, "GET "), key)
+		}
+	}
+
+	// Plan
+	assert.Equal(t, strings.TrimLeft(`
+1. PUT https://connection.keboola.mock/v2/storage/branch/123/components/foo.bar/configs/456
+   {"name":"Config"}
+2. DELETE https://connection.keboola.mock/v2/storage/branch/123/components/foo.bar/configs/456/rows/old-row
+3. POST https://connection.keboola.mock/v2/storage/branch/123/components/foo.bar/configs/456/rows
+   {"changeDescription":"","configuration":null,"description":"","isDisabled":false,"name":"New Row"}
+4. DELETE https://connection.keboola.mock/v2/storage/branch/123/buckets/in.c-bucket?async=1&force=true
+`, "\n"), plan.String())
+}
+
+func TestAPI_WithPlan_QueueJob(t *testing.T) {
+	t.Parallel()
+
+	// Setup, only the index is mocked
+	c, transport := mockedClient()
+	plan := request.NewPlan()
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+	api, err := keboola.NewAuthorizedAPI(ctx, "https://connection.keboola.mock", "my-token", keboola.WithClient(&c), keboola.WithPlan(plan))
+	require.NoError(t, err)
+
+	// Create job, the placeholder job is finished
+	job, err := api.CreateQueueJobRequest("foo.bar", "456").Send(ctx)
+	require.NoError(t, err)
+	assert.True(t, job.IsPlaceholder())
+	assert.True(t, job.IsFinished)
+	assert.Empty(t, job.ID)
+
+	// Wait for the job, no request is sent
+	require.NoError(t, api.WaitForCreatedQueueJob(ctx, job))
+	require.NoError(t, api.DeleteWorkspaceJobRequest("789").SendOrErr(ctx))
+	assert.Equal(t, 1, transport.GetTotalCallCount())
+	assert.Len(t, plan.Requests(), 2)
+
+	// The placeholder has no ID, which could be sent to the API
+	err = api.WaitForQueueJob(ctx, job.ID)
+	if assert.Error(t, err) {
+		assert.Equal(t, "job id must be set", err.Error())
+	}
+	assert.Equal(t, 1, transport.GetTotalCallCount())
+}
+
+func TestAPI_WithPlan_StorageJob(t *testing.T) {
+	t.Parallel()
+
+	// Setup, only the index is mocked
+	c, transport := mockedClient()
+	plan := request.NewPlan()
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+	api, err := keboola.NewAuthorizedAPI(ctx, "https://connection.keboola.mock", "my-token", keboola.WithClient(&c), keboola.WithPlan(plan))
+	require.NoError(t, err)
+
+	// The storage job of the intercepted request is a placeholder, WaitForStorageJob sends no request
+	branch, err := api.CreateBranchRequest(&keboola.Branch{Name: "My Branch"}).Send(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, "My Branch", branch.Name)
+	assert.Equal(t, 1, transport.GetTotalCallCount())
+	assert.Len(t, plan.Requests(), 1)
+}
@@ -19,11 +19,10 @@ func (a *PublicAPI) EncryptRequest(projectID int, componentID ComponentID, data
 	}
 	result := make(map[string]string)
 	req := a.newRequest(EncryptionAPI).
-		WithResult(&result).
 		WithMethod(http.MethodPost).
 		WithURL(EncryptionAPIEncrypt).
 		AndQueryParam("componentId", componentID.String()).
 		AndQueryParam("projectId", cast.ToString(projectID)).
 		WithJSONBody(data)
-	return request.NewAPIRequest(&result, req)
+	return request.NewTypedHTTPRequest(req, &result).APIRequest()
 }
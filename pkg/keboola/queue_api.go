@@ -5,13 +5,13 @@ package keboola
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"net/http"
+	"strconv"
 	"time"
 
 	"github.com/cenkalti/backoff/v4"
-	"go.opentelemetry.io/otel/codes"
-	"go.opentelemetry.io/otel/trace"
 
 	"github.com/keboola/go-client/pkg/request"
 )
@@ -146,11 +146,10 @@ func (b *CreateQueueJobRequestBuilder) WithBackendSize(size JobBackendSize) *Cre
 func (b *CreateQueueJobRequestBuilder) Build() request.APIRequest[*QueueJob] {
 	result := &QueueJob{}
 	req := b.api.newRequest(QueueAPI).
-		WithResult(&result).
 		WithMethod(http.MethodPost).
 		WithURL(QueueAPIJobs).
 		WithJSONBody(b.config)
-	return request.NewAPIRequest(result, req)
+	return request.NewTypedHTTPRequest(req, result).APIRequest()
 }
 
 // Send builds the request and immediately sends it.
@@ -171,11 +170,10 @@ func (a *AuthorizedAPI) CreateQueueJobRequest(componentID ComponentID, configID
 	}
 	result := QueueJob{}
 	req := a.newRequest(QueueAPI).
-		WithResult(&result).
 		WithMethod(http.MethodPost).
 		WithURL(QueueAPIJobs).
 		WithJSONBody(data)
-	return request.NewAPIRequest(&result, req)
+	return request.NewTypedHTTPRequest(req, &result).APIRequest()
 }
 
 // Deprecated: Use `NewCreateJobRequest` instead.
@@ -197,11 +195,10 @@ func (a *AuthorizedAPI) CreateQueueJobConfigDataRequest(componentID ComponentID,
 
 	result := &QueueJob{}
 	req := a.newRequest(QueueAPI).
-		WithResult(result).
 		WithMethod(http.MethodPost).
 		WithURL(QueueAPIJobs).
 		WithJSONBody(body)
-	return request.NewAPIRequest(result, req)
+	return request.NewTypedHTTPRequest(req, result).APIRequest()
 }
 
 // GetJobRequest https://app.swaggerhub.com/apis-docs/keboola/job-queue-api/1.3.2#/Jobs/getJob
@@ -212,55 +209,62 @@ func (a *AuthorizedAPI) GetQueueJobRequest(key JobKey) request.APIRequest[*Queue
 func (a *AuthorizedAPI) getQueueJobRequest(id JobID) request.APIRequest[*QueueJob] {
 	job := &QueueJob{}
 	req := a.newRequest(QueueAPI).
-		WithResult(job).
 		WithGet(QueueAPIJob).
 		AndPathParam("jobId", id.String())
-	return request.NewAPIRequest(job, req)
+	return request.NewTypedHTTPRequest(req, job).APIRequest()
+}
+
+// ListQueueJobsPaginator https://app.swaggerhub.com/apis-docs/keboola/job-queue-api/1.3.2#/Jobs/listJobs
+// Jobs of the configuration are loaded lazily, page by page.
+func (a *AuthorizedAPI) ListQueueJobsPaginator(componentID ComponentID, configID ConfigID, opts ...request.PaginatorOption) request.Paginator[*QueueJob] {
+	return request.NewPaginator(func(offset, limit int) request.APIRequest[*[]*QueueJob] {
+		var jobs []*QueueJob
+		req := a.newRequest(QueueAPI).
+			WithGet(QueueAPIJobs).
+			AndQueryParam("componentId", componentID.String()).
+			AndQueryParam("configId", configID.String()).
+			AndQueryParam("offset", strconv.Itoa(offset)).
+			AndQueryParam("limit", strconv.Itoa(limit))
+		return request.NewTypedHTTPRequest(req, &jobs).APIRequest()
+	}, opts...)
+}
+
+// WaitForCreatedQueueJob pulls status of the job returned by a create request until it is completed.
+// Unlike WaitForQueueJob, a placeholder job in the dry run is skipped, see request.Plan.
+func (a *AuthorizedAPI) WaitForCreatedQueueJob(ctx context.Context, job *QueueJob) error {
+	// The job is a placeholder in the dry run, see request.Plan
+	if job.IsPlaceholder() {
+		return nil
+	}
+	return a.WaitForQueueJob(ctx, job.ID)
 }
 
 // WaitForQueueJob pulls job status until it is completed.
-func (a *AuthorizedAPI) WaitForQueueJob(ctx context.Context, id JobID) (err error) {
-	_, ok := ctx.Deadline()
-	if !ok {
-		return fmt.Errorf("timeout for the job was not set")
+func (a *AuthorizedAPI) WaitForQueueJob(ctx context.Context, id JobID) error {
+	// The placeholder job in the dry run has no ID, see WaitForCreatedQueueJob
+	if id == "" {
+		return errors.New("job id must be set")
 	}
 
-	// Telemetry
-	parentSpan := trace.SpanFromContext(ctx)
-	var span trace.Span
-	ctx, span = parentSpan.TracerProvider().Tracer(appName).Start(ctx, "keboola.go.api.client.waitFor.queueJob")
-	defer func() {
-		if err != nil {
-			span.RecordError(err)
-			span.SetStatus(codes.Error, err.Error())
-		}
-		span.End()
-	}()
-
-	retry := newQueueJobBackoff()
-	for {
-		// Get job status
-		job, err := a.getQueueJobRequest(id).Send(ctx)
-		if err != nil {
-			return err
-		}
-
-		// Check status
-		if job.IsFinished {
-			if job.Status == "success" {
-				return nil
+	_, err := request.
+		NewPoller(a.getQueueJobRequest(id), func(job *QueueJob) bool { return job.IsFinished }).
+		WithFailure(func(job *QueueJob) error {
+			if job.IsFinished && job.Status != "success" {
+				return fmt.Errorf(`job "%s" failed: %v`, job.ID, job.Result.Message)
 			}
-			return fmt.Errorf(`job "%s" failed: %v`, job.ID, job.Result.Message)
-		}
-
-		// Wait and check again
-		select {
-		case <-ctx.Done():
-			return fmt.Errorf(`error while waiting for the job "%s" to complete: %w`, job.ID, ctx.Err())
-		case <-time.After(retry.NextBackOff()):
-			// try again
-		}
+			return nil
+		}).
+		WithBackoff(func() backoff.BackOff { return newQueueJobBackoff() }).
+		WithDescription(fmt.Sprintf(`job "%s"`, id)).
+		WithSpan(appName, "keboola.go.api.client.waitFor.queueJob").
+		Wait(ctx)
+
+	// The error of the job request is returned as it is, the retries are not reported, for backward compatibility
+	var sendErr request.PollSendError
+	if errors.As(err, &sendErr) {
+		return sendErr.Err
 	}
+	return err
 }
 
 // newQueueJobBackoff creates retry for WaitForQueueJob.
@@ -5,6 +5,8 @@ import (
 	"fmt"
 
 	"github.com/relvacode/iso8601"
+
+	"github.com/keboola/go-client/pkg/request"
 )
 
 // JobID is an ID of a component job.
@@ -55,6 +57,7 @@ func (r *JobResult) UnmarshalJSON(data []byte) (err error) {
 
 // QueueJob is a component job.
 type QueueJob struct {
+	request.Placeholder
 	JobKey
 	Status     string        `json:"status"`
 	IsFinished bool          `json:"isFinished"`
@@ -64,3 +67,11 @@ type QueueJob struct {
 	StartTime  *iso8601.Time `json:"startTime"`
 	EndTime    *iso8601.Time `json:"endTime"`
 }
+
+// SetPlaceholder implements request.PlaceholderResult, the job of an intercepted request is finished, so nobody waits for it.
+// The job is marked as a placeholder, so WaitForCreatedQueueJob returns immediately, without a request.
+func (j *QueueJob) SetPlaceholder() {
+	j.Placeholder.SetPlaceholder()
+	j.Status = "success"
+	j.IsFinished = true
+}
@@ -20,11 +20,10 @@ func (a *AuthorizedAPI) ActivateScheduleRequest(configID ConfigID, configuration
 	}
 	result := &Schedule{}
 	req := a.newRequest(SchedulerAPI).
-		WithResult(result).
 		WithMethod(http.MethodPost).
 		WithURL(SchedulerAPISchedules).
 		WithJSONBody(body)
-	return request.NewAPIRequest(result, req)
+	return request.NewTypedHTTPRequest(req, result).APIRequest()
 }
 
 // DeleteScheduleRequest https://app.swaggerhub.com/apis/odinuv/scheduler/1.0.0#/schedules/deleteSchedule
@@ -49,10 +48,9 @@ func (a *AuthorizedAPI) DeleteSchedulesForConfigurationRequest(configID ConfigID
 func (a *AuthorizedAPI) ListSchedulesRequest() request.APIRequest[*[]*Schedule] {
 	result := make([]*Schedule, 0)
 	req := a.newRequest(SchedulerAPI).
-		WithResult(&result).
 		WithMethod(http.MethodGet).
 		WithURL(SchedulerAPISchedules)
-	return request.NewAPIRequest(&result, req)
+	return request.NewTypedHTTPRequest(req, &result).APIRequest()
 }
 
 // GetScheduleRequest retrieves a schedule by its ID
@@ -60,11 +58,10 @@ func (a *AuthorizedAPI) ListSchedulesRequest() request.APIRequest[*[]*Schedule]
 func (a *AuthorizedAPI) GetScheduleRequest(key ScheduleKey) request.APIRequest[*Schedule] {
 	var result Schedule
 	req := a.newRequest(SchedulerAPI).
-		WithResult(&result).
 		WithMethod(http.MethodGet).
 		WithURL(SchedulerAPISchedule).
 		AndPathParam("scheduleId", key.ID.String())
-	return request.NewAPIRequest(&result, req)
+	return request.NewTypedHTTPRequest(req, &result).APIRequest()
 }
 
 // RefreshScheduleTokenRequest refreshes the token for a schedule
@@ -41,9 +41,8 @@ func (a *AuthorizedAPI) ListBranchesRequest() request.APIRequest[*[]*Branch] {
 	result := make([]*Branch, 0)
 	req := a.
 		newRequest(StorageAPI).
-		WithResult(&result).
 		WithGet("dev-branches")
-	return request.NewAPIRequest(&result, req)
+	return request.NewTypedHTTPRequest(req, &result).APIRequest()
 }
 
 // GetDefaultBranchRequest lists all branches and returns the default branch.
@@ -65,13 +64,11 @@ func (a *AuthorizedAPI) GetDefaultBranchRequest() request.APIRequest[*Branch] {
 
 // GetBranchRequest https://keboola.docs.apiary.io/#reference/development-branches/branch-manipulation/branch-detail
 func (a *AuthorizedAPI) GetBranchRequest(key BranchKey) request.APIRequest[*Branch] {
-	result := &Branch{}
 	req := a.
 		newRequest(StorageAPI).
-		WithResult(result).
 		WithGet("dev-branches/{branchId}").
 		AndPathParam("branchId", key.ID.String())
-	return request.NewAPIRequest(result, req)
+	return request.NewTypedHTTPRequest(req, &Branch{}).APIRequest()
 }
 
 // CreateBranchRequest https://keboola.docs.apiary.io/#reference/development-branches/branches/create-branch
@@ -112,13 +109,11 @@ func (a *AuthorizedAPI) CreateBranchAsyncRequest(branch *Branch) request.APIRequ
 		panic(fmt.Errorf("default branch cannot be created"))
 	}
 
-	result := &StorageJob{}
 	req := a.
 		newRequest(StorageAPI).
-		WithResult(result).
 		WithPost("dev-branches").
 		WithJSONBody(request.StructToMap(branch, nil))
-	return request.NewAPIRequest(result, req)
+	return request.NewTypedHTTPRequest(req, &StorageJob{}).APIRequest()
 }
 
 // UpdateBranchRequest https://keboola.docs.apiary.io/#reference/development-branches/branches/update-branch
@@ -139,11 +134,10 @@ func (a *AuthorizedAPI) UpdateBranchRequest(branch *Branch, changedFields []stri
 	// Create request
 	req := a.
 		newRequest(StorageAPI).
-		WithResult(branch).
 		WithPut("dev-branches/{branchId}").
 		AndPathParam("branchId", branch.ID.String()).
 		WithJSONBody(request.StructToMap(branch, changedFields))
-	return request.NewAPIRequest(branch, req)
+	return request.NewTypedHTTPRequest(req, branch).APIRequest()
 }
 
 // DeleteBranchRequest https://keboola.docs.apiary.io/#reference/development-branches/branch-manipulation/delete-branch
@@ -161,13 +155,11 @@ func (a *AuthorizedAPI) DeleteBranchRequest(key BranchKey) request.APIRequest[re
 
 // DeleteBranchAsyncRequest https://keboola.docs.apiary.io/#reference/development-branches/branch-manipulation/delete-branch
 func (a *AuthorizedAPI) DeleteBranchAsyncRequest(key BranchKey) request.APIRequest[*StorageJob] {
-	result := &StorageJob{}
 	req := a.
 		newRequest(StorageAPI).
-		WithResult(result).
 		WithDelete("dev-branches/{branchId}").
 		AndPathParam("branchId", key.ID.String())
-	return request.NewAPIRequest(result, req)
+	return request.NewTypedHTTPRequest(req, &StorageJob{}).APIRequest()
 }
 
 // ListBranchMetadataRequest https://keboola.docs.apiary.io/#reference/metadata/development-branch-metadata/list
@@ -175,10 +167,9 @@ func (a *AuthorizedAPI) ListBranchMetadataRequest(key BranchKey) request.APIRequ
 	result := make(MetadataDetails, 0)
 	req := a.
 		newRequest(StorageAPI).
-		WithResult(&result).
 		WithGet("branch/{branchId}/metadata").
 		AndPathParam("branchId", key.ID.String())
-	return request.NewAPIRequest(&result, req)
+	return request.NewTypedHTTPRequest(req, &result).APIRequest()
 }
 
 // AppendBranchMetadataRequest https://keboola.docs.apiary.io/#reference/metadata/development-branch-metadata/create-or-update https://keboola.docs.apiary.io/#reference/metadata/development-branch-metadata/delete
@@ -54,14 +54,12 @@ type ListBucketsOption func(c *listBucketsConfig)
 
 // GetBucketRequest https://keboola.docs.apiary.io/#reference/buckets/manage-bucket/bucket-detail
 func (a *AuthorizedAPI) GetBucketRequest(k BucketKey) request.APIRequest[*Bucket] {
-	result := Bucket{BucketKey: k}
 	req := a.
 		newRequest(StorageAPI).
-		WithResult(&result).
 		WithGet("branch/{branchId}/buckets/{bucketId}").
 		AndPathParam("branchId", k.BranchID.String()).
 		AndPathParam("bucketId", k.BucketID.String())
-	return request.NewAPIRequest(&result, req)
+	return request.NewTypedHTTPRequest(req, &Bucket{BucketKey: k}).APIRequest()
 }
 
 // ListBucketsRequest https://keboola.docs.apiary.io/#reference/buckets/create-or-list-buckets/list-all-buckets
@@ -74,13 +72,13 @@ func (a *AuthorizedAPI) ListBucketsRequest(branchID BranchID, opts ...ListBucket
 	result := make([]*Bucket, 0)
 	req := a.
 		newRequest(StorageAPI).
-		WithResult(&result).
 		WithGet("branch/{branchId}/buckets").
 		AndPathParam("branchId", branchID.String()).
 		AndQueryParam("include", config.includeString())
 
 	return request.
-		NewAPIRequest(&result, req).
+		NewTypedHTTPRequest(req, &result).
+		APIRequest().
 		WithOnSuccess(func(ctx context.Context, result *[]*Bucket) error {
 			for _, bucket := range *result {
 				bucket.BranchID = branchID
@@ -115,19 +113,10 @@ func (a *AuthorizedAPI) CreateBucketRequest(bucket *Bucket) request.APIRequest[*
 
 	req := a.
 		newRequest(StorageAPI).
-		WithResult(bucket).
 		WithPost("branch/{branchId}/buckets").
 		AndPathParam("branchId", bucket.BranchID.String()).
-		WithJSONBody(params).
-		WithOnError(ignoreResourceAlreadyExistsError(func(ctx context.Context) error {
-			if result, err := a.GetBucketRequest(bucket.BucketKey).Send(ctx); err == nil {
-				*bucket = *result
-				return nil
-			} else {
-				return err
-			}
-		}))
-	return request.NewAPIRequest(bucket, req)
+		WithJSONBody(params)
+	return request.NewTypedHTTPRequest(req, bucket).APIRequest()
 }
 
 // DeleteBucketRequest https://keboola.docs.apiary.io/#reference/buckets/manage-bucket/drop-bucket
@@ -155,10 +144,8 @@ func (a *AuthorizedAPI) DeleteBucketAsyncRequest(k BucketKey, opts ...DeleteOpti
 		opt(c)
 	}
 
-	result := &StorageJob{}
 	req := a.
 		newRequest(StorageAPI).
-		WithResult(result).
 		WithDelete("branch/{branchId}/buckets/{bucketId}").
 		AndPathParam("branchId", k.BranchID.String()).
 		AndPathParam("bucketId", k.BucketID.String()).
@@ -168,5 +155,5 @@ func (a *AuthorizedAPI) DeleteBucketAsyncRequest(k BucketKey, opts ...DeleteOpti
 		req = req.AndQueryParam("force", "true")
 	}
 
-	return request.NewAPIRequest(result, req)
+	return request.NewTypedHTTPRequest(req, &StorageJob{}).APIRequest()
 }
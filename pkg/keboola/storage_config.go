@@ -83,7 +83,6 @@ func (a *AuthorizedAPI) ListConfigsAndRowsFrom(branch BranchKey) request.APIRequ
 	result := make([]*ComponentWithConfigs, 0)
 	req := a.
 		newRequest(StorageAPI).
-		WithResult(&result).
 		WithGet("branch/{branchId}/components").
 		AndPathParam("branchId", branch.ID.String()).
 		AndQueryParam("include", "configuration,rows").
@@ -108,13 +107,38 @@ func (a *AuthorizedAPI) ListConfigsAndRowsFrom(branch BranchKey) request.APIRequ
 			}
 			return nil
 		})
-	return request.NewAPIRequest(&result, req)
+	return request.NewTypedHTTPRequest(req, &result).APIRequest()
+}
+
+// StreamConfigsAndRowsFrom is the same as ListConfigsAndRowsFrom, but the components are decoded one by one and passed to the callback.
+// It keeps the memory usage low for branches with many configurations.
+func (a *AuthorizedAPI) StreamConfigsAndRowsFrom(branch BranchKey, fn func(component *ComponentWithConfigs) error) request.APIRequest[request.NoResult] {
+	result := request.NewStreamResult(func(component *ComponentWithConfigs) error {
+		// Add missing values
+		component.BranchID = branch.ID
+		for _, config := range component.Configs {
+			config.BranchID = branch.ID
+			config.ComponentID = component.ID
+			config.SortRows()
+			for _, row := range config.Rows {
+				row.BranchID = branch.ID
+				row.ComponentID = component.ID
+				row.ConfigID = config.ID
+			}
+		}
+		return fn(component)
+	})
+	req := a.
+		newRequest(StorageAPI).
+		WithGet("branch/{branchId}/components").
+		AndPathParam("branchId", branch.ID.String()).
+		AndQueryParam("include", "configuration,rows")
+	return request.NewAPIRequest(request.NoResult{}, request.NewTypedHTTPRequest(req, result))
 }
 
 func (a *AuthorizedAPI) ListConfigRequest(branchID BranchID, componentID ComponentID) request.APIRequest[*[]*Config] {
 	result := make([]*Config, 0)
 	req := a.newRequest(StorageAPI).
-		WithResult(&result).
 		WithGet("branch/{branchId}/components/{componentId}/configs").
 		AndPathParam("branchId", branchID.String()).
 		AndPathParam("componentId", componentID.String()).
@@ -125,7 +149,7 @@ func (a *AuthorizedAPI) ListConfigRequest(branchID BranchID, componentID Compone
 			}
 			return nil
 		})
-	return request.NewAPIRequest(&result, req)
+	return request.NewTypedHTTPRequest(req, &result).APIRequest()
 }
 
 // GetConfigRequest https://keboola.docs.apiary.io/#reference/components-and-configurations/manage-configurations/development-branch-configuration-detail
@@ -135,12 +159,11 @@ func (a *AuthorizedAPI) GetConfigRequest(key ConfigKey) request.APIRequest[*Conf
 	result.ComponentID = key.ComponentID
 	req := a.
 		newRequest(StorageAPI).
-		WithResult(result).
 		WithGet("branch/{branchId}/components/{componentId}/configs/{configId}").
 		AndPathParam("branchId", key.BranchID.String()).
 		AndPathParam("componentId", key.ComponentID.String()).
 		AndPathParam("configId", key.ID.String())
-	return request.NewAPIRequest(result, req)
+	return request.NewTypedHTTPRequest(req, result).APIRequest()
 }
 
 // CreateConfigRequest https://keboola.docs.apiary.io/#reference/components-and-configurations/component-configurations/create-development-branch-configuration
@@ -148,19 +171,10 @@ func (a *AuthorizedAPI) CreateConfigRequest(config *ConfigWithRows, withAsyncRow
 	// Create config
 	req := a.
 		newRequest(StorageAPI).
-		WithResult(config).
 		WithPost("branch/{branchId}/components/{componentId}/configs").
 		AndPathParam("branchId", config.BranchID.String()).
 		AndPathParam("componentId", string(config.ComponentID)).
 		WithJSONBody(request.StructToMap(config.Config, nil)).
-		WithOnError(ignoreResourceAlreadyExistsError(func(ctx context.Context) error {
-			if result, err := a.GetConfigRequest(config.ConfigKey).Send(ctx); err == nil {
-				*config.Config = *result
-				return nil
-			} else {
-				return err
-			}
-		})).
 		// Create config rows
 		WithOnSuccess(func(ctx context.Context, _ request.HTTPResponse) error {
 			wg := request.NewWaitGroup(ctx)
@@ -181,7 +195,7 @@ func (a *AuthorizedAPI) CreateConfigRequest(config *ConfigWithRows, withAsyncRow
 
 			return wg.Wait()
 		})
-	return request.NewAPIRequest(config, req)
+	return request.NewTypedHTTPRequest(req, config).APIRequest()
 }
 
 // UpdateConfigRequest https://keboola.docs.apiary.io/#reference/components-and-configurations/manage-configurations/update-configuration
@@ -195,7 +209,6 @@ func (a *AuthorizedAPI) UpdateConfigRequest(config *ConfigWithRows, changedField
 	tmpConfig := &ConfigWithRows{}
 	req := a.
 		newRequest(StorageAPI).
-		WithResult(tmpConfig).
 		WithPut("branch/{branchId}/components/{componentId}/configs/{configId}").
 		AndPathParam("branchId", config.BranchID.String()).
 		AndPathParam("componentId", string(config.ComponentID)).
@@ -203,7 +216,10 @@ func (a *AuthorizedAPI) UpdateConfigRequest(config *ConfigWithRows, changedField
 		WithJSONBody(request.StructToMap(config, changedFields)).
 		// Update config rows
 		WithOnSuccess(func(ctx context.Context, resp request.HTTPResponse) error {
-			// Update config fields from tmpConfig, preserving Rows
+			// Update config fields from tmpConfig, preserving Rows, there is no result in the dry run
+			if request.IsDryRun(resp) {
+				return a.synchronizeConfigRows(ctx, config, changedFields)
+			}
 			tmpConfig.BranchID = config.BranchID
 			tmpConfig.ComponentID = config.ComponentID
 			tmpConfig.ID = config.ID
@@ -213,7 +229,7 @@ func (a *AuthorizedAPI) UpdateConfigRequest(config *ConfigWithRows, changedField
 			return a.synchronizeConfigRows(ctx, config, changedFields)
 		})
 
-	return request.NewAPIRequest(config, req)
+	return request.NewAPIRequest(config, request.NewTypedHTTPRequest(req, tmpConfig))
 }
 
 // DeleteConfigRequest https://keboola.docs.apiary.io/#reference/components-and-configurations/manage-configurations/delete-configuration
@@ -250,7 +266,6 @@ func (a *AuthorizedAPI) ListConfigMetadataRequest(branchID BranchID) request.API
 	result := make(ConfigsMetadata, 0)
 	req := a.
 		newRequest(StorageAPI).
-		WithResult(&result).
 		WithGet("branch/{branchId}/search/component-configurations").
 		AndPathParam("branchId", branchID.String()).
 		AndQueryParam("include", "filteredMetadata").
@@ -260,7 +275,7 @@ func (a *AuthorizedAPI) ListConfigMetadataRequest(branchID BranchID) request.API
 			}
 			return nil
 		})
-	return request.NewAPIRequest(&result, req)
+	return request.NewTypedHTTPRequest(req, &result).APIRequest()
 }
 
 // AppendConfigMetadataRequest https://keboola.docs.apiary.io/#reference/metadata/components-configurations-metadata/create-or-update
@@ -2,6 +2,7 @@ package keboola
 
 import (
 	jsonLib "encoding/json"
+	"strconv"
 
 	"github.com/keboola/go-client/pkg/client"
 	"github.com/keboola/go-client/pkg/request"
@@ -41,10 +42,24 @@ func (a *AuthorizedAPI) CreateEventRequest(event *Event) request.APIRequest[*Eve
 	body["results"] = string(rValue)
 	req := a.
 		newRequest(StorageAPI).
-		WithResult(event).
 		WithPost("events").
 		WithJSONBody(body)
-	return request.NewAPIRequest(event, req)
+	return request.NewTypedHTTPRequest(req, event).APIRequest()
+}
+
+// ListEventsPaginator https://keboola.docs.apiary.io/#reference/events/events/list-events
+// Events are loaded lazily, page by page, from the newest, the WithMaxItems option limits the history.
+func (a *AuthorizedAPI) ListEventsPaginator(branchID BranchID, opts ...request.PaginatorOption) request.Paginator[*Event] {
+	return request.NewPaginator(func(offset, limit int) request.APIRequest[*[]*Event] {
+		var events []*Event
+		req := a.
+			newRequest(StorageAPI).
+			WithGet("branch/{branchId}/events").
+			AndPathParam("branchId", branchID.String()).
+			AndQueryParam("offset", strconv.Itoa(offset)).
+			AndQueryParam("limit", strconv.Itoa(limit))
+		return request.NewTypedHTTPRequest(req, &events).APIRequest()
+	}, opts...)
 }
 
 // JSONString is Json encoded as string, see CreateEventRequest.
@@ -17,7 +17,10 @@ import (
 	"github.com/keboola/go-client/pkg/request"
 )
 
-const ManifestFileName = "manifest"
+const (
+	ManifestFileName  = "manifest"
+	listFilesPageSize = 200
+)
 
 type FileID int
 
@@ -216,7 +219,6 @@ func (a *AuthorizedAPI) CreateFileResourceRequest(branchID BranchID, name string
 	file.BranchID = branchID
 	req := a.
 		newRequest(StorageAPI).
-		WithResult(file).
 		WithPost("branch/{branchId}/files/prepare").
 		AndPathParam("branchId", branchID.String()).
 		WithJSONBody(c.toMap()).
@@ -227,31 +229,43 @@ func (a *AuthorizedAPI) CreateFileResourceRequest(branchID BranchID, name string
 			file.Notify = c.notify
 			return nil
 		})
-	return request.NewAPIRequest(file, req)
+	return request.NewTypedHTTPRequest(req, file).APIRequest()
 }
 
 // ListFilesRequest https://keboola.docs.apiary.io/#reference/files/list-files
+// All pages are loaded, see ListFilesPaginator.
 func (a *AuthorizedAPI) ListFilesRequest(branchID BranchID) request.APIRequest[*[]*File] {
 	var files []*File
-	req := a.
-		newRequest(StorageAPI).
-		WithResult(&files).
-		WithGet("branch/{branchId}/files").
-		AndPathParam("branchId", branchID.String()).
-		AndQueryParam("limit", "200").
-		WithOnSuccess(func(_ context.Context, _ request.HTTPResponse) error {
-			for _, file := range files {
-				file.BranchID = branchID
-			}
-			return nil
-		}).
-		WithOnSuccess(func(_ context.Context, _ request.HTTPResponse) error {
+	return request.
+		NewAPIRequest(&files, a.ListFilesPaginator(branchID).Into(&files)).
+		WithOnSuccess(func(_ context.Context, _ *[]*File) error {
 			sort.Slice(files, func(i, j int) bool {
 				return files[i].FileID < files[j].FileID
 			})
 			return nil
 		})
-	return request.NewAPIRequest(&files, req)
+}
+
+// ListFilesPaginator https://keboola.docs.apiary.io/#reference/files/list-files
+// Files are loaded lazily, page by page.
+func (a *AuthorizedAPI) ListFilesPaginator(branchID BranchID, opts ...request.PaginatorOption) request.Paginator[*File] {
+	opts = append([]request.PaginatorOption{request.WithPageSize(listFilesPageSize)}, opts...)
+	return request.NewPaginator(func(offset, limit int) request.APIRequest[*[]*File] {
+		var files []*File
+		req := a.
+			newRequest(StorageAPI).
+			WithGet("branch/{branchId}/files").
+			AndPathParam("branchId", branchID.String()).
+			AndQueryParam("offset", strconv.Itoa(offset)).
+			AndQueryParam("limit", strconv.Itoa(limit)).
+			WithOnSuccess(func(_ context.Context, _ request.HTTPResponse) error {
+				for _, file := range files {
+					file.BranchID = branchID
+				}
+				return nil
+			})
+		return request.NewTypedHTTPRequest(req, &files).APIRequest()
+	}, opts...)
 }
 
 // GetFileRequest https://keboola.docs.apiary.io/#reference/files/manage-files/file-detail
@@ -259,11 +273,10 @@ func (a *AuthorizedAPI) GetFileRequest(k FileKey) request.APIRequest[*File] {
 	file := &File{FileKey: k}
 	req := a.
 		newRequest(StorageAPI).
-		WithResult(file).
 		WithGet("branch/{branchId}/files/{fileId}").
 		AndPathParam("branchId", k.BranchID.String()).
 		AndPathParam("fileId", k.FileID.String())
-	return request.NewAPIRequest(file, req)
+	return request.NewTypedHTTPRequest(req, file).APIRequest()
 }
 
 // GetFileWithCredentialsRequest https://keboola.docs.apiary.io/#reference/files/manage-files/file-detail
@@ -272,12 +285,11 @@ func (a *AuthorizedAPI) GetFileWithCredentialsRequest(k FileKey) request.APIRequ
 	file.FileKey = k
 	req := a.
 		newRequest(StorageAPI).
-		WithResult(file).
 		WithGet("branch/{branchId}/files/{fileId}").
 		AndPathParam("branchId", k.BranchID.String()).
 		AndPathParam("fileId", k.FileID.String()).
 		AndQueryParam("federationToken", "1")
-	return request.NewAPIRequest(file, req)
+	return request.NewTypedHTTPRequest(req, file).APIRequest()
 }
 
 // DeleteFileRequest https://keboola.docs.apiary.io/#reference/files/manage-files/delete-file
@@ -1,9 +1,17 @@
 package keboola
 
 import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"time"
+
 	"github.com/keboola/go-client/pkg/request"
 )
 
+// IndexCacheTTL is the maximum age of a cached index response, if the cache is enabled, see WithCache.
+const IndexCacheTTL = time.Hour
+
 // Index of Storage API.
 type Index struct {
 	Services Services `json:"services"`
@@ -56,10 +64,10 @@ func (a *PublicAPI) IndexRequest() request.APIRequest[*Index] {
 	index := &Index{}
 	req := a.
 		newRequest(StorageAPI).
-		WithResult(index).
 		WithGet("").
+		WithCache(IndexCacheTTL).
 		AndQueryParam("exclude", "components")
-	return request.NewAPIRequest(index, req)
+	return request.NewTypedHTTPRequest(req, index).APIRequest()
 }
 
 // IndexComponentsRequest returns index of Storage API with components definitions.
@@ -67,9 +75,84 @@ func (a *PublicAPI) IndexComponentsRequest() request.APIRequest[*IndexComponents
 	result := &IndexComponents{}
 	req := a.
 		newRequest(StorageAPI).
-		WithResult(result).
-		WithGet("")
-	return request.NewAPIRequest(result, req)
+		WithGet("").
+		WithCache(IndexCacheTTL)
+	return request.NewTypedHTTPRequest(req, result).APIRequest()
+}
+
+// StreamIndexComponentsRequest is the same as IndexComponentsRequest, but the components are decoded one by one and passed to the callback.
+// The result is the index without components. It keeps the memory usage low, the components definitions are large.
+func (a *PublicAPI) StreamIndexComponentsRequest(fn func(component *Component) error) request.APIRequest[*Index] {
+	result := &indexComponentsStream{index: &Index{}, fn: fn}
+	req := a.
+		newRequest(StorageAPI).
+		WithGet("").
+		WithCache(IndexCacheTTL)
+	return request.NewAPIRequest(result.index, request.NewTypedHTTPRequest(req, result))
+}
+
+// indexComponentsStream decodes the index, the components are passed to the callback, see StreamIndexComponentsRequest.
+type indexComponentsStream struct {
+	index *Index
+	fn    func(component *Component) error
+}
+
+// DecodeStream implements the request.StreamDecoder interface.
+func (s *indexComponentsStream) DecodeStream(body io.Reader) error {
+	decoder := json.NewDecoder(body)
+	if err := expectJSONDelim(decoder, '{'); err != nil {
+		return err
+	}
+	for decoder.More() {
+		token, err := decoder.Token()
+		if err != nil {
+			return fmt.Errorf(`cannot decode index: %w`, err)
+		}
+		switch token {
+		case "services":
+			err = decoder.Decode(&s.index.Services)
+		case "features":
+			err = decoder.Decode(&s.index.Features)
+		case "components":
+			// An error of the callback is not wrapped
+			if err := s.decodeComponents(decoder); err != nil {
+				return err
+			}
+		default:
+			err = decoder.Decode(&json.RawMessage{})
+		}
+		if err != nil {
+			return fmt.Errorf(`cannot decode index "%v": %w`, token, err)
+		}
+	}
+	return expectJSONDelim(decoder, '}')
+}
+
+func (s *indexComponentsStream) decodeComponents(decoder *json.Decoder) error {
+	if err := expectJSONDelim(decoder, '['); err != nil {
+		return err
+	}
+	for decoder.More() {
+		component := &Component{}
+		if err := decoder.Decode(component); err != nil {
+			return fmt.Errorf(`cannot decode index component: %w`, err)
+		}
+		if err := s.fn(component); err != nil {
+			return err
+		}
+	}
+	return expectJSONDelim(decoder, ']')
+}
+
+func expectJSONDelim(decoder *json.Decoder, expected json.Delim) error {
+	token, err := decoder.Token()
+	if err != nil {
+		return fmt.Errorf(`cannot decode index: %w`, err)
+	}
+	if delim, ok := token.(json.Delim); !ok || delim != expected {
+		return fmt.Errorf(`cannot decode index: expected "%v", found "%v"`, expected, token)
+	}
+	return nil
 }
 
 // ToMap converts Services slice to ServicesMap.
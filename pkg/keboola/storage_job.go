@@ -9,8 +9,6 @@ import (
 
 	"github.com/cenkalti/backoff/v4"
 	"github.com/relvacode/iso8601"
-	"go.opentelemetry.io/otel/codes"
-	"go.opentelemetry.io/otel/trace"
 
 	"github.com/keboola/go-client/pkg/request"
 )
@@ -50,6 +48,7 @@ func (r *StorageJobResult) UnmarshalJSON(data []byte) (err error) {
 
 // StorageJob is a storage job.
 type StorageJob struct {
+	request.Placeholder
 	StorageJobKey
 	Status          string           `json:"status"`
 	URL             string           `json:"url"`
@@ -62,6 +61,13 @@ type StorageJob struct {
 	Error           *StorageJobError `json:"error,omitempty"`
 }
 
+// SetPlaceholder implements request.PlaceholderResult, the job of an intercepted request is finished, so nobody waits for it.
+// The job is marked as a placeholder, so WaitForStorageJob returns immediately, without a request.
+func (j *StorageJob) SetPlaceholder() {
+	j.Placeholder.SetPlaceholder()
+	j.Status = StorageJobStatusSuccess
+}
+
 type StorageJobError struct {
 	Code        string `json:"code"`
 	Message     string `json:"message"`
@@ -74,59 +80,40 @@ func (a *AuthorizedAPI) GetStorageJobRequest(key StorageJobKey) request.APIReque
 }
 
 func (a *AuthorizedAPI) getStorageJobRequest(job *StorageJob) request.APIRequest[*StorageJob] {
+	return a.getStorageJobHTTPRequest(job).APIRequest()
+}
+
+func (a *AuthorizedAPI) getStorageJobHTTPRequest(job *StorageJob) request.TypedHTTPRequest[*StorageJob] {
 	req := a.
 		newRequest(StorageAPI).
-		WithResult(job).
 		WithGet("jobs/{jobId}").
 		AndPathParam("jobId", job.ID.String())
-	return request.NewAPIRequest(job, req)
+	return request.NewTypedHTTPRequest(req, job)
 }
 
 // WaitForStorageJob pulls job status until it is completed.
-func (a *AuthorizedAPI) WaitForStorageJob(ctx context.Context, job *StorageJob) (err error) {
-	_, ok := ctx.Deadline()
-	if !ok {
-		return fmt.Errorf("timeout for the job was not set")
+func (a *AuthorizedAPI) WaitForStorageJob(ctx context.Context, job *StorageJob) error {
+	// The job is a placeholder in the dry run, see request.Plan
+	if job.IsPlaceholder() {
+		return nil
 	}
 
-	// Telemetry
-	parentSpan := trace.SpanFromContext(ctx)
-	var span trace.Span
-	ctx, span = parentSpan.TracerProvider().Tracer(appName).Start(ctx, "keboola.go.api.client.waitFor.storageJob")
-	defer func() {
-		if err != nil {
-			span.RecordError(err)
-			span.SetStatus(codes.Error, err.Error())
-		}
-		span.End()
-	}()
-
-	i := 0
-	startedAt := time.Now()
-	retry := newStorageJobBackoff()
-	for {
-		// Get job status
-		if err := a.getStorageJobRequest(job).SendOrErr(ctx); err != nil {
-			return fmt.Errorf(`error after %d retries, total time %s: %w`, i, time.Since(startedAt), err)
-		}
-
-		// Check status
-		if job.Status == StorageJobStatusSuccess {
+	pollReq := a.getStorageJobHTTPRequest(job).
+		WithRequest(func(r request.HTTPRequest) request.HTTPRequest { return r.WithRetryModifier(raiseStorageJobPollRetry) }).
+		APIRequest()
+	_, err := request.
+		NewPoller(pollReq, func(job *StorageJob) bool { return job.Status == StorageJobStatusSuccess }).
+		WithFailure(func(job *StorageJob) error {
+			if job.Status == StorageJobStatusError {
+				return fmt.Errorf(`job "%s" failed: %s (exception id: %s)`, job.ID, job.Error.Message, job.Error.ExceptionID)
+			}
 			return nil
-		} else if job.Status == StorageJobStatusError {
-			return fmt.Errorf(`job "%s" failed: %s (exception id: %s)`, job.ID, job.Error.Message, job.Error.ExceptionID)
-		}
-
-		// Wait and check again
-		select {
-		case <-ctx.Done():
-			return fmt.Errorf(`error while waiting for the job "%s" to complete: %w`, job.ID, ctx.Err())
-		case <-time.After(retry.NextBackOff()):
-			// try again
-		}
-
-		i += 1
-	}
+		}).
+		WithBackoff(func() backoff.BackOff { return newStorageJobBackoff() }).
+		WithDescription(fmt.Sprintf(`job "%s"`, job.ID)).
+		WithSpan(appName, "keboola.go.api.client.waitFor.storageJob").
+		Wait(ctx)
+	return err
 }
 
 // newStorageJobBackoff creates retry for WaitForStorageJob.
@@ -140,3 +127,14 @@ func newStorageJobBackoff() *backoff.ExponentialBackOff {
 	b.Reset()
 	return b
 }
+
+// raiseStorageJobPollRetry adjusts the retry config of the client for the job status request in WaitForStorageJob.
+// The GET request has no side effects, so it is retried more aggressively than the client default.
+// Other settings of the client, such as the retry condition and the timeout, are kept, disabled retries stay disabled.
+func raiseStorageJobPollRetry(retry request.RetryConfig) request.RetryConfig {
+	if retry.Count > 0 {
+		retry.Count = max(retry.Count, 10)
+		retry.WaitTimeMax = max(retry.WaitTimeMax, 5*time.Second)
+	}
+	return retry
+}
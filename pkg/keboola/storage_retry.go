@@ -0,0 +1,66 @@
+package keboola
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/keboola/go-client/pkg/client"
+	"github.com/keboola/go-client/pkg/request"
+)
+
+// ignoreResourceNotFoundError reconciles a retried DELETE request.
+// The DELETE method is idempotent, so the request is retried, for example, after a 500 error.
+// Sometimes the first attempt ends with the error, but the operation was performed.
+// In that case, the retry ends with a "not found" error.
+// The error should be ignored, because the DELETE operation was performed.
+//
+// Non-idempotent requests, for example CREATE operations, are not retried in such cases,
+// see request.RetryConfig.NonIdempotentCondition.
+func ignoreResourceNotFoundError() func(context.Context, request.HTTPResponse, error) error {
+	return func(_ context.Context, response request.HTTPResponse, err error) error {
+		rawResponse := response.RawResponse()
+		defer rawResponse.Body.Close()
+		if isResourceNotFoundError(rawResponse, err) {
+			return nil
+		}
+		return err
+	}
+}
+
+func isResourceNotFoundError(response *http.Response, err error) bool {
+	var storageAPIError *StorageError
+
+	// There must be an HTTP response
+	if response == nil {
+		return false
+	}
+
+	// There must be an HTTP request
+	if response.Request == nil {
+		return false
+	}
+
+	// There must be a retry, so the operation was performed but the HTTP request ended with an error.
+	if attempt, _ := client.ContextRetryAttempt(response.Request.Context()); attempt == 0 {
+		return false
+	}
+
+	// It must be a Storage API error
+	if !errors.As(err, &storageAPIError) {
+		return false
+	}
+
+	// The error HTTP code must match
+	if response.StatusCode != http.StatusNotFound {
+		return false
+	}
+
+	// The error code must match, for example "storage.bucket.notFound"
+	if !strings.HasSuffix(storageAPIError.ErrCode, "notFound") {
+		return false
+	}
+
+	return true
+}
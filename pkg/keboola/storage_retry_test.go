@@ -13,31 +13,10 @@ import (
 	"github.com/stretchr/testify/assert"
 
 	"github.com/keboola/go-client/pkg/client"
+	"github.com/keboola/go-client/pkg/client/trace"
+	"github.com/keboola/go-client/pkg/request"
 )
 
-func TestIsResourceAlreadyExistsError(t *testing.T) {
-	t.Parallel()
-	assert.False(t, isResourceAlreadyExistsError(
-		&http.Response{},
-		errors.New("foo bar"),
-	))
-	assert.False(t, isResourceAlreadyExistsError(
-		&http.Response{StatusCode: http.StatusBadRequest},
-		errors.New("foo bar"),
-	))
-	assert.False(t, isResourceAlreadyExistsError(
-		&http.Response{StatusCode: http.StatusBadRequest},
-		fmt.Errorf("foo: %w", &StorageError{ErrCode: "foo.notFound"}),
-	))
-	assert.True(t, isResourceAlreadyExistsError(
-		&http.Response{
-			StatusCode: http.StatusBadRequest,
-			Request:    (&http.Request{}).WithContext(context.WithValue(context.Background(), client.RetryAttemptContextKey, 1)),
-		},
-		fmt.Errorf("foo: %w", &StorageError{ErrCode: "fooAlreadyExists"}),
-	))
-}
-
 func TestIsResourceNotFoundError(t *testing.T) {
 	t.Parallel()
 	assert.False(t, isResourceNotFoundError(
@@ -61,7 +40,7 @@ func TestIsResourceNotFoundError(t *testing.T) {
 	))
 }
 
-func TestHack_CreateConfigRequest_AlreadyExists(t *testing.T) {
+func TestRetry_CreateConfigRequest_NotRetried(t *testing.T) {
 	t.Parallel()
 	// Mocked response
 	transport := httpmock.NewMockTransport()
@@ -72,30 +51,12 @@ func TestHack_CreateConfigRequest_AlreadyExists(t *testing.T) {
 	transport.RegisterResponder(
 		http.MethodPost,
 		`https://connection.keboola.com/v2/storage/branch/123/components/foo.bar/configs`,
-		httpmock.ResponderFromMultipleResponses([]*http.Response{
-			{
-				StatusCode: http.StatusInternalServerError,
-				Body:       io.NopCloser(strings.NewReader("internal error")),
-			},
-			{
-				StatusCode: http.StatusBadRequest,
-				Header:     map[string][]string{"Content-Type": {"application/json"}},
-				Body:       io.NopCloser(strings.NewReader(`{"code": "configurationAlreadyExists"}`)),
-			},
-		}),
-	)
-	transport.RegisterResponder(
-		http.MethodGet,
-		`https://connection.keboola.com/v2/storage/branch/123/components/foo.bar/configs/123`,
-		httpmock.NewJsonResponderOrPanic(http.StatusOK, map[string]any{
-			"componentId": "foo.bar",
-			"id":          "123",
-			"name":        "Name from the GET response",
-		}),
+		httpmock.NewStringResponder(http.StatusInternalServerError, "internal error"),
 	)
 
 	// Create client
-	c := client.New().WithTransport(transport).WithRetry(client.TestingRetry())
+	var retries []int
+	c := client.New().WithTransport(transport).WithRetry(client.TestingRetry()).AndTrace(retryTracer(&retries))
 	api, err := NewAuthorizedAPI(context.Background(), "https://connection.keboola.com", "my-token", WithClient(&c))
 	assert.NoError(t, err)
 
@@ -103,19 +64,20 @@ func TestHack_CreateConfigRequest_AlreadyExists(t *testing.T) {
 	config := &ConfigWithRows{Config: &Config{ConfigKey: ConfigKey{BranchID: 123, ComponentID: "foo.bar", ID: "123"}}}
 	_, err = api.CreateConfigRequest(config, true).Send(context.Background())
 
-	// The request ended without an error, the config was loaded via a GET request
-	assert.NoError(t, err)
-	assert.Equal(t, "Name from the GET response", config.Name)
+	// The POST request is not idempotent, so it is not retried, the operation may have been performed
+	if assert.Error(t, err) {
+		assert.Equal(t, `request POST "https://connection.keboola.com/v2/storage/branch/123/components/foo.bar/configs" failed: 500 Internal Server Error`, err.Error())
+	}
+	assert.Empty(t, retries)
 
 	// Check HTTP requests count
 	assert.Equal(t, map[string]int{
-		"GET https://connection.keboola.com/v2/storage/?exclude=components":                       1,
-		"POST https://connection.keboola.com/v2/storage/branch/123/components/foo.bar/configs":    2,
-		"GET https://connection.keboola.com/v2/storage/branch/123/components/foo.bar/configs/123": 1,
+		"GET https://connection.keboola.com/v2/storage/?exclude=components":                    1,
+		"POST https://connection.keboola.com/v2/storage/branch/123/components/foo.bar/configs": 1,
 	}, transport.GetCallCountInfo())
 }
 
-func TestHack_DeleteTableRequest_NotFound(t *testing.T) {
+func TestRetry_DeleteTableRequest_NotFound(t *testing.T) {
 	t.Parallel()
 	// Mocked response
 	transport := httpmock.NewMockTransport()
@@ -140,7 +102,8 @@ func TestHack_DeleteTableRequest_NotFound(t *testing.T) {
 	)
 
 	// Create client
-	c := client.New().WithTransport(transport).WithRetry(client.TestingRetry())
+	var retries []int
+	c := client.New().WithTransport(transport).WithRetry(client.TestingRetry()).AndTrace(retryTracer(&retries))
 	api, err := NewAuthorizedAPI(context.Background(), "https://connection.keboola.com", "my-token", WithClient(&c))
 	assert.NoError(t, err)
 
@@ -148,8 +111,9 @@ func TestHack_DeleteTableRequest_NotFound(t *testing.T) {
 	k := TableKey{BranchID: 123, TableID: MustParseTableID("in.c-bucket.table")}
 	_, err = api.DeleteTableRequest(k).Send(context.Background())
 
-	// The request ended without an error
+	// The request ended without an error, the retry of the DELETE request has been reported
 	assert.NoError(t, err)
+	assert.Equal(t, []int{1}, retries)
 
 	// Check HTTP requests count
 	assert.Equal(t, map[string]int{
@@ -157,3 +121,14 @@ func TestHack_DeleteTableRequest_NotFound(t *testing.T) {
 		"DELETE https://connection.keboola.com/v2/storage/branch/123/tables/in.c-bucket.table": 2,
 	}, transport.GetCallCountInfo())
 }
+
+// retryTracer collects attempts of retried mutations.
+func retryTracer(attempts *[]int) trace.Factory {
+	return func(ctx context.Context, _ request.HTTPRequest) (context.Context, *trace.ClientTrace) {
+		return ctx, &trace.ClientTrace{
+			MutationRetry: func(_ *http.Request, attempt int, _ *http.Response, _ error) {
+				*attempts = append(*attempts, attempt)
+			},
+		}
+	}
+}
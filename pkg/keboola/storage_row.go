@@ -47,13 +47,12 @@ func (a *AuthorizedAPI) GetConfigRowRequest(key ConfigRowKey) request.APIRequest
 	row.ConfigID = key.ConfigID
 	req := a.
 		newRequest(StorageAPI).
-		WithResult(row).
 		WithGet("branch/{branchId}/components/{componentId}/configs/{configId}/rows/{rowId}").
 		AndPathParam("branchId", key.BranchID.String()).
 		AndPathParam("componentId", key.ComponentID.String()).
 		AndPathParam("configId", key.ConfigID.String()).
 		AndPathParam("rowId", key.ID.String())
-	return request.NewAPIRequest(row, req)
+	return request.NewTypedHTTPRequest(req, row).APIRequest()
 }
 
 // ListConfigRowRequest https://keboola.docs.apiary.io/#reference/components-and-configurations/create-or-list-configuration-rows/configuration-row-list
@@ -61,7 +60,6 @@ func (a *AuthorizedAPI) ListConfigRowRequest(key ConfigRowKey) request.APIReques
 	result := make([]*ConfigRow, 0)
 	req := a.
 		newRequest(StorageAPI).
-		WithResult(&result).
 		WithGet("branch/{branchId}/components/{componentId}/configs/{configId}/rows").
 		AndPathParam("branchId", key.BranchID.String()).
 		AndPathParam("componentId", key.ComponentID.String()).
@@ -74,7 +72,7 @@ func (a *AuthorizedAPI) ListConfigRowRequest(key ConfigRowKey) request.APIReques
 			}
 			return nil
 		})
-	return request.NewAPIRequest(&result, req)
+	return request.NewTypedHTTPRequest(req, &result).APIRequest()
 }
 
 // CreateConfigRowRequest https://kebooldocs.apiary.io/#reference/components-and-configurations/create-or-list-configuration-rows/create-development-branch-configuration-row
@@ -82,21 +80,12 @@ func (a *AuthorizedAPI) CreateConfigRowRequest(row *ConfigRow) request.APIReques
 	// Create request
 	req := a.
 		newRequest(StorageAPI).
-		WithResult(row).
 		WithPost("branch/{branchId}/components/{componentId}/configs/{configId}/rows").
 		AndPathParam("branchId", row.BranchID.String()).
 		AndPathParam("componentId", string(row.ComponentID)).
 		AndPathParam("configId", string(row.ConfigID)).
-		WithJSONBody(request.StructToMap(row, nil)).
-		WithOnError(ignoreResourceAlreadyExistsError(func(ctx context.Context) error {
-			if result, err := a.GetConfigRowRequest(row.ConfigRowKey).Send(ctx); err == nil {
-				*row = *result
-				return nil
-			} else {
-				return err
-			}
-		}))
-	return request.NewAPIRequest(row, req)
+		WithJSONBody(request.StructToMap(row, nil))
+	return request.NewTypedHTTPRequest(req, row).APIRequest()
 }
 
 // UpdateConfigRowRequest https://kebooldocs.apiary.io/#reference/components-and-configurations/manage-configuration-rows/update-row-for-development-branch
@@ -109,14 +98,13 @@ func (a *AuthorizedAPI) UpdateConfigRowRequest(row *ConfigRow, changedFields []s
 	// Create request
 	req := a.
 		newRequest(StorageAPI).
-		WithResult(row).
 		WithPut("branch/{branchId}/components/{componentId}/configs/{configId}/rows/{rowId}").
 		AndPathParam("branchId", row.BranchID.String()).
 		AndPathParam("componentId", string(row.ComponentID)).
 		AndPathParam("configId", string(row.ConfigID)).
 		AndPathParam("rowId", string(row.ID)).
 		WithJSONBody(request.StructToMap(row, changedFields))
-	return request.NewAPIRequest(row, req)
+	return request.NewTypedHTTPRequest(req, row).APIRequest()
 }
 
 // DeleteConfigRowRequest https://kebooldocs.apiary.io/#reference/components-and-configurations/manage-configuration-rows/update-row
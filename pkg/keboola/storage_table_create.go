@@ -52,7 +52,6 @@ func (a *AuthorizedAPI) CreateTableFromFileRequest(tableKey TableKey, fileKey Fi
 	table := &Table{TableKey: tableKey}
 	req := a.
 		newRequest(StorageAPI).
-		WithResult(job).
 		WithPost("branch/{branchId}/buckets/{bucketId}/tables-async").
 		AndPathParam("branchId", tableKey.BranchID.String()).
 		AndPathParam("bucketId", tableKey.TableID.BucketID.String()).
@@ -75,7 +74,7 @@ func (a *AuthorizedAPI) CreateTableFromFileRequest(tableKey TableKey, fileKey Fi
 			return nil
 		})
 
-	return request.NewAPIRequest(table, req)
+	return request.NewAPIRequest(table, request.NewTypedHTTPRequest(req, job))
 }
 
 func writeHeaderToCSV(ctx context.Context, file *FileUploadCredentials, columns []string) (err error) {
@@ -143,12 +142,11 @@ func (a *AuthorizedAPI) CreateTableDefinitionAsyncRequest(k TableKey, definition
 	result := &StorageJob{}
 	req := a.
 		newRequest(StorageAPI).
-		WithResult(result).
 		WithPost("branch/{branchId}/buckets/{bucketId}/tables-definition").
 		AndPathParam("branchId", k.BranchID.String()).
 		AndPathParam("bucketId", k.BucketKey().BucketID.String()).
 		WithJSONBody(CreateTableRequest{TableDefinition: definition, Name: k.TableID.TableName})
-	return request.NewAPIRequest(result, req)
+	return request.NewTypedHTTPRequest(req, result).APIRequest()
 }
 
 func (a *AuthorizedAPI) CreateTableDefinitionRequest(k TableKey, definition TableDefinition) request.APIRequest[*Table] {
@@ -8,9 +8,8 @@ func (a *AuthorizedAPI) GetTableRequest(k TableKey) request.APIRequest[*Table] {
 	table := &Table{TableKey: k, Bucket: &Bucket{BucketKey: bucketKey}}
 	req := a.
 		newRequest(StorageAPI).
-		WithResult(table).
 		WithGet("branch/{branchId}/tables/{tableId}").
 		AndPathParam("branchId", k.BranchID.String()).
 		AndPathParam("tableId", k.TableID.String())
-	return request.NewAPIRequest(table, req)
+	return request.NewTypedHTTPRequest(req, table).APIRequest()
 }
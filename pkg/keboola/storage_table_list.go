@@ -57,7 +57,6 @@ func (a *AuthorizedAPI) ListTablesRequest(branchID BranchID, opts ...ListTableOp
 	result := make([]*Table, 0)
 	req := a.
 		newRequest(StorageAPI).
-		WithResult(&result).
 		WithGet("branch/{branchId}/tables").
 		AndPathParam("branchId", branchID.String()).
 		AndQueryParam("include", config.includeString()).
@@ -68,5 +67,26 @@ func (a *AuthorizedAPI) ListTablesRequest(branchID BranchID, opts ...ListTableOp
 			return nil
 		})
 
-	return request.NewAPIRequest(&result, req)
+	return request.NewTypedHTTPRequest(req, &result).APIRequest()
+}
+
+// StreamTablesRequest is the same as ListTablesRequest, but the tables are decoded one by one and passed to the callback.
+// It keeps the memory usage low for projects with many tables, for example with the WithColumnMetadata option.
+func (a *AuthorizedAPI) StreamTablesRequest(branchID BranchID, fn func(table *Table) error, opts ...ListTableOption) request.APIRequest[request.NoResult] {
+	config := listTablesConfig{include: make(map[string]bool)}
+	for _, opt := range opts {
+		opt(&config)
+	}
+
+	result := request.NewStreamResult(func(table *Table) error {
+		table.BranchID = branchID
+		return fn(table)
+	})
+	req := a.
+		newRequest(StorageAPI).
+		WithGet("branch/{branchId}/tables").
+		AndPathParam("branchId", branchID.String()).
+		AndQueryParam("include", config.includeString())
+
+	return request.NewAPIRequest(request.NoResult{}, request.NewTypedHTTPRequest(req, result))
 }
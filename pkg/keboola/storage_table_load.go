@@ -26,11 +26,10 @@ func (a *AuthorizedAPI) LoadDataFromFileRequest(tableKey TableKey, fileKey FileK
 	job := &StorageJob{}
 	req := a.
 		newRequest(StorageAPI).
-		WithResult(job).
 		WithPost("branch/{branchId}/tables/{tableId}/import-async").
 		AndPathParam("branchId", tableKey.BranchID.String()).
 		AndPathParam("tableId", tableKey.TableID.String()).
 		WithJSONBody(params)
 
-	return request.NewAPIRequest(job, req)
+	return request.NewTypedHTTPRequest(req, job).APIRequest()
 }
@@ -57,11 +57,10 @@ func (a *AuthorizedAPI) CreateOrUpdateTableMetadata(k TableKey, provider string,
 	result := &TableMetadataResponse{}
 	req := a.
 		newRequest(StorageAPI).
-		WithResult(result).
 		WithPost("branch/{branchId}/tables/{tableId}/metadata").
 		AndPathParam("branchId", k.BranchID.String()).
 		AndPathParam("tableId", k.TableID.String()).
 		WithJSONBody(params)
 
-	return request.NewAPIRequest(result, req)
+	return request.NewTypedHTTPRequest(req, result).APIRequest()
 }
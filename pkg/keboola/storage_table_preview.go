@@ -1,12 +1,11 @@
 package keboola
 
 import (
-	"bytes"
-	"context"
-	"encoding/csv"
+	"errors"
 	"fmt"
 	"strings"
 
+	"github.com/keboola/go-client/pkg/client"
 	"github.com/keboola/go-client/pkg/request"
 )
 
@@ -15,6 +14,16 @@ type TablePreview struct {
 	Rows    [][]string
 }
 
+// UnmarshalCSV implements the client.CSVUnmarshaler interface, the first record is the header.
+func (v *TablePreview) UnmarshalCSV(records [][]string) error {
+	if len(records) == 0 {
+		return errors.New("table preview CSV is empty, the header is missing")
+	}
+	v.Columns = records[0]
+	v.Rows = records[1:]
+	return nil
+}
+
 type previewDataConfig struct {
 	limit        uint
 	changedSince *string
@@ -306,25 +315,13 @@ func (a *AuthorizedAPI) PreviewTableRequest(k TableKey, opts ...PreviewOption) r
 	}
 
 	data := &TablePreview{}
-	responseBytes := []byte{}
 	req := a.
 		newRequest(StorageAPI).
-		WithResult(&responseBytes).
 		WithGet("branch/{branchId}/tables/{tableId}/data-preview").
 		AndPathParam("branchId", k.BranchID.String()).
 		AndPathParam("tableId", k.TableID.String()).
 		WithQueryParams(config.toQueryParams()).
-		WithOnSuccess(func(ctx context.Context, response request.HTTPResponse) error {
-			records, err := csv.NewReader(bytes.NewReader(responseBytes)).ReadAll()
-			if err != nil {
-				return fmt.Errorf("failed to read body csv: %w", err)
-			}
-
-			data.Columns = records[0]
-			data.Rows = records[1:]
-
-			return nil
-		})
+		WithResponseMediaType(client.ContentTypeTextCSV)
 
-	return request.NewAPIRequest(data, req)
+	return request.NewTypedHTTPRequest(req, data).APIRequest()
 }
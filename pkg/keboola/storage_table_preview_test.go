@@ -5,11 +5,15 @@ import (
 	"context"
 	"fmt"
 	"math/rand"
+	"net/http"
 	"net/url"
 	"testing"
 
+	"github.com/jarcoal/httpmock"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+
+	"github.com/keboola/go-client/pkg/client"
 )
 
 func TestPreviewTableRequestOptions(t *testing.T) {
@@ -271,3 +275,35 @@ func TestPreviewTableRequest(t *testing.T) {
 		preview,
 	)
 }
+
+func TestTablePreview_UnmarshalCSV(t *testing.T) {
+	t.Parallel()
+
+	preview := &TablePreview{}
+	require.NoError(t, preview.UnmarshalCSV([][]string{{"id", "name"}, {"1", "foo"}, {"2", "bar"}}))
+	assert.Equal(t, &TablePreview{Columns: []string{"id", "name"}, Rows: [][]string{{"1", "foo"}, {"2", "bar"}}}, preview)
+
+	assert.Error(t, (&TablePreview{}).UnmarshalCSV(nil))
+}
+
+func TestPreviewTableRequest_ContentType(t *testing.T) {
+	t.Parallel()
+
+	// Mocked API, the content type of the preview is not "text/csv"
+	c, transport := client.NewMockedClient()
+	transport.RegisterResponder(http.MethodGet, `/v2/storage/?exclude=components`, httpmock.NewJsonResponderOrPanic(http.StatusOK, &Index{}))
+	transport.RegisterResponder(http.MethodGet, `=~/data-preview`, func(req *http.Request) (*http.Response, error) {
+		res := httpmock.NewStringResponse(http.StatusOK, "id,value\n1,foo\n")
+		res.Header.Set("Content-Type", "application/octet-stream")
+		return res, nil
+	})
+	ctx := context.Background()
+	api, err := NewAuthorizedAPI(ctx, "https://connection.keboola.mock", "my-token", WithClient(&c))
+	require.NoError(t, err)
+
+	// The preview is decoded as CSV regardless of the content type
+	tableKey := TableKey{BranchID: 123, TableID: MustParseTableID("in.c-bucket.table")}
+	preview, err := api.PreviewTableRequest(tableKey).Send(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, &TablePreview{Columns: []string{"id", "value"}, Rows: [][]string{{"1", "foo"}}}, preview)
+}
@@ -103,6 +103,18 @@ func TestTableApiCalls(t *testing.T) {
 	}
 	assert.True(t, tableFound)
 
+	// Stream tables
+	tableFound = false
+	err = api.StreamTablesRequest(defBranch.ID, func(resTable *Table) error {
+		assert.Equal(t, resTable.BranchID, defBranch.ID)
+		if resTable.TableID == table.TableID {
+			tableFound = true
+		}
+		return nil
+	}).SendOrErr(ctx)
+	assert.NoError(t, err)
+	assert.True(t, tableFound)
+
 	// Get table (without table and columns metadata)
 	respGet1, err := api.GetTableRequest(tableKey).Send(ctx)
 	assert.NoError(t, err)
@@ -45,13 +45,12 @@ func (a *AuthorizedAPI) NewTableUnloadRequest(k TableKey) *TableUnloadRequestBui
 func (b *TableUnloadRequestBuilder) Build() request.APIRequest[*StorageJob] {
 	result := &StorageJob{}
 	req := b.api.newRequest(StorageAPI).
-		WithResult(result).
 		WithMethod(http.MethodPost).
 		WithURL("branch/{branchId}/tables/{tableId}/export-async").
 		AndPathParam("branchId", b.tableKey.BranchID.String()).
 		AndPathParam("tableId", b.tableKey.TableID.String()).
 		WithJSONBody(b.config)
-	return request.NewAPIRequest(result, req)
+	return request.NewTypedHTTPRequest(req, result).APIRequest()
 }
 
 func (b *TableUnloadRequestBuilder) Send(ctx context.Context) (*StorageJob, error) {
@@ -18,9 +18,8 @@ func (a *AuthorizedAPI) GenerateIDRequest() request.APIRequest[*Ticket] {
 	result := &Ticket{}
 	req := a.
 		newRequest(StorageAPI).
-		WithResult(result).
 		WithPost("tickets")
-	return request.NewAPIRequest(result, req)
+	return request.NewTypedHTTPRequest(req, result).APIRequest()
 }
 
 // TicketProvider generates new IDs and GUARANTEES that the IDs will be returned with the same order as the Request method was called.
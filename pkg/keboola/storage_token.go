@@ -139,14 +139,13 @@ func (a *PublicAPI) VerifyTokenRequest(token string) request.APIRequest[*Token]
 	result := &Token{}
 	req := a.
 		newRequest(StorageAPI).
-		WithResult(result).
 		WithGet("tokens/verify").
 		AndHeader("X-StorageApi-Token", token).
 		WithOnSuccess(func(_ context.Context, _ request.HTTPResponse) error {
 			result.Token = token
 			return nil
 		})
-	return request.NewAPIRequest(result, req)
+	return request.NewTypedHTTPRequest(req, result).APIRequest()
 }
 
 // TokenDetailRequest https://keboola.docs.apiary.io/#reference/tokens-and-permissions/token/token-detail
@@ -154,10 +153,9 @@ func (a *AuthorizedAPI) TokenDetailRequest(tokenID string) request.APIRequest[*T
 	result := &Token{}
 	req := a.
 		newRequest(StorageAPI).
-		WithResult(result).
 		WithGet("tokens/{tokenId}").
 		AndPathParam("tokenId", tokenID)
-	return request.NewAPIRequest(result, req)
+	return request.NewTypedHTTPRequest(req, result).APIRequest()
 }	
 
 // CreateTokenRequest https://keboola.docs.apiary.io/#reference/tokens-and-permissions/tokens-collection/create-token
@@ -170,10 +168,9 @@ func (a *AuthorizedAPI) CreateTokenRequest(opts ...CreateTokenOption) request.AP
 	result := &Token{}
 	req := a.
 		newRequest(StorageAPI).
-		WithResult(result).
 		WithPost("tokens").
 		WithJSONBody(request.StructToMap(options, nil))
-	return request.NewAPIRequest(result, req)
+	return request.NewTypedHTTPRequest(req, result).APIRequest()
 }
 
 // ListTokensRequest https://keboola.docs.apiary.io/#reference/tokens-and-permissions/tokens-collection/list-all-tokens
@@ -181,9 +178,8 @@ func (a *AuthorizedAPI) ListTokensRequest() request.APIRequest[*[]*Token] {
 	var result []*Token
 	req := a.
 		newRequest(StorageAPI).
-		WithResult(&result).
 		WithGet("tokens")
-	return request.NewAPIRequest(&result, req)
+	return request.NewTypedHTTPRequest(req, &result).APIRequest()
 }
 
 // DeleteTokenRequest (no documentation).
@@ -191,11 +187,10 @@ func (a *AuthorizedAPI) DeleteTokenRequest(tokenID string) request.APIRequest[*T
 	result := &Token{}
 	req := a.
 		newRequest(StorageAPI).
-		WithResult(result).
 		WithDelete("tokens/{tokenId}").
 		AndPathParam("tokenId", tokenID).
 		WithOnError(ignoreResourceNotFoundError())
-	return request.NewAPIRequest(result, req)
+	return request.NewTypedHTTPRequest(req, result).APIRequest()
 }
 
 // RefreshTokenRequest https://keboola.docs.apiary.io/#reference/tokens-and-permissions/share-token/refresh-token
@@ -203,10 +198,9 @@ func (a *AuthorizedAPI) RefreshTokenRequest(tokenID string) request.APIRequest[*
 	result := &Token{}
 	req := a.
 		newRequest(StorageAPI).
-		WithResult(result).
 		WithPost("tokens/{tokenId}/refresh").
 		AndPathParam("tokenId", tokenID)
-	return request.NewAPIRequest(result, req)
+	return request.NewTypedHTTPRequest(req, result).APIRequest()
 }
 
 // ProjectID returns ID of project to which the token belongs.
@@ -45,10 +45,9 @@ type WorkspaceDetails struct {
 func (a *AuthorizedAPI) GetWorkspaceInstanceRequest(workspaceID WorkspaceID) request.APIRequest[*Workspace] {
 	result := &Workspace{}
 	req := a.newRequest(WorkspacesAPI).
-		WithResult(&result).
 		WithGet(WorkspacesAPISandbox).
 		AndPathParam("sandboxId", workspaceID.String())
-	return request.NewAPIRequest(result, req)
+	return request.NewTypedHTTPRequest(req, result).APIRequest()
 }
 
 // ListWorkspaceInstancesRequest returns a list of all workspaces
@@ -56,9 +55,8 @@ func (a *AuthorizedAPI) GetWorkspaceInstanceRequest(workspaceID WorkspaceID) req
 func (a *AuthorizedAPI) ListWorkspaceInstancesRequest() request.APIRequest[*[]*Workspace] {
 	result := make([]*Workspace, 0)
 	req := a.newRequest(WorkspacesAPI).
-		WithResult(&result).
 		WithGet(WorkspacesAPISandboxes)
-	return request.NewAPIRequest(&result, req)
+	return request.NewTypedHTTPRequest(req, &result).APIRequest()
 }
 
 func (a *AuthorizedAPI) CleanWorkspaceInstances(ctx context.Context) error {
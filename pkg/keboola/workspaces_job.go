@@ -64,7 +64,7 @@ func (a *AuthorizedAPI) CreateWorkspaceJobRequest(configID ConfigID, workspaceTy
 	params := newParams(workspaceType, opts...)
 	req := a.CreateQueueJobConfigDataRequest(WorkspacesComponent, configID, map[string]any{"parameters": params.toMap()}).
 		WithOnSuccess(func(ctx context.Context, result *QueueJob) error {
-			return a.WaitForQueueJob(ctx, result.ID)
+			return a.WaitForCreatedQueueJob(ctx, result)
 		})
 	return request.NewAPIRequest(request.NoResult{}, req)
 }
@@ -78,7 +78,7 @@ func (a *AuthorizedAPI) DeleteWorkspaceJobRequest(workspaceID WorkspaceID) reque
 	}
 	req := a.CreateQueueJobConfigDataRequest(WorkspacesComponent, "", configData).
 		WithOnSuccess(func(ctx context.Context, result *QueueJob) error {
-			return a.WaitForQueueJob(ctx, result.ID)
+			return a.WaitForCreatedQueueJob(ctx, result)
 		})
 	return request.NewAPIRequest(request.NoResult{}, req)
 }
@@ -24,8 +24,12 @@ const (
 	attrSpanKindValueClient = "client"
 	attrSpanType            = attribute.Key("span.type")
 	attrSpanTypeValueHTTP   = "http"
+	// definedInContextKey - see ContextDefinedIn.
+	definedInContextKey = contextKey("apiRequestDefinedIn")
 )
 
+type contextKey string
+
 // APIRequest with response mapped to the generic type R.
 type APIRequest[R Result] interface {
 	// WithBefore method registers callback to be executed before the request.
@@ -64,20 +68,33 @@ func (v ParallelAPIRequests) SendOrErr(ctx context.Context) error {
 // NewAPIRequest creates an API request with the result mapped to the R type.
 // It is composed of one or multiple Sendable (HTTPRequest or APIRequest).
 func NewAPIRequest[R Result](result R, requests ...Sendable) APIRequest[R] {
+	return newAPIRequest(result, callerName(2), requests...)
+}
+
+func newAPIRequest[R Result](result R, definedIn string, requests ...Sendable) APIRequest[R] {
 	if len(requests) == 0 {
 		panic(fmt.Errorf("at least one request must be provided"))
 	}
+	return &apiRequest[R]{requests: requests, result: result, definedIn: definedIn}
+}
 
-	// Get name of the caller function
-	var definedIn string
-	if pc, _, _, ok := runtime.Caller(1); ok {
+// callerName returns name of the function, where the request was defined, the skip is the same as in runtime.Caller.
+func callerName(skip int) string {
+	if pc, _, _, ok := runtime.Caller(skip); ok {
 		if details := runtime.FuncForPC(pc); details != nil {
 			fn := details.Name()
-			definedIn = strings.TrimLeft(fn[strings.LastIndex(fn, "/"):], "/")
+			return strings.TrimLeft(fn[strings.LastIndex(fn, "/"):], "/")
 		}
 	}
+	return ""
+}
 
-	return &apiRequest[R]{requests: requests, result: result, definedIn: definedIn}
+// ContextDefinedIn returns name of the function, where the APIRequest being sent was defined.
+// For nested requests, the innermost APIRequest is returned.
+// It links HTTP requests, for example in a trace.Factory, to the API request.
+func ContextDefinedIn(ctx context.Context) (string, bool) {
+	v, ok := ctx.Value(definedInContextKey).(string)
+	return v, ok
 }
 
 // NewNoOperationAPIRequest returns an APIRequest that immediately returns a Result without calling any HTTPRequest.
@@ -92,6 +109,8 @@ type apiRequest[R Result] struct {
 	before   []func(ctx context.Context) error
 	after    []func(ctx context.Context, result R, err error) error
 	result   R
+	// send is an optional function which replaces the requests, it is used by the combinators, see Map and Then.
+	send func(ctx context.Context) (R, error)
 	// definedIn is optional name of the function, where the request was defined
 	definedIn string
 }
@@ -165,6 +184,7 @@ func (r apiRequest[R]) Send(ctx context.Context) (result R, err error) {
 			attrResourceName.String(r.definedIn),
 			attrRequestDefinedIn.String(r.definedIn),
 		)
+		ctx = context.WithValue(ctx, definedInContextKey, r.definedIn)
 	}
 
 	// Stop if context has been cancelled
@@ -185,24 +205,27 @@ func (r apiRequest[R]) Send(ctx context.Context) (result R, err error) {
 	}
 
 	// Send requests in parallel
-	wg := NewWaitGroup(ctx)
-	for _, request := range r.requests {
-		wg.Send(request)
+	result = r.result
+	if r.send != nil {
+		result, err = r.send(ctx)
+	} else {
+		wg := NewWaitGroup(ctx)
+		for _, request := range r.requests {
+			wg.Send(request)
+		}
+		err = wg.Wait()
 	}
 
-	// Process error by listener, if any
-	err = wg.Wait()
-
 	// Invoke "after" listeners
 	for _, fn := range r.after {
 		// Stop if context has been cancelled
 		if err := ctx.Err(); err != nil {
-			return r.result, err
+			return result, err
 		}
-		err = fn(ctx, r.result, err)
+		err = fn(ctx, result, err)
 	}
 
-	return r.result, err
+	return result, err
 }
 
 func (r apiRequest[R]) SendOrErr(ctx context.Context) error {
@@ -0,0 +1,70 @@
+package request
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"regexp"
+	"strings"
+
+	jsoniter "github.com/json-iterator/go"
+)
+
+// ContentTypeApplicationJSONRegexp matches JSON content types, for example "application/json" or "application/vnd.api+json".
+const ContentTypeApplicationJSONRegexp = `^application/([a-zA-Z0-9\.\-]+\+)?json$`
+
+var jsonContentTypeRegexp = regexp.MustCompile(ContentTypeApplicationJSONRegexp)
+
+// IsJSONContentType returns true, if the media type is a JSON content type, see ContentTypeApplicationJSONRegexp.
+func IsJSONContentType(contentType string) bool {
+	return jsonContentTypeRegexp.MatchString(contentType)
+}
+
+// EncodeBody returns a new stream of the request body, the body is encoded according to its type.
+//
+// Supported types are string, []byte, *MultipartBody, io.ReadSeeker, which is rewound,
+// and any value if the Content-Type header is a JSON content type, the value is encoded as JSON.
+// The body is not compressed, see HTTPRequest.WithCompressedBody.
+// Nil reader is returned, if the body is empty.
+func EncodeBody(r HTTPRequest) (io.ReadCloser, error) {
+	contentType := r.RequestHeader().Get("Content-Type")
+	body := r.RequestBody()
+	if v, ok := body.(string); ok {
+		return io.NopCloser(strings.NewReader(v)), nil
+	}
+	if v, ok := body.([]byte); ok {
+		return io.NopCloser(bytes.NewReader(v)), nil
+	}
+	if v, ok := body.(*MultipartBody); ok {
+		// multipart body, streamed
+		return v.Reader()
+	}
+	if v, ok := body.(io.ReadSeekCloser); ok {
+		// io.ReadSeekCloser stream
+		if _, err := v.Seek(0, io.SeekStart); err != nil {
+			return nil, err
+		}
+		return v, nil
+	}
+	if v, ok := body.(io.ReadSeeker); ok {
+		// io.ReadSeeker stream
+		if _, err := v.Seek(0, io.SeekStart); err != nil {
+			return nil, err
+		}
+		return io.NopCloser(v), nil
+	}
+	if body != nil && IsJSONContentType(contentType) {
+		// Json body, the same jsoniter config as in the client package
+		c, err := jsoniter.ConfigCompatibleWithStandardLibrary.Marshal(body)
+		if err != nil {
+			return nil, fmt.Errorf(`cannot encode JSON body: %w`, err)
+		}
+		return io.NopCloser(bytes.NewReader(c)), nil
+	}
+	if body == nil {
+		// empty body
+		return nil, nil
+	}
+	// unsupported body
+	return nil, fmt.Errorf(`unsupported request body type "%T", to encode body as JSON please specify content-type header`, body)
+}
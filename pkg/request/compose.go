@@ -0,0 +1,36 @@
+package request
+
+import (
+	"context"
+)
+
+// Map creates an APIRequest, which sends the request and maps its result by the function.
+// The function is called only if the request succeeds.
+func Map[A, B Result](request APIRequest[A], fn func(result A) B) APIRequest[B] {
+	return &apiRequest[B]{
+		definedIn: callerName(2),
+		send: func(ctx context.Context) (result B, err error) {
+			a, err := request.Send(ctx)
+			if err != nil {
+				return result, err
+			}
+			return fn(a), nil
+		},
+	}
+}
+
+// Then creates an APIRequest, which sends the request and then the request created by the function from its result.
+// The function is called only if the first request succeeds, the result is the result of the second request.
+// Both requests have their own span, they are children of the span of the returned request.
+func Then[A, B Result](request APIRequest[A], fn func(result A) APIRequest[B]) APIRequest[B] {
+	return &apiRequest[B]{
+		definedIn: callerName(2),
+		send: func(ctx context.Context) (result B, err error) {
+			a, err := request.Send(ctx)
+			if err != nil {
+				return result, err
+			}
+			return fn(a).Send(ctx)
+		},
+	}
+}
@@ -0,0 +1,178 @@
+package request_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strconv"
+	"sync"
+	"testing"
+
+	"github.com/jarcoal/httpmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+
+	"github.com/keboola/go-client/pkg/client"
+	"github.com/keboola/go-client/pkg/request"
+)
+
+type composeItem struct {
+	ID   int    `json:"id"`
+	Name string `json:"name"`
+}
+
+func newComposeClient() (client.Client, *httpmock.MockTransport) {
+	c, transport := client.NewMockedClient()
+	transport.RegisterResponder(http.MethodGet, "https://example.com/items/1", httpmock.NewJsonResponderOrPanic(http.StatusOK, composeItem{ID: 1, Name: "one"}))
+	transport.RegisterResponder(http.MethodGet, "https://example.com/items/2", httpmock.NewJsonResponderOrPanic(http.StatusOK, composeItem{ID: 2, Name: "two"}))
+	transport.RegisterResponder(http.MethodGet, "https://example.com/items/3", httpmock.NewStringResponder(http.StatusNotFound, "not found"))
+	return c, transport
+}
+
+func getComposeItem(sender request.Sender, id int) request.APIRequest[*composeItem] {
+	item := &composeItem{}
+	return request.NewAPIRequest(item, request.NewHTTPRequest(sender).WithGet("https://example.com/items/"+strconv.Itoa(id)).WithResult(item))
+}
+
+func TestMap(t *testing.T) {
+	t.Parallel()
+	c, _ := newComposeClient()
+	ctx := context.Background()
+
+	name, err := request.Map(getComposeItem(c, 1), func(item *composeItem) string { return item.Name }).Send(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, "one", name)
+
+	// The function is not called on error
+	_, err = request.Map(getComposeItem(c, 3), func(item *composeItem) string {
+		assert.Fail(t, "unexpected call")
+		return ""
+	}).Send(ctx)
+	require.Error(t, err)
+	assert.Equal(t, `request GET "https://example.com/items/3" failed: 404 Not Found`, err.Error())
+}
+
+func TestThen(t *testing.T) {
+	t.Parallel()
+	c, transport := newComposeClient()
+	ctx := context.Background()
+
+	// The result of the first request is used to create the second request
+	var onSuccess *composeItem
+	item, err := request.
+		Then(getComposeItem(c, 1), func(item *composeItem) request.APIRequest[*composeItem] {
+			return getComposeItem(c, item.ID+1)
+		}).
+		WithOnSuccess(func(_ context.Context, result *composeItem) error {
+			onSuccess = result
+			return nil
+		}).
+		Send(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, &composeItem{ID: 2, Name: "two"}, item)
+	assert.Same(t, item, onSuccess)
+	assert.Equal(t, 2, transport.GetTotalCallCount())
+
+	// The function is not called on error
+	_, err = request.Then(getComposeItem(c, 3), func(item *composeItem) request.APIRequest[*composeItem] {
+		assert.Fail(t, "unexpected call")
+		return nil
+	}).Send(ctx)
+	require.Error(t, err)
+}
+
+func TestDAG(t *testing.T) {
+	t.Parallel()
+
+	// Trace spans
+	spans := tracetest.NewSpanRecorder()
+	tracerProvider := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(spans))
+	ctx, rootSpan := tracerProvider.Tracer("test").Start(context.Background(), "root")
+
+	// Steps record the order
+	var lock sync.Mutex
+	var order []string
+	step := func(name string) request.Sendable {
+		return request.NewNoOperationAPIRequest(request.NoResult{}).WithBefore(func(ctx context.Context) error {
+			lock.Lock()
+			defer lock.Unlock()
+			order = append(order, name)
+			return nil
+		})
+	}
+
+	err := request.DAGWithLimit(2).
+		Add("config", step("config")).
+		Add("row1", step("row1"), "config").
+		Add("row2", step("row2"), "config").
+		Add("job", step("job"), "row1", "row2").
+		Run(ctx)
+	require.NoError(t, err)
+	rootSpan.End()
+
+	require.Len(t, order, 4)
+	assert.Equal(t, "config", order[0])
+	assert.ElementsMatch(t, []string{"row1", "row2"}, order[1:3])
+	assert.Equal(t, "job", order[3])
+
+	// Each step has own span, a child of the DAG span
+	var dagSpan sdktrace.ReadOnlySpan
+	var stepSpans []string
+	for _, span := range spans.Ended() {
+		if span.Name() == request.DAGSpanName {
+			dagSpan = span
+		}
+	}
+	require.NotNil(t, dagSpan)
+	assert.Equal(t, rootSpan.SpanContext().SpanID(), dagSpan.Parent().SpanID())
+	for _, span := range spans.Ended() {
+		if span.Name() == request.DAGStepSpanName {
+			assert.Equal(t, dagSpan.SpanContext().SpanID(), span.Parent().SpanID())
+			for _, attr := range span.Attributes() {
+				if attr.Key == "api.dag.step" {
+					stepSpans = append(stepSpans, attr.Value.AsString())
+				}
+			}
+		}
+	}
+	assert.ElementsMatch(t, []string{"config", "row1", "row2", "job"}, stepSpans)
+}
+
+func TestDAG_Error(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+
+	called := false
+	err := request.NewDAG().
+		Add("a", request.NewNoOperationAPIRequest(request.NoResult{}).WithBefore(func(ctx context.Context) error {
+			return errors.New("some error")
+		})).
+		Add("b", request.NewNoOperationAPIRequest(request.NoResult{}).WithBefore(func(ctx context.Context) error {
+			called = true
+			return nil
+		}), "a").
+		Run(ctx)
+	require.Error(t, err)
+	assert.Equal(t, `DAG step "a" failed: some error`, err.Error())
+	assert.False(t, called)
+}
+
+func TestDAG_Invalid(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	noop := request.NewNoOperationAPIRequest(request.NoResult{})
+
+	err := request.NewDAG().Add("a", noop, "missing").Run(ctx)
+	require.Error(t, err)
+	assert.Equal(t, `DAG step "a" depends on an undefined step "missing"`, err.Error())
+
+	err = request.NewDAG().Add("a", noop, "c").Add("b", noop, "a").Add("c", noop, "b").Run(ctx)
+	require.Error(t, err)
+	assert.Equal(t, `DAG contains a cycle: a -> c -> b -> a`, err.Error())
+
+	assert.PanicsWithError(t, `DAG step "a" is already defined`, func() {
+		request.NewDAG().Add("a", noop).Add("a", noop)
+	})
+}
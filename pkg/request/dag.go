@@ -0,0 +1,192 @@
+package request
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const (
+	DAGSpanName     = "keboola.go.api.client.dag"
+	DAGStepSpanName = "keboola.go.api.client.dag.step"
+	attrDAGStep     = attribute.Key("api.dag.step")
+	attrDAGSteps    = attribute.Key("api.dag.steps_count")
+)
+
+// DAG runs named steps with declared dependencies, see NewDAG function.
+//
+// A step is sent when all its dependencies are successfully completed,
+// independent steps are sent concurrently, up to the concurrency limit, see RunGroup.
+// The run stops when the first error occurs.
+// Each step has its own span, it is a child of the span of the whole run.
+type DAG struct {
+	limit int64
+	steps []*dagStep
+}
+
+type dagStep struct {
+	name      string
+	request   Sendable
+	dependsOn []string
+}
+
+// NewDAG creates an empty DAG with the default concurrency limit, see RunGroupConcurrencyLimit.
+func NewDAG() *DAG {
+	return DAGWithLimit(RunGroupConcurrencyLimit)
+}
+
+// DAGWithLimit creates an empty DAG with the concurrency limit.
+func DAGWithLimit(limit int64) *DAG {
+	return &DAG{limit: limit}
+}
+
+// Add adds the step, it is sent after all the steps it depends on.
+// The dependencies may be added later, they are checked by the Run method.
+func (d *DAG) Add(name string, request Sendable, dependsOn ...string) *DAG {
+	if slices.ContainsFunc(d.steps, func(s *dagStep) bool { return s.name == name }) {
+		panic(fmt.Errorf(`DAG step "%s" is already defined`, name))
+	}
+	d.steps = append(d.steps, &dagStep{name: name, request: request, dependsOn: dependsOn})
+	return d
+}
+
+// Run sends all steps and waits for the result, the first error is returned.
+func (d *DAG) Run(ctx context.Context) (err error) {
+	// Telemetry
+	var span trace.Span
+	tracer := trace.SpanFromContext(ctx).TracerProvider().Tracer(appName)
+	ctx, span = tracer.Start(ctx, DAGSpanName, trace.WithAttributes(attrDAGSteps.Int(len(d.steps))))
+	defer func() {
+		if err != nil {
+			span.RecordError(err)
+			span.SetStatus(codes.Error, err.Error())
+		}
+		span.End()
+	}()
+
+	if err := d.validate(); err != nil {
+		return err
+	}
+
+	// Number of pending dependencies of each step, and dependents of each step
+	var lock sync.Mutex
+	pending := make(map[string]int)
+	dependents := make(map[string][]*dagStep)
+	for _, step := range d.steps {
+		pending[step.name] = len(step.dependsOn)
+		for _, dep := range step.dependsOn {
+			dependents[dep] = append(dependents[dep], step)
+		}
+	}
+
+	// Steps are added to the group, when all dependencies are completed
+	grp := RunGroupWithLimit(ctx, nil, d.limit)
+	var add func(step *dagStep)
+	add = func(step *dagStep) {
+		grp.Add(dagStepRequest{step: step, tracer: tracer, onSuccess: func() {
+			lock.Lock()
+			var ready []*dagStep
+			for _, dependent := range dependents[step.name] {
+				pending[dependent.name]--
+				if pending[dependent.name] == 0 {
+					ready = append(ready, dependent)
+				}
+			}
+			lock.Unlock()
+			for _, s := range ready {
+				add(s)
+			}
+		}})
+	}
+	for _, step := range d.steps {
+		if len(step.dependsOn) == 0 {
+			add(step)
+		}
+	}
+
+	return grp.RunAndWait()
+}
+
+// validate checks, that all dependencies exist and there is no cycle.
+func (d *DAG) validate() error {
+	steps := make(map[string]*dagStep, len(d.steps))
+	for _, step := range d.steps {
+		steps[step.name] = step
+	}
+	for _, step := range d.steps {
+		for _, dep := range step.dependsOn {
+			if _, found := steps[dep]; !found {
+				return fmt.Errorf(`DAG step "%s" depends on an undefined step "%s"`, step.name, dep)
+			}
+		}
+	}
+
+	// Depth-first search, the path contains visited steps on the current path
+	const (
+		unvisited = iota
+		visiting
+		visited
+	)
+	state := make(map[string]int, len(d.steps))
+	var path []string
+	var visit func(name string) error
+	visit = func(name string) error {
+		switch state[name] {
+		case visiting:
+			cycle := append(path[slices.Index(path, name):], name)
+			return fmt.Errorf(`DAG contains a cycle: %s`, strings.Join(cycle, " -> "))
+		case visited:
+			return nil
+		}
+		state[name] = visiting
+		path = append(path, name)
+		for _, dep := range steps[name].dependsOn {
+			if err := visit(dep); err != nil {
+				return err
+			}
+		}
+		path = path[:len(path)-1]
+		state[name] = visited
+		return nil
+	}
+	for _, step := range d.steps {
+		if err := visit(step.name); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// dagStepRequest sends the step in its own span and schedules dependents on success.
+type dagStepRequest struct {
+	step      *dagStep
+	tracer    trace.Tracer
+	onSuccess func()
+}
+
+func (r dagStepRequest) SendOrErr(ctx context.Context) (err error) {
+	var span trace.Span
+	ctx, span = r.tracer.Start(ctx, DAGStepSpanName, trace.WithAttributes(
+		attrResourceName.String(r.step.name),
+		attrDAGStep.String(r.step.name),
+	))
+	defer func() {
+		if err != nil {
+			span.RecordError(err)
+			span.SetStatus(codes.Error, err.Error())
+		}
+		span.End()
+	}()
+
+	if err := r.step.request.SendOrErr(ctx); err != nil {
+		return fmt.Errorf(`DAG step "%s" failed: %w`, r.step.name, err)
+	}
+	r.onSuccess()
+	return nil
+}
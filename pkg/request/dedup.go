@@ -0,0 +1,248 @@
+package request
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"reflect"
+	"slices"
+	"strings"
+	"sync"
+
+	jsoniter "github.com/json-iterator/go"
+	"go.opentelemetry.io/otel/trace"
+)
+
+//nolint:gochecknoglobals
+var (
+	jsonMarshalerType   = reflect.TypeOf((*json.Marshaler)(nil)).Elem()
+	jsonUnmarshalerType = reflect.TypeOf((*json.Unmarshaler)(nil)).Elem()
+	csvUnmarshalerType  = reflect.TypeOf((*csvUnmarshaler)(nil)).Elem()
+	placeholderType     = reflect.TypeOf(Placeholder{})
+)
+
+// csvUnmarshaler is the same interface as the client.CSVUnmarshaler, the client package cannot be imported.
+type csvUnmarshaler interface {
+	UnmarshalCSV(records [][]string) error
+}
+
+// Deduplicator collapses concurrent identical GET requests into one request, see Deduplicator.Sender method.
+//
+// Requests are identical, if they have the same method, URL, query parameters, headers and the result type.
+// The first request is sent, other requests wait for it and receive a copy of its result and a copy of its response without the body.
+// The result is copied to the ResultDef of each request by JSON encoding, so the callers don't share any data.
+// Requests with a result, which is not plain JSON, are never deduplicated, see isPlainJSONType.
+// For example, a streamed result, to an io.Writer or a callback, or a result decoded by a codec.
+//
+// Relative URLs are resolved by the Sender, so the Deduplicator should be shared only by Senders with the same base URL.
+type Deduplicator struct {
+	lock  sync.Mutex
+	calls map[string]*dedupCall
+}
+
+// dedupCall is an in-flight request, the result is available when the done channel is closed.
+// The result is stored JSON encoded, because the leader may modify its own result, for example in a listener, while the waiters copy it.
+type dedupCall struct {
+	done        chan struct{}
+	rawResponse *http.Response
+	result      []byte
+	resultErr   error
+	err         error
+}
+
+// NewDeduplicator creates a Deduplicator.
+func NewDeduplicator() *Deduplicator {
+	return &Deduplicator{calls: make(map[string]*dedupCall)}
+}
+
+// Sender wraps the sender, concurrent identical GET requests sent by the returned Sender are sent only once.
+func (d *Deduplicator) Sender(sender Sender) Sender {
+	return dedupSender{deduplicator: d, sender: sender}
+}
+
+// Middleware returns the Deduplicator as a Middleware, see Deduplicator.Sender.
+func (d *Deduplicator) Middleware() Middleware {
+	return d.Sender
+}
+
+// dedupSender implements Sender interface, see Deduplicator.Sender.
+type dedupSender struct {
+	deduplicator *Deduplicator
+	sender       Sender
+}
+
+func (s dedupSender) Send(ctx context.Context, request HTTPRequest) (*http.Response, any, error) {
+	if !isDeduplicable(request) {
+		return s.sender.Send(ctx, request)
+	}
+
+	key := dedupKey(request)
+	d := s.deduplicator
+
+	// Wait for the in-flight request, if any
+	d.lock.Lock()
+	if call, found := d.calls[key]; found {
+		d.lock.Unlock()
+		select {
+		case <-ctx.Done():
+			return nil, nil, ctx.Err()
+		case <-call.done:
+		}
+
+		// The shared request has been canceled by its caller, send own request
+		if isContextError(call.err) && ctx.Err() == nil {
+			return s.sender.Send(ctx, request)
+		}
+
+		// The body has been read by the first request
+		rawResponse := withoutBody(call.rawResponse)
+		result, err := copyResult(call.result, call.resultErr, request.ResultDef())
+		if err != nil {
+			return rawResponse, nil, fmt.Errorf(`request %s "%s": cannot copy deduplicated result: %w`, request.Method(), request.URL().String(), err)
+		}
+		return rawResponse, result, call.err
+	}
+
+	// Send the request
+	call := &dedupCall{done: make(chan struct{})}
+	d.calls[key] = call
+	d.lock.Unlock()
+
+	rawResponse, result, err := s.sender.Send(ctx, request)
+	call.rawResponse, call.err = rawResponse, err
+	if result != nil {
+		call.result, call.resultErr = jsoniter.ConfigCompatibleWithStandardLibrary.Marshal(result)
+	}
+
+	d.lock.Lock()
+	delete(d.calls, key)
+	d.lock.Unlock()
+	close(call.done)
+
+	return rawResponse, result, err
+}
+
+func (s dedupSender) Tracer() trace.Tracer {
+	if tp, ok := s.sender.(withTracer); ok {
+		return tp.Tracer()
+	}
+	return nil
+}
+
+func isDeduplicable(request HTTPRequest) bool {
+	if request.Method() != http.MethodGet {
+		return false
+	}
+	if request.ResponseMediaType() != "" {
+		// The result is decoded by a codec, not as JSON
+		return false
+	}
+	if _, ok := request.ResultDef().(io.Writer); ok {
+		return false
+	}
+	if _, ok := request.ResultDef().(StreamDecoder); ok {
+		return false
+	}
+	if resultDef := request.ResultDef(); resultDef != nil {
+		return isPlainJSONType(reflect.TypeOf(resultDef), make(map[reflect.Type]bool))
+	}
+	return true
+}
+
+// isPlainJSONType returns true, if the value of the type can be copied by JSON encoding without a loss.
+// A type with a custom JSON decoding must also have a custom encoding, so the decoding of the API response is not applied to the copy.
+// Unexported fields, callbacks and CSV results, see client.CSVUnmarshaler, cannot be copied, the Placeholder is not a part of the response.
+func isPlainJSONType(t reflect.Type, visited map[reflect.Type]bool) bool {
+	if visited[t] {
+		return true
+	}
+	visited[t] = true
+
+	if t.Implements(csvUnmarshalerType) || reflect.PointerTo(t).Implements(csvUnmarshalerType) {
+		return false
+	}
+	marshaler := t.Implements(jsonMarshalerType) || reflect.PointerTo(t).Implements(jsonMarshalerType)
+	unmarshaler := reflect.PointerTo(t).Implements(jsonUnmarshalerType)
+	if marshaler || unmarshaler {
+		return marshaler && unmarshaler
+	}
+
+	switch t.Kind() {
+	case reflect.Pointer, reflect.Slice, reflect.Array:
+		return isPlainJSONType(t.Elem(), visited)
+	case reflect.Map:
+		return isPlainJSONType(t.Key(), visited) && isPlainJSONType(t.Elem(), visited)
+	case reflect.Struct:
+		for i := range t.NumField() {
+			field := t.Field(i)
+			if field.Type == placeholderType {
+				continue
+			}
+			if !field.IsExported() || !isPlainJSONType(field.Type, visited) {
+				return false
+			}
+		}
+		return true
+	case reflect.Func, reflect.Chan, reflect.UnsafePointer, reflect.Complex64, reflect.Complex128:
+		return false
+	default:
+		return true
+	}
+}
+
+// withoutBody returns a copy of the shared response for a deduplicated request, the body is read only by the first request.
+func withoutBody(response *http.Response) *http.Response {
+	if response == nil {
+		return nil
+	}
+	out := *response
+	out.Header = response.Header.Clone()
+	out.Body = http.NoBody
+	return &out
+}
+
+func isContextError(err error) bool {
+	return errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded)
+}
+
+// dedupKey returns the key of the request, it contains all values which may affect the response.
+func dedupKey(request HTTPRequest) string {
+	reqURL := request.URL()
+	for k, v := range request.PathParams() {
+		reqURL.Path = strings.ReplaceAll(reqURL.Path, "{"+k+"}", url.PathEscape(v))
+	}
+	reqURL.RawQuery = request.QueryParams().Encode()
+
+	var b strings.Builder
+	b.WriteString(request.Method() + " " + reqURL.String() + "\n")
+	b.WriteString(fmt.Sprintf("%T\n", request.ResultDef()))
+
+	header := request.RequestHeader()
+	names := make([]string, 0, len(header))
+	for name := range header {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+	for _, name := range names {
+		b.WriteString(name + ": " + strings.Join(header.Values(name), ", ") + "\n")
+	}
+	return b.String()
+}
+
+// copyResult decodes the shared JSON encoded result to the result definition of the request.
+func copyResult(result []byte, resultErr error, resultDef any) (any, error) {
+	if resultErr != nil {
+		return nil, resultErr
+	}
+	if result == nil || resultDef == nil {
+		return nil, nil
+	}
+	if err := jsoniter.ConfigCompatibleWithStandardLibrary.Unmarshal(result, resultDef); err != nil {
+		return nil, err
+	}
+	return resultDef, nil
+}
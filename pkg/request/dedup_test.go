@@ -0,0 +1,242 @@
+package request_test
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/jarcoal/httpmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/keboola/go-client/pkg/client"
+	"github.com/keboola/go-client/pkg/request"
+)
+
+type dedupResult struct {
+	request.Placeholder
+	ID   string   `json:"id"`
+	Tags []string `json:"tags"`
+}
+
+// dedupCustomResult has a custom JSON decoding, without the encoding, the JSON copy is not the same.
+type dedupCustomResult struct {
+	ID string `json:"id"`
+}
+
+func (r *dedupCustomResult) UnmarshalJSON(data []byte) error {
+	r.ID = "custom:" + string(data)
+	return nil
+}
+
+type dedupUnexportedResult struct {
+	ID      string `json:"id"`
+	counter int    //nolint:unused // the unexported state cannot be copied by JSON encoding
+}
+
+type dedupCSVResult struct {
+	Records [][]string
+}
+
+func (r *dedupCSVResult) UnmarshalCSV(records [][]string) error {
+	r.Records = records
+	return nil
+}
+
+func TestDeduplicator(t *testing.T) {
+	t.Parallel()
+
+	// Mocked response, slow, so the requests overlap
+	c, transport := client.NewMockedClient()
+	transport.RegisterResponder(http.MethodGet, "https://example.com/tables/foo", func(req *http.Request) (*http.Response, error) {
+		time.Sleep(50 * time.Millisecond)
+		return httpmock.NewJsonResponse(http.StatusOK, map[string]any{"id": "foo", "tags": []string{"a", "b"}})
+	})
+
+	// Send identical requests concurrently
+	ctx := context.Background()
+	sender := request.NewDeduplicator().Sender(c)
+	results := make([]*dedupResult, 5)
+	responses := make([]*http.Response, len(results))
+	wg := &sync.WaitGroup{}
+	for i := range results {
+		results[i] = &dedupResult{}
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			response, result, err := request.NewHTTPRequest(sender).
+				WithGet("https://example.com/tables/{tableId}").
+				AndPathParam("tableId", "foo").
+				AndHeader("X-StorageApi-Token", "my-token").
+				WithResult(results[i]).
+				Send(ctx)
+			assert.NoError(t, err)
+			assert.Same(t, results[i], result)
+			responses[i] = response.RawResponse()
+		}()
+	}
+	wg.Wait()
+
+	// Only one request has been sent, each caller has own copy of the result
+	assert.Equal(t, 1, transport.GetCallCountInfo()["GET https://example.com/tables/foo"])
+	for _, result := range results {
+		assert.Equal(t, &dedupResult{ID: "foo", Tags: []string{"a", "b"}}, result)
+	}
+	results[0].Tags[0] = "modified"
+	assert.Equal(t, "a", results[1].Tags[0])
+
+	// Each caller has own response, the body is read only by the first request
+	withoutBody := 0
+	for i, response := range responses {
+		assert.Equal(t, http.StatusOK, response.StatusCode)
+		for _, other := range responses[i+1:] {
+			assert.NotSame(t, response, other)
+		}
+		if response.Body == http.NoBody {
+			withoutBody++
+		}
+	}
+	assert.Equal(t, len(responses)-1, withoutBody)
+}
+
+func TestDeduplicator_DifferentRequests(t *testing.T) {
+	t.Parallel()
+
+	// Mocked response, slow, so the requests overlap
+	c, transport := client.NewMockedClient()
+	transport.RegisterResponder(http.MethodGet, "https://example.com/tables/foo", func(req *http.Request) (*http.Response, error) {
+		time.Sleep(50 * time.Millisecond)
+		return httpmock.NewJsonResponse(http.StatusOK, map[string]any{"id": "foo"})
+	})
+	transport.RegisterResponder(http.MethodPost, "https://example.com/tables/foo", httpmock.NewStringResponder(http.StatusOK, "{}"))
+
+	// Requests with a different token or method are not deduplicated
+	ctx := context.Background()
+	sender := request.NewDeduplicator().Sender(c)
+	group := request.NewWaitGroup(ctx)
+	group.Send(request.NewHTTPRequest(sender).WithGet("https://example.com/tables/foo").AndHeader("X-StorageApi-Token", "token1"))
+	group.Send(request.NewHTTPRequest(sender).WithGet("https://example.com/tables/foo").AndHeader("X-StorageApi-Token", "token2"))
+	group.Send(request.NewHTTPRequest(sender).WithPost("https://example.com/tables/foo"))
+	group.Send(request.NewHTTPRequest(sender).WithPost("https://example.com/tables/foo"))
+	require.NoError(t, group.Wait())
+	assert.Equal(t, 2, transport.GetCallCountInfo()["GET https://example.com/tables/foo"])
+	assert.Equal(t, 2, transport.GetCallCountInfo()["POST https://example.com/tables/foo"])
+}
+
+func TestDeduplicator_ModifiedResult(t *testing.T) {
+	t.Parallel()
+
+	// Mocked response, slow, so the requests overlap
+	c, transport := client.NewMockedClient()
+	transport.RegisterResponder(http.MethodGet, "https://example.com/tables/foo", func(req *http.Request) (*http.Response, error) {
+		time.Sleep(50 * time.Millisecond)
+		return httpmock.NewJsonResponse(http.StatusOK, map[string]any{"id": "foo", "tags": []string{"a", "b"}})
+	})
+
+	// Each caller modifies own result in a listener, while the others copy the shared result, run with -race
+	ctx := context.Background()
+	sender := request.NewDeduplicator().Sender(c)
+	results := make([]*dedupResult, 10)
+	wg := &sync.WaitGroup{}
+	for i := range results {
+		results[i] = &dedupResult{}
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, _, err := request.NewHTTPRequest(sender).
+				WithGet("https://example.com/tables/foo").
+				WithResult(results[i]).
+				WithOnSuccess(func(ctx context.Context, response request.HTTPResponse) error {
+					result := response.Result().(*dedupResult)
+					for j := range 100 {
+						result.ID = "modified"
+						result.Tags = append(result.Tags, strconv.Itoa(j))
+					}
+					return nil
+				}).
+				Send(ctx)
+			assert.NoError(t, err)
+		}()
+	}
+	wg.Wait()
+
+	// Each caller has modified only own copy of the result
+	assert.Equal(t, 1, transport.GetCallCountInfo()["GET https://example.com/tables/foo"])
+	for _, result := range results {
+		assert.Equal(t, "modified", result.ID)
+		assert.Len(t, result.Tags, 102)
+		assert.Equal(t, []string{"a", "b", "0"}, result.Tags[:3])
+	}
+}
+
+func TestDeduplicator_NotPlainJSON(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		name        string
+		contentType string
+		body        string
+		resultDef   func() any
+		modify      func(r request.HTTPRequest) request.HTTPRequest
+	}{
+		{
+			name:        "custom JSON decoding",
+			contentType: "application/json",
+			body:        `{"id":"foo"}`,
+			resultDef:   func() any { return &dedupCustomResult{} },
+		},
+		{
+			name:        "unexported field",
+			contentType: "application/json",
+			body:        `{"id":"foo"}`,
+			resultDef:   func() any { return &dedupUnexportedResult{} },
+		},
+		{
+			name:        "CSV",
+			contentType: "text/csv",
+			body:        "id\nfoo\n",
+			resultDef:   func() any { return &dedupCSVResult{} },
+		},
+		{
+			name:        "codec",
+			contentType: "application/octet-stream",
+			body:        "id\nfoo\n",
+			resultDef:   func() any { return &[][]string{} },
+			modify: func(r request.HTTPRequest) request.HTTPRequest {
+				return r.WithResponseMediaType(client.ContentTypeTextCSV)
+			},
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			// Mocked response, slow, so the requests overlap
+			c, transport := client.NewMockedClient()
+			transport.RegisterResponder(http.MethodGet, "https://example.com/tables/foo", func(req *http.Request) (*http.Response, error) {
+				time.Sleep(50 * time.Millisecond)
+				response := httpmock.NewStringResponse(http.StatusOK, tc.body)
+				response.Header.Set("Content-Type", tc.contentType)
+				return response, nil
+			})
+
+			// The result cannot be copied by JSON encoding, the requests are not deduplicated
+			ctx := context.Background()
+			sender := request.NewDeduplicator().Sender(c)
+			group := request.NewWaitGroup(ctx)
+			for range 3 {
+				req := request.NewHTTPRequest(sender).WithGet("https://example.com/tables/foo").WithResult(tc.resultDef())
+				if tc.modify != nil {
+					req = tc.modify(req)
+				}
+				group.Send(req)
+			}
+			require.NoError(t, group.Wait())
+			assert.Equal(t, 3, transport.GetCallCountInfo()["GET https://example.com/tables/foo"])
+		})
+	}
+}
@@ -0,0 +1,262 @@
+package request
+
+import (
+	"bytes"
+	"encoding/base64"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// streamPlaceholder replaces content of a stream body, which is not read by the export.
+const streamPlaceholder = "<content of the stream>"
+
+// exportedRequest contains all parts of the request needed to export it.
+type exportedRequest struct {
+	method string
+	url    *url.URL
+	header http.Header
+	def    HTTPRequest
+	// pathParams are not replaced in the url yet, see exportedRequest.resolvedURL
+	pathParams map[string]string
+}
+
+// ToCurl exports the request as a curl command, for example to reproduce a failed request.
+//
+// The relative URL of the request is resolved against the baseURL, in the same way as by the client.Client.
+// The body is encoded in the same way as by the client.Client, see EncodeBody, but it is not compressed.
+// Files of a multipart body are not read, they are referenced by the file name.
+// A stream body is read only if it is an io.Seeker, it is rewound to the original position, otherwise a placeholder is used.
+func ToCurl(r HTTPRequest, baseURL string, opts ...RedactOption) (string, error) {
+	req, err := newExportedRequest(r, baseURL)
+	if err != nil {
+		return "", err
+	}
+	return req.curl(NewRedactor(opts...))
+}
+
+// ToRawHTTP exports the request as a raw HTTP/1.1 message.
+//
+// The relative URL of the request is resolved against the baseURL, in the same way as by the client.Client.
+// The body is encoded in the same way as by the client.Client, see EncodeBody, but it is not compressed.
+// Files of a multipart body are not read, a placeholder is used instead of the content.
+// A stream body is read only if it is an io.Seeker, it is rewound to the original position, otherwise a placeholder is used.
+func ToRawHTTP(r HTTPRequest, baseURL string, opts ...RedactOption) (string, error) {
+	req, err := newExportedRequest(r, baseURL)
+	if err != nil {
+		return "", err
+	}
+	return req.rawHTTP(NewRedactor(opts...))
+}
+
+// ExportBody returns the request body encoded in the same way as by the client.Client, see EncodeBody, but it is not compressed.
+// Streams are never read, so the function is safe to call while the request is being sent:
+// files of a multipart body and an io.Reader body are replaced by a placeholder.
+func ExportBody(r HTTPRequest) ([]byte, error) {
+	body := r.RequestBody()
+	if _, ok := body.(*MultipartBody); !ok {
+		if _, ok := body.(io.Reader); ok {
+			return []byte(streamPlaceholder), nil
+		}
+	}
+	return (&exportedRequest{def: r}).body()
+}
+
+func newExportedRequest(r HTTPRequest, baseURL string) (*exportedRequest, error) {
+	reqURL := r.URL()
+
+	// Convert to absolute url, see client.Client.WithBaseURL
+	if baseURL != "" && !reqURL.IsAbs() {
+		base, err := url.Parse(baseURL)
+		if err != nil {
+			return nil, fmt.Errorf(`base url "%s" is not valid: %w`, baseURL, err)
+		}
+		base.Path = strings.TrimRight(base.Path, "/") + "/"
+		reqURL = base.ResolveReference(reqURL)
+	}
+
+	// Set query parameters
+	reqURL.RawQuery = r.QueryParams().Encode()
+
+	return &exportedRequest{method: r.Method(), url: reqURL, header: r.RequestHeader(), def: r, pathParams: r.PathParams()}, nil
+}
+
+// newExportedResponseRequest exports the request actually sent, with headers set by the client, if it is available.
+func newExportedResponseRequest(r httpResponse) (*exportedRequest, error) {
+	raw := r.RawRequest()
+	if raw == nil {
+		return newExportedRequest(r.httpRequest, "")
+	}
+
+	// The body is exported uncompressed
+	header := raw.Header.Clone()
+	header.Del("Content-Encoding")
+
+	reqURL := *raw.URL
+	return &exportedRequest{method: raw.Method, url: &reqURL, header: header, def: r.httpRequest}, nil
+}
+
+func (r *exportedRequest) curl(redactor Redactor) (string, error) {
+	var b strings.Builder
+	var stdin string
+	lines := []string{"curl -X " + r.method + " " + shellQuote(r.resolvedURL(redactor).String())}
+
+	// Headers, Content-Type of a multipart body is generated by the curl, with a new boundary
+	multipartBody, isMultipart := r.def.RequestBody().(*MultipartBody)
+	for _, name := range sortedKeys(r.header) {
+		if isMultipart && strings.EqualFold(name, "Content-Type") {
+			continue
+		}
+		for _, value := range r.header.Values(name) {
+			lines = append(lines, "-H "+shellQuote(name+": "+redactor.HeaderValue(name, value)))
+		}
+	}
+
+	// Body
+	switch {
+	case isMultipart:
+		for _, k := range sortedKeys(multipartBody.fields) {
+			lines = append(lines, "--form-string "+shellQuote(k+"="+multipartBody.fields[k]))
+		}
+		for _, file := range multipartBody.files {
+			value := fmt.Sprintf(`%s=@"%s"`, file.FieldName, escapeQuotes(file.FileName))
+			if file.ContentType != "" {
+				value += ";type=" + file.ContentType
+			}
+			lines = append(lines, "-F "+shellQuote(value))
+		}
+	default:
+		body, err := r.body()
+		if err != nil {
+			return "", err
+		}
+		if body == nil {
+			break
+		}
+		if utf8.Valid(body) {
+			lines = append(lines, "--data-binary "+shellQuote(string(body)))
+		} else {
+			// Binary body is piped to the curl
+			stdin = "echo " + shellQuote(base64.StdEncoding.EncodeToString(body)) + " | base64 -d | "
+			lines = append(lines, "--data-binary @-")
+		}
+	}
+
+	b.WriteString(stdin)
+	b.WriteString(strings.Join(lines, " \\\n  "))
+	return b.String(), nil
+}
+
+func (r *exportedRequest) rawHTTP(redactor Redactor) (string, error) {
+	body, err := r.body()
+	if err != nil {
+		return "", err
+	}
+
+	reqURL := r.resolvedURL(redactor)
+	header := r.header.Clone()
+	if header.Get("Host") == "" && reqURL.Host != "" {
+		header.Set("Host", reqURL.Host)
+	}
+	if len(body) > 0 {
+		header.Set("Content-Length", strconv.Itoa(len(body)))
+	}
+
+	var b strings.Builder
+	b.WriteString(r.method + " " + reqURL.RequestURI() + " HTTP/1.1\r\n")
+	for _, name := range sortedKeys(header) {
+		for _, value := range header.Values(name) {
+			b.WriteString(name + ": " + redactor.HeaderValue(name, value) + "\r\n")
+		}
+	}
+	b.WriteString("\r\n")
+	b.Write(body)
+	return b.String(), nil
+}
+
+// body returns the encoded body, files of a multipart body are replaced by a placeholder, so they are not read.
+// A stream body is not owned by the export, so it is never closed and it is read only if it can be rewound.
+func (r *exportedRequest) body() ([]byte, error) {
+	def := r.def
+	if v, ok := def.RequestBody().(io.Reader); ok {
+		return streamBody(v)
+	}
+	if v, ok := def.RequestBody().(*MultipartBody); ok {
+		placeholder := &MultipartBody{boundary: v.boundary, fields: v.fields}
+		for _, file := range v.files {
+			file.Reader = strings.NewReader(fmt.Sprintf(`<content of the file "%s">`, file.FileName))
+			placeholder.files = append(placeholder.files, file)
+		}
+		var buf bytes.Buffer
+		if err := placeholder.write(&buf); err != nil {
+			return nil, fmt.Errorf(`cannot export request body: %w`, err)
+		}
+		return buf.Bytes(), nil
+	}
+
+	body, err := EncodeBody(def)
+	if err != nil {
+		return nil, fmt.Errorf(`cannot export request body: %w`, err)
+	}
+	if body == nil {
+		return nil, nil
+	}
+	defer body.Close()
+	content, err := io.ReadAll(body)
+	if err != nil {
+		return nil, fmt.Errorf(`cannot export request body: %w`, err)
+	}
+	return content, nil
+}
+
+// streamBody reads the stream and rewinds it to the original position, or returns a placeholder, if the stream is not an io.Seeker.
+func streamBody(stream io.Reader) ([]byte, error) {
+	seeker, ok := stream.(io.Seeker)
+	if !ok {
+		return []byte(streamPlaceholder), nil
+	}
+
+	pos, err := seeker.Seek(0, io.SeekCurrent)
+	if err != nil {
+		return nil, fmt.Errorf(`cannot export request body: %w`, err)
+	}
+	if _, err := seeker.Seek(0, io.SeekStart); err != nil {
+		return nil, fmt.Errorf(`cannot export request body: %w`, err)
+	}
+	content, err := io.ReadAll(stream)
+	if err != nil {
+		return nil, fmt.Errorf(`cannot export request body: %w`, err)
+	}
+	if _, err := seeker.Seek(pos, io.SeekStart); err != nil {
+		return nil, fmt.Errorf(`cannot export request body: %w`, err)
+	}
+	return content, nil
+}
+
+// resolvedURL returns the URL with path parameters replaced and sensitive values masked.
+func (r *exportedRequest) resolvedURL(redactor Redactor) *url.URL {
+	out := redactor.URL(r.url)
+	for k, v := range redactor.PathParams(r.pathParams) {
+		out.Path = strings.ReplaceAll(out.Path, "{"+k+"}", url.PathEscape(v))
+	}
+	return out
+}
+
+// shellQuote quotes the value for a POSIX shell.
+func shellQuote(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	slices.Sort(keys)
+	return keys
+}
@@ -0,0 +1,142 @@
+package request_test
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/jarcoal/httpmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/keboola/go-client/pkg/client"
+	"github.com/keboola/go-client/pkg/request"
+)
+
+func TestToCurl(t *testing.T) {
+	t.Parallel()
+
+	req := request.NewHTTPRequest(client.New()).
+		WithPost("branch/{branchId}/buckets").
+		AndPathParam("branchId", "123").
+		AndQueryParam("async", "1").
+		AndQueryParam("token", "secret").
+		AndHeader("Authorization", "Bearer secret").
+		AndHeader("X-StorageApi-Token", "secret").
+		WithJSONBody(map[string]any{"name": "it's"})
+
+	curl, err := request.ToCurl(req, "https://connection.keboola.com/v2/storage", request.WithRedactedHeaders("X-StorageApi-Token"), request.WithRedactedQueryParams("token"))
+	require.NoError(t, err)
+	assert.Equal(t, strings.TrimSpace(`
+curl -X POST 'https://connection.keboola.com/v2/storage/branch/123/buckets?async=1&token=%2A%2A%2A%2A' \
+  -H 'Authorization: ****' \
+  -H 'Content-Type: application/json' \
+  -H 'X-Storageapi-Token: ****' \
+  --data-binary '{"name":"it'\''s"}'
+`), curl)
+
+	raw, err := request.ToRawHTTP(req, "https://connection.keboola.com/v2/storage", request.WithRedactedHeaders("X-StorageApi-Token"))
+	require.NoError(t, err)
+	assert.Equal(t, "POST /v2/storage/branch/123/buckets?async=1&token=secret HTTP/1.1\r\n"+
+		"Authorization: ****\r\n"+
+		"Content-Length: 15\r\n"+
+		"Content-Type: application/json\r\n"+
+		"Host: connection.keboola.com\r\n"+
+		"X-Storageapi-Token: ****\r\n"+
+		"\r\n"+
+		`{"name":"it's"}`, raw)
+}
+
+func TestToCurl_Binary(t *testing.T) {
+	t.Parallel()
+
+	req := request.NewHTTPRequest(client.New()).WithPut("https://example.com/file").WithBody([]byte{0xff, 0xfe})
+	curl, err := request.ToCurl(req, "")
+	require.NoError(t, err)
+	assert.Equal(t, "echo '//4=' | base64 -d | curl -X PUT 'https://example.com/file' \\\n  --data-binary @-", curl)
+}
+
+func TestToCurl_Multipart(t *testing.T) {
+	t.Parallel()
+
+	// The file is not read by the export
+	file := request.MultipartFile{FieldName: "data", FileName: "data.csv", ContentType: "text/csv", Reader: &notReadableReader{}}
+	req := request.NewHTTPRequest(client.New()).
+		WithPost("https://example.com/upload").
+		WithMultipartBody(map[string]string{"name": "foo"}, file)
+
+	curl, err := request.ToCurl(req, "")
+	require.NoError(t, err)
+	assert.Equal(t, "curl -X POST 'https://example.com/upload' \\\n  --form-string 'name=foo' \\\n  -F 'data=@\"data.csv\";type=text/csv'", curl)
+
+	raw, err := request.ToRawHTTP(req, "")
+	require.NoError(t, err)
+	assert.Contains(t, raw, "Content-Type: multipart/form-data; boundary=")
+	assert.Contains(t, raw, "Content-Disposition: form-data; name=\"data\"; filename=\"data.csv\"\r\nContent-Type: text/csv\r\n\r\n<content of the file \"data.csv\">\r\n")
+}
+
+func TestHTTPResponse_ToCurl(t *testing.T) {
+	t.Parallel()
+
+	c, transport := client.NewMockedClient()
+	c = c.WithBaseURL("https://example.com/api").WithHeader("X-StorageApi-Token", "secret")
+	transport.RegisterResponder(http.MethodGet, "https://example.com/api/foo/123", httpmock.NewStringResponder(http.StatusBadRequest, "error"))
+
+	var curl string
+	_, _, err := request.NewHTTPRequest(c).
+		WithGet("foo/{id}").
+		AndPathParam("id", "123").
+		WithOnError(func(ctx context.Context, response request.HTTPResponse, err error) error {
+			var exportErr error
+			curl, exportErr = response.ToCurl(request.WithRedactedHeaders("X-StorageApi-Token"))
+			require.NoError(t, exportErr)
+			return err
+		}).
+		Send(context.Background())
+	require.Error(t, err)
+
+	// Base URL and global headers are set by the client
+	assert.Contains(t, curl, "'https://example.com/api/foo/123'")
+	assert.Contains(t, curl, "'X-Storageapi-Token: ****'")
+}
+
+func TestToCurl_Stream(t *testing.T) {
+	t.Parallel()
+
+	// Seekable stream is exported, it is rewound to the original position and it is not closed
+	stream := &closeRecorder{Reader: strings.NewReader("stream content")}
+	_, err := stream.Seek(7, io.SeekStart)
+	require.NoError(t, err)
+	req := request.NewHTTPRequest(client.New()).WithPut("https://example.com/file").WithBody(stream)
+	curl, err := request.ToCurl(req, "")
+	require.NoError(t, err)
+	assert.Equal(t, "curl -X PUT 'https://example.com/file' \\\n  --data-binary 'stream content'", curl)
+	rest, err := io.ReadAll(stream)
+	require.NoError(t, err)
+	assert.Equal(t, "content", string(rest))
+	assert.False(t, stream.closed)
+
+	// Other stream is not read
+	req = request.NewHTTPRequest(client.New()).WithPut("https://example.com/file").WithBody(&notReadableReader{})
+	raw, err := request.ToRawHTTP(req, "")
+	require.NoError(t, err)
+	assert.True(t, strings.HasSuffix(raw, "\r\n\r\n<content of the stream>"))
+}
+
+type closeRecorder struct {
+	*strings.Reader
+	closed bool
+}
+
+func (r *closeRecorder) Close() error {
+	r.closed = true
+	return nil
+}
+
+type notReadableReader struct{}
+
+func (r *notReadableReader) Read(_ []byte) (int, error) {
+	panic("the reader should not be read")
+}
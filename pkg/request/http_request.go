@@ -9,6 +9,7 @@ import (
 	"net/url"
 	"reflect"
 	"strings"
+	"time"
 
 	"go.opentelemetry.io/otel/trace"
 )
@@ -54,15 +55,39 @@ type HTTPRequest interface {
 	WithPathParams(params map[string]string) HTTPRequest
 	// WithFormBody method sets Form parameters and Content-Type header to "application/x-www-form-urlencoded".
 	WithFormBody(form map[string]string) HTTPRequest
+	// WithMultipartBody method sets request body to the streamed form fields and files, and Content-Type header to "multipart/form-data".
+	WithMultipartBody(fields map[string]string, files ...MultipartFile) HTTPRequest
 	// WithJSONBody method sets request body to the JSON value and Content-Type header to "application/json".
 	WithJSONBody(body any) HTTPRequest
 	// WithBody method sets request body.
 	WithBody(body any) HTTPRequest
+	// WithCompressedBody method enables compression of the request body, for example "gzip" or "br".
+	// The body is compressed on the fly, as it is sent, and the Content-Encoding header is set.
+	WithCompressedBody(contentEncoding string) HTTPRequest
 	// WithContentType method sets custom content type.
 	WithContentType(contentType string) HTTPRequest
+	// WithRetry method overrides the default retry configuration of the Sender for the request.
+	WithRetry(retry RetryConfig) HTTPRequest
+	// WithRetryModifier method adjusts the effective retry configuration of the Sender for the request.
+	// Unlike WithRetry, the rest of the Sender configuration, for example the retry condition, is kept.
+	WithRetryModifier(fn RetryModifier) HTTPRequest
+	// WithTimeout method overrides the total timeout of the request, including all retries.
+	WithTimeout(timeout time.Duration) HTTPRequest
+	// WithIdempotent method explicitly marks the request as idempotent or non-idempotent.
+	// By default, the idempotency is determined by the HTTP method, see IsIdempotent.
+	WithIdempotent(idempotent bool) HTTPRequest
+	// WithCache method enables caching of the GET request response, if the Sender has a cache, see client.Client.WithCache.
+	// The ttl is the maximum time for which the response is used without revalidation.
+	WithCache(ttl time.Duration) HTTPRequest
+	// WithResponseMediaType method sets the media type of a successful response, for example "text/csv".
+	// The response is decoded by the codec of the media type, regardless of the Content-Type header, see client.Client.WithCodec.
+	WithResponseMediaType(mediaType string) HTTPRequest
+	// WithService method sets name of the logical API service, it is used for example by the RateLimiter.
+	WithService(service string) HTTPRequest
 	// WithError method registers the request `Error` value for automatic mapping.
 	WithError(err error) HTTPRequest
 	// WithResult method registers the request `Result` value for automatic mapping.
+	// The value must be a pointer, an io.Writer, or a callback supported by a codec of the Sender, see client.NDJSONCodec.
 	WithResult(result any) HTTPRequest
 	// WithOnComplete method registers callback to be executed when the request is completed.
 	WithOnComplete(func(ctx context.Context, response HTTPResponse, err error) error) HTTPRequest
@@ -91,10 +116,26 @@ type httpRequestReadOnly interface {
 	// `*string`, `*[]byte`, `*struct`, `*map`, `*slice`, `io.ReadSeeker` and `io.ReadSeekCloser`.
 	// Automatic marshaling for JSON is provided, if it is `*struct`, `*map`, or `*slice`.
 	RequestBody() any
+	// BodyEncoding method returns the content encoding used to compress the request body, or an empty string, see WithCompressedBody.
+	BodyEncoding() string
 	// ErrorDef method returns a target value for error result mapping.
 	ErrorDef() error
 	// ResultDef method returns a target value for result mapping.
 	ResultDef() any
+	// Retry method returns the retry configuration override, or nil if the default configuration of the Sender should be used.
+	Retry() *RetryConfig
+	// RetryModifier method returns the function adjusting the effective retry configuration, or nil if it is not set.
+	RetryModifier() RetryModifier
+	// Timeout method returns the total timeout override, or 0 if the default timeout of the Sender should be used.
+	Timeout() time.Duration
+	// Idempotent method returns the explicit idempotency flag, or nil if it is not set, see IsIdempotent.
+	Idempotent() *bool
+	// CacheTTL method returns the maximum age of a cached response, or 0 if the caching is disabled.
+	CacheTTL() time.Duration
+	// ResponseMediaType method returns the media type of a successful response, or an empty string if the Content-Type header is used.
+	ResponseMediaType() string
+	// Service method returns name of the logical API service, or an empty string if it is not set.
+	Service() string
 }
 
 // NewHTTPRequest creates immutable HTTP request.
@@ -104,17 +145,25 @@ func NewHTTPRequest(sender Sender) HTTPRequest {
 
 // httpRequest implements HTTPRequest interface.
 type httpRequest struct {
-	sender      Sender
-	method      string
-	baseURL     *url.URL
-	url         *url.URL
-	header      http.Header
-	queryParams url.Values
-	pathParams  map[string]string
-	body        any
-	resultDef   any
-	errorDef    error
-	listeners   []func(ctx context.Context, response HTTPResponse, err error) error
+	sender       Sender
+	method       string
+	baseURL      *url.URL
+	url          *url.URL
+	header       http.Header
+	queryParams  url.Values
+	pathParams   map[string]string
+	body         any
+	bodyEncoding string
+	resultDef    any
+	errorDef     error
+	retry        *RetryConfig
+	retryMod     RetryModifier
+	timeout      time.Duration
+	idempotent   *bool
+	cacheTTL     time.Duration
+	mediaType    string
+	service      string
+	listeners    []func(ctx context.Context, response HTTPResponse, err error) error
 }
 
 func (r httpRequest) Tracer() trace.Tracer {
@@ -170,6 +219,38 @@ func (r httpRequest) ResultDef() any {
 	return r.resultDef
 }
 
+func (r httpRequest) Retry() *RetryConfig {
+	return r.retry
+}
+
+func (r httpRequest) RetryModifier() RetryModifier {
+	return r.retryMod
+}
+
+func (r httpRequest) Timeout() time.Duration {
+	return r.timeout
+}
+
+func (r httpRequest) Idempotent() *bool {
+	return r.idempotent
+}
+
+func (r httpRequest) BodyEncoding() string {
+	return r.bodyEncoding
+}
+
+func (r httpRequest) CacheTTL() time.Duration {
+	return r.cacheTTL
+}
+
+func (r httpRequest) ResponseMediaType() string {
+	return r.mediaType
+}
+
+func (r httpRequest) Service() string {
+	return r.service
+}
+
 func (r httpRequest) WithHead(url string) HTTPRequest {
 	return r.WithMethod(http.MethodHead).WithURL(url)
 }
@@ -265,6 +346,12 @@ func (r httpRequest) WithFormBody(form map[string]string) HTTPRequest {
 	return r.AndHeader("Content-Type", "application/x-www-form-urlencoded")
 }
 
+func (r httpRequest) WithMultipartBody(fields map[string]string, files ...MultipartFile) HTTPRequest {
+	body := newMultipartBody(fields, files)
+	r.body = body
+	return r.AndHeader("Content-Type", body.ContentType())
+}
+
 func (r httpRequest) WithJSONBody(body any) HTTPRequest {
 	r.body = body
 	return r.AndHeader("Content-Type", "application/json")
@@ -275,10 +362,56 @@ func (r httpRequest) WithBody(body any) HTTPRequest {
 	return r
 }
 
+func (r httpRequest) WithCompressedBody(contentEncoding string) HTTPRequest {
+	r.bodyEncoding = contentEncoding
+	return r
+}
+
 func (r httpRequest) WithContentType(contentType string) HTTPRequest {
 	return r.AndHeader("Content-Type", contentType)
 }
 
+func (r httpRequest) WithRetry(retry RetryConfig) HTTPRequest {
+	r.retry = &retry
+	return r
+}
+
+func (r httpRequest) WithRetryModifier(fn RetryModifier) HTTPRequest {
+	r.retryMod = fn
+	return r
+}
+
+func (r httpRequest) WithTimeout(timeout time.Duration) HTTPRequest {
+	if timeout <= 0 {
+		panic(fmt.Errorf(`timeout must be greater than 0, found "%s"`, timeout))
+	}
+	r.timeout = timeout
+	return r
+}
+
+func (r httpRequest) WithIdempotent(idempotent bool) HTTPRequest {
+	r.idempotent = &idempotent
+	return r
+}
+
+func (r httpRequest) WithCache(ttl time.Duration) HTTPRequest {
+	if ttl < 0 {
+		panic(fmt.Errorf("cache ttl cannot be negative, found %s", ttl))
+	}
+	r.cacheTTL = ttl
+	return r
+}
+
+func (r httpRequest) WithResponseMediaType(mediaType string) HTTPRequest {
+	r.mediaType = strings.ToLower(mediaType)
+	return r
+}
+
+func (r httpRequest) WithService(service string) HTTPRequest {
+	r.service = service
+	return r
+}
+
 func (r httpRequest) WithError(err error) HTTPRequest {
 	if reflect.ValueOf(err).Kind() != reflect.Ptr {
 		panic(fmt.Errorf(`error must be defined by a pointer`))
@@ -290,7 +423,7 @@ func (r httpRequest) WithError(err error) HTTPRequest {
 func (r httpRequest) WithResult(result any) HTTPRequest {
 	_, ok1 := result.(io.Writer)
 	_, ok2 := result.(io.WriteCloser)
-	if !ok1 && !ok2 && reflect.ValueOf(result).Kind() != reflect.Ptr {
+	if kind := reflect.ValueOf(result).Kind(); !ok1 && !ok2 && kind != reflect.Ptr && kind != reflect.Func {
 		panic(fmt.Errorf(`result must be defined by a pointer`))
 	}
 	r.resultDef = result
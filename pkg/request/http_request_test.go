@@ -4,7 +4,9 @@ import (
 	"context"
 	"net/http"
 	"net/url"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 
@@ -139,12 +141,67 @@ func TestHttpRequest_Immutability(t *testing.T) {
 	assert.Equal(t, "foo1=bar1", a.RequestBody())
 	assert.Equal(t, "foo2=bar2", b.RequestBody())
 
+	// WithMultipartBody
+	a = a.WithMultipartBody(map[string]string{"foo1": "bar1"})
+	b = a.WithMultipartBody(map[string]string{"foo2": "bar2"})
+	assert.NotSame(t, a.RequestBody(), b.RequestBody())
+	assert.True(t, strings.HasPrefix(a.RequestHeader().Get("Content-Type"), "multipart/form-data; boundary="))
+	assert.NotEqual(t, a.RequestHeader().Get("Content-Type"), b.RequestHeader().Get("Content-Type"))
+
 	// WithPathParams
 	a = a.WithJSONBody(123)
 	b = a.WithJSONBody(456)
 	assert.Equal(t, 123, a.RequestBody())
 	assert.Equal(t, 456, b.RequestBody())
 
+	// WithCompressedBody
+	a = a.WithCompressedBody("gzip")
+	b = a.WithCompressedBody("br")
+	assert.Equal(t, "gzip", a.BodyEncoding())
+	assert.Equal(t, "br", b.BodyEncoding())
+
+	// WithRetry
+	a = a.WithRetry(request.RetryConfig{Count: 1})
+	b = a.WithRetry(request.RetryConfig{Count: 2})
+	assert.Equal(t, &request.RetryConfig{Count: 1}, a.Retry())
+	assert.Equal(t, &request.RetryConfig{Count: 2}, b.Retry())
+
+	// WithRetryModifier
+	a = a.WithRetryModifier(func(v request.RetryConfig) request.RetryConfig { v.Count = 3; return v })
+	b = a.WithRetryModifier(nil)
+	assert.Equal(t, request.RetryConfig{Count: 3}, a.RetryModifier()(request.RetryConfig{}))
+	assert.Nil(t, b.RetryModifier())
+
+	// WithTimeout
+	a = a.WithTimeout(1 * time.Second)
+	b = a.WithTimeout(2 * time.Second)
+	assert.Equal(t, 1*time.Second, a.Timeout())
+	assert.Equal(t, 2*time.Second, b.Timeout())
+
+	// WithIdempotent
+	a = a.WithIdempotent(true)
+	b = a.WithIdempotent(false)
+	assert.True(t, *a.Idempotent())
+	assert.False(t, *b.Idempotent())
+
+	// WithCache
+	a = a.WithCache(time.Minute)
+	b = a.WithCache(time.Hour)
+	assert.Equal(t, time.Minute, a.CacheTTL())
+	assert.Equal(t, time.Hour, b.CacheTTL())
+
+	// WithResponseMediaType
+	a = a.WithResponseMediaType("text/csv")
+	b = a.WithResponseMediaType("Application/XML")
+	assert.Equal(t, "text/csv", a.ResponseMediaType())
+	assert.Equal(t, "application/xml", b.ResponseMediaType())
+
+	// WithService
+	a = a.WithService("foo")
+	b = a.WithService("bar")
+	assert.Equal(t, "foo", a.Service())
+	assert.Equal(t, "bar", b.Service())
+
 	// WithError
 	a = a.WithError(&error1{})
 	b = a.WithError(&error2{})
@@ -8,6 +8,11 @@ type HTTPResponse interface {
 	httpResponseCommon
 	// Result method returns the response mapped as a data type, if any.
 	Result() any
+	// ToCurl method exports the sent request as a curl command, for example to reproduce a failed request, see ToCurl function.
+	// The URL and headers are taken from the RawRequest, so they contain values set by the client, for example the base URL.
+	ToCurl(opts ...RedactOption) (string, error)
+	// ToRawHTTP method exports the sent request as a raw HTTP/1.1 message, see ToCurl method.
+	ToRawHTTP(opts ...RedactOption) (string, error)
 }
 
 type httpResponseCommon interface {
@@ -70,3 +75,19 @@ func (r httpResponse) Result() any {
 func (r httpResponse) Error() error {
 	return r.err
 }
+
+func (r httpResponse) ToCurl(opts ...RedactOption) (string, error) {
+	req, err := newExportedResponseRequest(r)
+	if err != nil {
+		return "", err
+	}
+	return req.curl(NewRedactor(opts...))
+}
+
+func (r httpResponse) ToRawHTTP(opts ...RedactOption) (string, error) {
+	req, err := newExportedResponseRequest(r)
+	if err != nil {
+		return "", err
+	}
+	return req.rawHTTP(NewRedactor(opts...))
+}
@@ -0,0 +1,34 @@
+package request
+
+import (
+	"context"
+	"net/http"
+)
+
+// Middleware wraps the next Sender, it can modify the request, short-circuit the response or veto the request.
+//
+// The middleware must send the request by the next Sender, not by the HTTPRequest.Send method,
+// otherwise the request would be processed by the middleware again.
+type Middleware func(next Sender) Sender
+
+// SenderFunc is an adapter to allow the use of an ordinary function as the Sender.
+type SenderFunc func(ctx context.Context, request HTTPRequest) (rawResponse *http.Response, result any, err error)
+
+// Send calls f(ctx, request).
+func (f SenderFunc) Send(ctx context.Context, request HTTPRequest) (*http.Response, any, error) {
+	return f(ctx, request)
+}
+
+// Chain wraps the sender by the middlewares.
+// The first middleware is the outermost, it processes the request first and the response last.
+func Chain(sender Sender, middlewares ...Middleware) Sender {
+	for i := len(middlewares) - 1; i >= 0; i-- {
+		sender = middlewares[i](sender)
+	}
+	return sender
+}
+
+// Middleware returns the RateLimiter as a Middleware, see RateLimiter.Sender.
+func (l *RateLimiter) Middleware() Middleware {
+	return l.Sender
+}
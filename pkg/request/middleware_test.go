@@ -0,0 +1,74 @@
+package request_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/jarcoal/httpmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/keboola/go-client/pkg/client"
+	"github.com/keboola/go-client/pkg/request"
+)
+
+func TestChain_Order(t *testing.T) {
+	t.Parallel()
+
+	var log []string
+	logMiddleware := func(name string) request.Middleware {
+		return func(next request.Sender) request.Sender {
+			return request.SenderFunc(func(ctx context.Context, req request.HTTPRequest) (*http.Response, any, error) {
+				log = append(log, name+" before")
+				res, result, err := next.Send(ctx, req)
+				log = append(log, name+" after")
+				return res, result, err
+			})
+		}
+	}
+
+	c, transport := client.NewMockedClient()
+	transport.RegisterResponder(http.MethodGet, "https://example.com", httpmock.NewStringResponder(http.StatusOK, "OK"))
+	sender := request.Chain(c, logMiddleware("first"), logMiddleware("second"))
+
+	require.NoError(t, request.NewHTTPRequest(sender).WithGet("https://example.com").SendOrErr(context.Background()))
+	assert.Equal(t, []string{"first before", "second before", "second after", "first after"}, log)
+}
+
+func TestChain_ShortCircuit(t *testing.T) {
+	t.Parallel()
+
+	// Veto all DELETE requests, mock a GET response
+	middleware := func(next request.Sender) request.Sender {
+		return request.SenderFunc(func(ctx context.Context, req request.HTTPRequest) (*http.Response, any, error) {
+			switch req.Method() {
+			case http.MethodDelete:
+				return nil, nil, errors.New("DELETE is not allowed")
+			case http.MethodGet:
+				result := req.ResultDef().(*string)
+				*result = "mocked"
+				return &http.Response{StatusCode: http.StatusOK}, result, nil
+			default:
+				return next.Send(ctx, req)
+			}
+		})
+	}
+
+	c, transport := client.NewMockedClient()
+	transport.RegisterResponder(http.MethodPost, "https://example.com", httpmock.NewStringResponder(http.StatusOK, "OK"))
+	sender := request.Chain(c, middleware)
+	ctx := context.Background()
+
+	err := request.NewHTTPRequest(sender).WithDelete("https://example.com").SendOrErr(ctx)
+	require.Error(t, err)
+	assert.Equal(t, "DELETE is not allowed", err.Error())
+
+	var result string
+	require.NoError(t, request.NewHTTPRequest(sender).WithGet("https://example.com").WithResult(&result).SendOrErr(ctx))
+	assert.Equal(t, "mocked", result)
+
+	require.NoError(t, request.NewHTTPRequest(sender).WithPost("https://example.com").SendOrErr(ctx))
+	assert.Equal(t, map[string]int{"POST https://example.com": 1}, transport.GetCallCountInfo())
+}
@@ -0,0 +1,129 @@
+package request
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net/textproto"
+	"slices"
+	"strings"
+	"sync"
+)
+
+// MultipartFile is a file part of the MultipartBody.
+//
+// The content is streamed from the Reader, it is not buffered in memory.
+// If the Reader implements io.Seeker, it is rewound before each attempt, so the request can be retried.
+type MultipartFile struct {
+	FieldName   string
+	FileName    string
+	ContentType string // default "application/octet-stream"
+	Reader      io.Reader
+}
+
+// MultipartBody is a "multipart/form-data" request body, see HTTPRequest.WithMultipartBody.
+type MultipartBody struct {
+	boundary string
+	fields   map[string]string
+	files    []MultipartFile
+	// lock protects the writer of the last stream, see MultipartBody.Reader
+	lock       sync.Mutex
+	writer     *io.PipeWriter
+	writerDone chan struct{}
+}
+
+func newMultipartBody(fields map[string]string, files []MultipartFile) *MultipartBody {
+	for _, file := range files {
+		if file.FieldName == "" {
+			panic(errors.New("multipart file field name cannot be empty"))
+		}
+		if file.Reader == nil {
+			panic(fmt.Errorf(`multipart file "%s" reader cannot be nil`, file.FieldName))
+		}
+	}
+	return &MultipartBody{boundary: multipart.NewWriter(io.Discard).Boundary(), fields: fields, files: files}
+}
+
+// ContentType returns the Content-Type header value, including the boundary.
+func (b *MultipartBody) ContentType() string {
+	return "multipart/form-data; boundary=" + b.boundary
+}
+
+// Reader returns a new stream of the body, the body is encoded on the fly, as it is read.
+// File readers are rewound, if they implement io.Seeker, otherwise the body can be read only once.
+// The writer of the previous stream is stopped first, so the file readers are never read concurrently.
+func (b *MultipartBody) Reader() (io.ReadCloser, error) {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+
+	// Stop the writer of the previous stream, for example, the previous attempt may not have read the whole body
+	rewind := b.writer != nil
+	if rewind {
+		_ = b.writer.CloseWithError(errors.New("multipart body has been reopened"))
+		<-b.writerDone
+	}
+
+	for _, file := range b.files {
+		if v, ok := file.Reader.(io.Seeker); ok {
+			if _, err := v.Seek(0, io.SeekStart); err != nil {
+				return nil, fmt.Errorf(`cannot rewind multipart file "%s": %w`, file.FieldName, err)
+			}
+		} else if rewind {
+			return nil, fmt.Errorf(`cannot rewind multipart file "%s": the reader is not an io.Seeker`, file.FieldName)
+		}
+	}
+
+	pr, pw := io.Pipe()
+	done := make(chan struct{})
+	b.writer, b.writerDone = pw, done
+	go func() {
+		defer close(done)
+		_ = pw.CloseWithError(b.write(pw))
+	}()
+	return pr, nil
+}
+
+func (b *MultipartBody) write(w io.Writer) error {
+	mw := multipart.NewWriter(w)
+	if err := mw.SetBoundary(b.boundary); err != nil {
+		return err
+	}
+
+	// Fields, sorted, so the body is deterministic
+	keys := make([]string, 0, len(b.fields))
+	for k := range b.fields {
+		keys = append(keys, k)
+	}
+	slices.Sort(keys)
+	for _, k := range keys {
+		if err := mw.WriteField(k, b.fields[k]); err != nil {
+			return fmt.Errorf(`cannot write multipart field "%s": %w`, k, err)
+		}
+	}
+
+	// Files
+	for _, file := range b.files {
+		contentType := file.ContentType
+		if contentType == "" {
+			contentType = "application/octet-stream"
+		}
+		header := make(textproto.MIMEHeader)
+		header.Set("Content-Disposition", fmt.Sprintf(`form-data; name="%s"; filename="%s"`, escapeQuotes(file.FieldName), escapeQuotes(file.FileName)))
+		header.Set("Content-Type", contentType)
+		part, err := mw.CreatePart(header)
+		if err != nil {
+			return fmt.Errorf(`cannot write multipart file "%s": %w`, file.FieldName, err)
+		}
+		if _, err := io.Copy(part, file.Reader); err != nil {
+			return fmt.Errorf(`cannot write multipart file "%s": %w`, file.FieldName, err)
+		}
+	}
+
+	return mw.Close()
+}
+
+// escapeQuotes is the same as in the mime/multipart package.
+func escapeQuotes(s string) string {
+	return strings.NewReplacer("\\", "\\\\", `"`, "\\\"").Replace(s)
+}
@@ -0,0 +1,132 @@
+package request
+
+import (
+	"context"
+	"fmt"
+	"iter"
+)
+
+// DefaultPageSize is the default number of items loaded by one page request of the Paginator.
+const DefaultPageSize = 100
+
+// PageFactory creates an APIRequest to load one page of items defined by the offset and limit.
+type PageFactory[T any] func(offset, limit int) APIRequest[*[]T]
+
+// Paginator lazily loads all items of a list endpoint, page by page, using the offset and limit.
+//
+// Pages are loaded until an empty page is returned, or until the maximum number of items, see WithMaxItems, is reached.
+// A page with fewer items than requested is not the last one, the server may limit the page size.
+type Paginator[T any] struct {
+	factory PageFactory[T]
+	config  paginatorConfig
+}
+
+type paginatorConfig struct {
+	pageSize int
+	maxItems int
+}
+
+type PaginatorOption func(c *paginatorConfig)
+
+// WithPageSize sets number of items loaded by one page request.
+func WithPageSize(v int) PaginatorOption {
+	if v <= 0 {
+		panic(fmt.Errorf("page size must be greater than 0, found %d", v))
+	}
+	return func(c *paginatorConfig) {
+		c.pageSize = v
+	}
+}
+
+// WithMaxItems sets the maximum number of loaded items, 0 means no limit.
+func WithMaxItems(v int) PaginatorOption {
+	if v < 0 {
+		panic(fmt.Errorf("max items cannot be negative, found %d", v))
+	}
+	return func(c *paginatorConfig) {
+		c.maxItems = v
+	}
+}
+
+// NewPaginator creates a Paginator, the factory is called for each page.
+func NewPaginator[T any](factory PageFactory[T], opts ...PaginatorOption) Paginator[T] {
+	cfg := paginatorConfig{pageSize: DefaultPageSize}
+	for _, o := range opts {
+		o(&cfg)
+	}
+	return Paginator[T]{factory: factory, config: cfg}
+}
+
+// All returns an iterator over all items, the pages are loaded lazily, as the iteration progresses.
+// If a page request fails, the error is yielded and the iteration stops.
+func (p Paginator[T]) All(ctx context.Context) iter.Seq2[T, error] {
+	return func(yield func(T, error) bool) {
+		offset := 0
+		for {
+			// Limit the last page, if there is the maximum number of items
+			limit := p.config.pageSize
+			if p.config.maxItems > 0 {
+				limit = min(limit, p.config.maxItems-offset)
+				if limit <= 0 {
+					return
+				}
+			}
+
+			// Load page
+			page, err := p.factory(offset, limit).Send(ctx)
+			if err != nil {
+				var empty T
+				yield(empty, err)
+				return
+			}
+
+			// Yield items
+			var items []T
+			if page != nil {
+				items = *page
+			}
+			for _, item := range items {
+				if !yield(item, nil) {
+					return
+				}
+			}
+
+			// The last page has been loaded
+			if len(items) == 0 {
+				return
+			}
+
+			offset += len(items)
+		}
+	}
+}
+
+// Send loads all pages and returns all items.
+func (p Paginator[T]) Send(ctx context.Context) ([]T, error) {
+	var out []T
+	for item, err := range p.All(ctx) {
+		if err != nil {
+			return out, err
+		}
+		out = append(out, item)
+	}
+	return out, nil
+}
+
+// Into returns a Sendable which loads all pages to the target slice.
+// It can be used to compose the Paginator with other requests, see NewAPIRequest.
+func (p Paginator[T]) Into(target *[]T) Sendable {
+	return paginatorRequest[T]{paginator: p, target: target}
+}
+
+// paginatorRequest implements Sendable interface, see Paginator.Into.
+type paginatorRequest[T any] struct {
+	paginator Paginator[T]
+	target    *[]T
+}
+
+func (r paginatorRequest[T]) SendOrErr(ctx context.Context) error {
+	items, err := r.paginator.Send(ctx)
+	*r.target = items
+	return err
+}
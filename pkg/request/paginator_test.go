@@ -0,0 +1,144 @@
+package request_test
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"testing"
+
+	"github.com/jarcoal/httpmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/keboola/go-client/pkg/client"
+	"github.com/keboola/go-client/pkg/request"
+)
+
+func TestPaginator_Send(t *testing.T) {
+	t.Parallel()
+	c, transport := client.NewMockedClient()
+	c = c.WithBaseURL("https://example.com")
+	registerItemsResponder(transport, 7)
+
+	// Load all items, 4 pages: 3 + 3 + 1 + 0
+	items, err := newItemsPaginator(c, request.WithPageSize(3)).Send(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, []int{0, 1, 2, 3, 4, 5, 6}, items)
+	assert.Equal(t, map[string]int{
+		"GET =~^https://example.com/items":               4,
+		"GET https://example.com/items?limit=3&offset=0": 1,
+		"GET https://example.com/items?limit=3&offset=3": 1,
+		"GET https://example.com/items?limit=3&offset=6": 1,
+		"GET https://example.com/items?limit=3&offset=7": 1,
+	}, transport.GetCallCountInfo())
+}
+
+func TestPaginator_Send_ServerPageSize(t *testing.T) {
+	t.Parallel()
+	c, transport := client.NewMockedClient()
+	c = c.WithBaseURL("https://example.com")
+	registerItemsResponder(transport, 5, 2)
+
+	// The server returns at most 2 items per page, all items are loaded: 2 + 2 + 1 + 0
+	items, err := newItemsPaginator(c, request.WithPageSize(3)).Send(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, []int{0, 1, 2, 3, 4}, items)
+	assert.Equal(t, map[string]int{
+		"GET =~^https://example.com/items":               4,
+		"GET https://example.com/items?limit=3&offset=0": 1,
+		"GET https://example.com/items?limit=3&offset=2": 1,
+		"GET https://example.com/items?limit=3&offset=4": 1,
+		"GET https://example.com/items?limit=3&offset=5": 1,
+	}, transport.GetCallCountInfo())
+}
+
+func TestPaginator_Send_LastPageEmpty(t *testing.T) {
+	t.Parallel()
+	c, transport := client.NewMockedClient()
+	c = c.WithBaseURL("https://example.com")
+	registerItemsResponder(transport, 4)
+
+	// The last page is full, so one more empty page is requested
+	items, err := newItemsPaginator(c, request.WithPageSize(2)).Send(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, []int{0, 1, 2, 3}, items)
+	assert.Equal(t, 3, transport.GetTotalCallCount())
+}
+
+func TestPaginator_Send_MaxItems(t *testing.T) {
+	t.Parallel()
+	c, transport := client.NewMockedClient()
+	c = c.WithBaseURL("https://example.com")
+	registerItemsResponder(transport, 100)
+
+	// The last page is limited by the max items
+	items, err := newItemsPaginator(c, request.WithPageSize(3), request.WithMaxItems(5)).Send(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, []int{0, 1, 2, 3, 4}, items)
+	assert.Equal(t, map[string]int{
+		"GET =~^https://example.com/items":               2,
+		"GET https://example.com/items?limit=3&offset=0": 1,
+		"GET https://example.com/items?limit=2&offset=3": 1,
+	}, transport.GetCallCountInfo())
+}
+
+func TestPaginator_All_Break(t *testing.T) {
+	t.Parallel()
+	c, transport := client.NewMockedClient()
+	c = c.WithBaseURL("https://example.com")
+	registerItemsResponder(transport, 100)
+
+	// Pages are loaded lazily, no other page is requested after the break
+	var items []int
+	for item, err := range newItemsPaginator(c, request.WithPageSize(3)).All(context.Background()) {
+		require.NoError(t, err)
+		items = append(items, item)
+		if item == 4 {
+			break
+		}
+	}
+	assert.Equal(t, []int{0, 1, 2, 3, 4}, items)
+	assert.Equal(t, 2, transport.GetTotalCallCount())
+}
+
+func TestPaginator_Into_Error(t *testing.T) {
+	t.Parallel()
+	c, transport := client.NewMockedClient()
+	c = c.WithBaseURL("https://example.com")
+	transport.RegisterResponder(http.MethodGet, `=~^https://example.com/items`, httpmock.NewStringResponder(http.StatusForbidden, "Forbidden"))
+
+	var items []int
+	err := request.NewAPIRequest(&items, newItemsPaginator(c).Into(&items)).SendOrErr(context.Background())
+	require.Error(t, err)
+	assert.Equal(t, `request GET "https://example.com/items?limit=100&offset=0" failed: 403 Forbidden`, err.Error())
+	assert.Empty(t, items)
+}
+
+func newItemsPaginator(sender request.Sender, opts ...request.PaginatorOption) request.Paginator[int] {
+	return request.NewPaginator(func(offset, limit int) request.APIRequest[*[]int] {
+		var items []int
+		req := request.NewHTTPRequest(sender).
+			WithResult(&items).
+			WithGet("items").
+			AndQueryParam("offset", strconv.Itoa(offset)).
+			AndQueryParam("limit", strconv.Itoa(limit))
+		return request.NewAPIRequest(&items, req)
+	}, opts...)
+}
+
+// registerItemsResponder mocks a list endpoint with the offset and limit support.
+// The optional maxLimit caps the page size, as a server may do.
+func registerItemsResponder(transport *httpmock.MockTransport, count int, maxLimit ...int) {
+	transport.RegisterResponder(http.MethodGet, `=~^https://example.com/items`, func(req *http.Request) (*http.Response, error) {
+		offset, _ := strconv.Atoi(req.URL.Query().Get("offset"))
+		limit, _ := strconv.Atoi(req.URL.Query().Get("limit"))
+		if len(maxLimit) > 0 {
+			limit = min(limit, maxLimit[0])
+		}
+		items := make([]int, 0)
+		for i := offset; i < count && i < offset+limit; i++ {
+			items = append(items, i)
+		}
+		return httpmock.NewJsonResponse(http.StatusOK, items)
+	})
+}
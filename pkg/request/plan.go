@@ -0,0 +1,221 @@
+package request
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"unicode/utf8"
+
+	"go.opentelemetry.io/otel/trace"
+)
+
+// DryRunHeader is set in the synthetic response of an intercepted request, see IsDryRun.
+const DryRunHeader = "X-Dry-Run"
+
+// Plan records mutating requests instead of sending them, see Plan.Sender method.
+//
+// GET, HEAD and OPTIONS requests are sent, so the flows built on listing of resources work as usual.
+// POST, PUT, PATCH and DELETE requests are intercepted, appended to the plan and a synthetic success response is returned.
+// The result definition of an intercepted request is left empty, or filled by the PlaceholderResult interface,
+// callbacks can detect the synthetic response by the IsDryRun function.
+type Plan struct {
+	lock     sync.Mutex
+	requests []PlannedRequest
+}
+
+// PlannedRequest is a mutating request intercepted by the Plan.
+type PlannedRequest struct {
+	Method string `json:"method"`
+	URL    string `json:"url"`
+	// Body is the decoded request body: a JSON value, form values, or a string, if any.
+	Body any `json:"body,omitempty"`
+}
+
+// PlaceholderResult is implemented by result types, which need a placeholder value, if the request is intercepted by the Plan.
+// For example, an asynchronous job can be marked as finished, so nobody waits for it.
+type PlaceholderResult interface {
+	SetPlaceholder()
+}
+
+// Placeholder can be embedded to a result type to mark the placeholder value, see PlaceholderResult.
+// The marker is not encoded, so the placeholder cannot be mistaken for a real value, for example by an ID.
+type Placeholder struct {
+	placeholder bool
+}
+
+// SetPlaceholder implements PlaceholderResult interface.
+func (p *Placeholder) SetPlaceholder() {
+	p.placeholder = true
+}
+
+// IsPlaceholder returns true, if the value is a placeholder of a request intercepted by the Plan.
+func (p Placeholder) IsPlaceholder() bool {
+	return p.placeholder
+}
+
+// withBaseURL is implemented by the client.Client, the Plan uses it to resolve relative URLs.
+type withBaseURL interface {
+	BaseURL() *url.URL
+}
+
+// NewPlan creates an empty Plan.
+func NewPlan() *Plan {
+	return &Plan{}
+}
+
+// IsDryRun returns true, if the response is synthetic, the request has been intercepted by the Plan.
+func IsDryRun(response HTTPResponse) bool {
+	return response != nil && response.RawResponse() != nil && response.ResponseHeader().Get(DryRunHeader) != ""
+}
+
+// Sender wraps the sender, mutating requests sent by the returned Sender are only recorded to the Plan.
+// Relative URLs are resolved, if the sender is the client.Client, so the Plan should wrap the client directly.
+func (p *Plan) Sender(sender Sender) Sender {
+	return planSender{plan: p, sender: sender}
+}
+
+// Middleware returns the Plan as a Middleware, see Plan.Sender.
+// In the middleware of the client.Client, relative URLs are not resolved.
+func (p *Plan) Middleware() Middleware {
+	return p.Sender
+}
+
+// Requests returns all recorded requests, in the order in which they were intercepted.
+func (p *Plan) Requests() []PlannedRequest {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	out := make([]PlannedRequest, len(p.requests))
+	copy(out, p.requests)
+	return out
+}
+
+// String returns the plan as a text, one request per line, followed by the body, if any.
+func (p *Plan) String() string {
+	var b strings.Builder
+	for i, r := range p.Requests() {
+		b.WriteString(fmt.Sprintf("%d. %s %s\n", i+1, r.Method, r.URL))
+		if r.Body != nil {
+			body, err := json.Marshal(r.Body)
+			if err != nil {
+				body = []byte(fmt.Sprintf("%v", r.Body))
+			}
+			b.WriteString("   " + string(body) + "\n")
+		}
+	}
+	return b.String()
+}
+
+// MarshalJSON implements json.Marshaler, the plan is encoded as an array of the PlannedRequest.
+func (p *Plan) MarshalJSON() ([]byte, error) {
+	return json.Marshal(p.Requests())
+}
+
+func (p *Plan) add(request PlannedRequest) {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	p.requests = append(p.requests, request)
+}
+
+// planSender implements Sender interface, see Plan.Sender.
+type planSender struct {
+	plan   *Plan
+	sender Sender
+}
+
+func (s planSender) Send(ctx context.Context, request HTTPRequest) (*http.Response, any, error) {
+	switch request.Method() {
+	case http.MethodGet, http.MethodHead, http.MethodOptions:
+		return s.sender.Send(ctx, request)
+	}
+
+	// Resolve URL, if possible
+	var baseURL string
+	if v, ok := s.sender.(withBaseURL); ok && v.BaseURL() != nil {
+		baseURL = v.BaseURL().String()
+	}
+	exported, err := newExportedRequest(request, baseURL)
+	if err != nil {
+		return nil, nil, fmt.Errorf(`request %s "%s": cannot plan request: %w`, request.Method(), request.URL().String(), err)
+	}
+
+	// Decode body
+	content, err := exported.body()
+	if err != nil {
+		return nil, nil, fmt.Errorf(`request %s "%s": cannot plan request: %w`, request.Method(), request.URL().String(), err)
+	}
+	reqURL := exported.resolvedURL(Redactor{}).String()
+	s.plan.add(PlannedRequest{Method: exported.method, URL: reqURL, Body: decodePlannedBody(request, content)})
+
+	// Synthetic response
+	rawRequest, err := http.NewRequestWithContext(ctx, exported.method, reqURL, nil)
+	if err != nil {
+		return nil, nil, err
+	}
+	rawResponse := &http.Response{
+		Status:     "200 OK",
+		StatusCode: http.StatusOK,
+		Proto:      "HTTP/1.1",
+		ProtoMajor: 1,
+		ProtoMinor: 1,
+		Header:     http.Header{DryRunHeader: []string{"true"}},
+		Body:       http.NoBody,
+		Request:    rawRequest,
+	}
+
+	// Placeholder result
+	result := request.ResultDef()
+	if v, ok := result.(PlaceholderResult); ok {
+		v.SetPlaceholder()
+	}
+	if _, ok := result.(io.Writer); ok {
+		// Nothing has been written
+		result = nil
+	}
+	return rawResponse, result, nil
+}
+
+func (s planSender) Tracer() trace.Tracer {
+	if tp, ok := s.sender.(withTracer); ok {
+		return tp.Tracer()
+	}
+	return nil
+}
+
+func decodePlannedBody(request HTTPRequest, content []byte) any {
+	if len(content) == 0 {
+		return nil
+	}
+
+	mediaType, _, _ := mime.ParseMediaType(request.RequestHeader().Get("Content-Type"))
+	switch {
+	case IsJSONContentType(mediaType):
+		var out any
+		if err := json.Unmarshal(content, &out); err == nil {
+			return out
+		}
+	case mediaType == "application/x-www-form-urlencoded":
+		if values, err := url.ParseQuery(string(content)); err == nil {
+			out := make(map[string]any, len(values))
+			for k, v := range values {
+				if len(v) == 1 {
+					out[k] = v[0]
+				} else {
+					out[k] = v
+				}
+			}
+			return out
+		}
+	}
+
+	if utf8.Valid(content) {
+		return string(content)
+	}
+	return "base64:" + base64.StdEncoding.EncodeToString(content)
+}
@@ -0,0 +1,86 @@
+package request_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/jarcoal/httpmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/keboola/go-client/pkg/client"
+	"github.com/keboola/go-client/pkg/request"
+)
+
+type placeholderResult struct {
+	request.Placeholder
+	Status string `json:"status"`
+}
+
+func (r *placeholderResult) SetPlaceholder() {
+	r.Placeholder.SetPlaceholder()
+	r.Status = "placeholder"
+}
+
+func TestPlan(t *testing.T) {
+	t.Parallel()
+
+	c, transport := client.NewMockedClient()
+	c = c.WithBaseURL("https://example.com/api")
+	transport.RegisterResponder(http.MethodGet, "https://example.com/api/items", httpmock.NewJsonResponderOrPanic(http.StatusOK, []string{"a", "b"}))
+
+	plan := request.NewPlan()
+	sender := plan.Sender(c)
+	ctx := context.Background()
+
+	// GET request is sent
+	var items []string
+	_, _, err := request.NewHTTPRequest(sender).WithGet("items").WithResult(&items).Send(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"a", "b"}, items)
+
+	// Mutating requests are intercepted
+	var isDryRun bool
+	result := &placeholderResult{}
+	_, _, err = request.NewHTTPRequest(sender).
+		WithPost("items/{id}").
+		AndPathParam("id", "c").
+		WithJSONBody(map[string]any{"name": "C"}).
+		WithResult(result).
+		WithOnSuccess(func(_ context.Context, response request.HTTPResponse) error {
+			isDryRun = request.IsDryRun(response)
+			return nil
+		}).
+		Send(ctx)
+	require.NoError(t, err)
+	assert.True(t, isDryRun)
+	assert.Equal(t, "placeholder", result.Status)
+	assert.True(t, result.IsPlaceholder())
+	require.NoError(t, request.NewHTTPRequest(sender).WithPut("items/a").WithFormBody(map[string]string{"name": "A"}).SendOrErr(ctx))
+	require.NoError(t, request.NewHTTPRequest(sender).WithDelete("items/b").AndQueryParam("force", "true").SendOrErr(ctx))
+	assert.Equal(t, map[string]int{"GET https://example.com/api/items": 1}, transport.GetCallCountInfo())
+
+	// Plan
+	assert.Equal(t, []request.PlannedRequest{
+		{Method: http.MethodPost, URL: "https://example.com/api/items/c", Body: map[string]any{"name": "C"}},
+		{Method: http.MethodPut, URL: "https://example.com/api/items/a", Body: map[string]any{"name": "A"}},
+		{Method: http.MethodDelete, URL: "https://example.com/api/items/b?force=true"},
+	}, plan.Requests())
+	assert.Equal(t, ""+
+		"1. POST https://example.com/api/items/c\n"+
+		"   {\"name\":\"C\"}\n"+
+		"2. PUT https://example.com/api/items/a\n"+
+		"   {\"name\":\"A\"}\n"+
+		"3. DELETE https://example.com/api/items/b?force=true\n",
+		plan.String(),
+	)
+	planJSON, err := json.Marshal(plan)
+	require.NoError(t, err)
+	assert.JSONEq(t, `[
+  {"method":"POST","url":"https://example.com/api/items/c","body":{"name":"C"}},
+  {"method":"PUT","url":"https://example.com/api/items/a","body":{"name":"A"}},
+  {"method":"DELETE","url":"https://example.com/api/items/b?force=true"}
+]`, string(planJSON))
+}
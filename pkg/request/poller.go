@@ -0,0 +1,227 @@
+package request
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/cenkalti/backoff/v4"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// Poller waits for an asynchronous operation, for example a job, see NewPoller function.
+//
+// The request loads the current state of the operation, it is sent repeatedly, with a backoff between attempts,
+// until the state is done, failed or the context deadline is exceeded.
+// The Poller is immutable, With* methods return a modified copy.
+type Poller[T any] struct {
+	request     APIRequest[T]
+	isDone      func(result T) bool
+	failure     func(result T) error
+	newBackoff  func() backoff.BackOff
+	description string
+	tracerName  string
+	spanName    string
+	onAttempt   PollAttemptTrace
+	onProgress  func(ctx context.Context, attempt int, result T) error
+}
+
+// PollAttemptTrace is called before each attempt of the Poller, see Poller.WithAttemptTrace.
+// The returned context is used to send the request, the returned function is called with the result of the attempt.
+type PollAttemptTrace func(ctx context.Context, attempt int) (context.Context, func(err error))
+
+// PollFailedError is returned by the Poller, if the operation has failed, see Poller.WithFailure.
+type PollFailedError struct {
+	Attempts int
+	Err      error
+}
+
+func (e PollFailedError) Error() string {
+	return e.Err.Error()
+}
+
+func (e PollFailedError) Unwrap() error {
+	return e.Err
+}
+
+// PollSendError is returned by the Poller, if the request failed, for example because of a network error.
+type PollSendError struct {
+	Attempts int
+	Elapsed  time.Duration
+	Err      error
+}
+
+func (e PollSendError) Error() string {
+	return fmt.Sprintf(`error after %d retries, total time %s: %s`, e.Attempts-1, e.Elapsed, e.Err.Error())
+}
+
+func (e PollSendError) Unwrap() error {
+	return e.Err
+}
+
+// PollTimeoutError is returned by the Poller, if the context is done or the backoff is stopped, before the operation is finished.
+type PollTimeoutError struct {
+	Description string
+	Attempts    int
+	Elapsed     time.Duration
+	Err         error
+}
+
+func (e PollTimeoutError) Error() string {
+	return fmt.Sprintf(`error while waiting for the %s to complete: %s`, e.Description, e.Err.Error())
+}
+
+func (e PollTimeoutError) Unwrap() error {
+	return e.Err
+}
+
+// NewPoller creates a Poller, the request is sent until the isDone function returns true.
+// The request can be sent repeatedly, so it may reuse the same result value.
+func NewPoller[T any](request APIRequest[T], isDone func(result T) bool) Poller[T] {
+	return Poller[T]{
+		request:     request,
+		isDone:      isDone,
+		newBackoff:  DefaultPollBackoff,
+		description: "operation",
+	}
+}
+
+// DefaultPollBackoff creates the default backoff of the Poller, it has no time limit, the Poller runs until the context deadline.
+func DefaultPollBackoff() backoff.BackOff {
+	b := backoff.NewExponentialBackOff()
+	b.RandomizationFactor = 0
+	b.InitialInterval = 100 * time.Millisecond
+	b.Multiplier = 2
+	b.MaxInterval = 5 * time.Second
+	b.MaxElapsedTime = 0 // no limit, run until context timeout
+	b.Reset()
+	return b
+}
+
+// WithFailure sets the function which checks, if the operation has failed.
+// If the function returns an error, the polling stops and the error is returned wrapped in the PollFailedError.
+func (p Poller[T]) WithFailure(fn func(result T) error) Poller[T] {
+	p.failure = fn
+	return p
+}
+
+// WithBackoff sets the factory of the backoff between attempts, a new backoff is created for each Wait call.
+func (p Poller[T]) WithBackoff(fn func() backoff.BackOff) Poller[T] {
+	p.newBackoff = fn
+	return p
+}
+
+// WithDescription sets the description of the operation, it is used in error messages, for example `job "123"`.
+func (p Poller[T]) WithDescription(v string) Poller[T] {
+	p.description = v
+	return p
+}
+
+// WithSpan wraps the whole Wait call to a span, it is created by the TracerProvider of the parent span.
+func (p Poller[T]) WithSpan(tracerName, spanName string) Poller[T] {
+	p.tracerName = tracerName
+	p.spanName = spanName
+	return p
+}
+
+// WithAttemptTrace registers the trace hook, it is called before each attempt.
+func (p Poller[T]) WithAttemptTrace(fn PollAttemptTrace) Poller[T] {
+	p.onAttempt = fn
+	return p
+}
+
+// WithOnProgress registers callback, it is called after each attempt with the current state of the operation.
+// If the callback returns an error, the polling stops and the error is returned.
+func (p Poller[T]) WithOnProgress(fn func(ctx context.Context, attempt int, result T) error) Poller[T] {
+	p.onProgress = fn
+	return p
+}
+
+// Wait sends the request until the operation is finished, the context must have a deadline.
+func (p Poller[T]) Wait(ctx context.Context) (result T, err error) {
+	if _, ok := ctx.Deadline(); !ok {
+		return result, fmt.Errorf("timeout for the %s was not set", p.description)
+	}
+
+	// Telemetry
+	if p.spanName != "" {
+		parentSpan := trace.SpanFromContext(ctx)
+		var span trace.Span
+		ctx, span = parentSpan.TracerProvider().Tracer(p.tracerName).Start(ctx, p.spanName)
+		defer func() {
+			if err != nil {
+				span.RecordError(err)
+				span.SetStatus(codes.Error, err.Error())
+			}
+			span.End()
+		}()
+	}
+
+	startedAt := time.Now()
+	retry := p.newBackoff()
+	for attempt := 1; ; attempt++ {
+		// Get the current state
+		result, err = p.send(ctx, attempt)
+		if err != nil {
+			return result, PollSendError{Attempts: attempt, Elapsed: time.Since(startedAt), Err: err}
+		}
+
+		// Report progress
+		if p.onProgress != nil {
+			if err := p.onProgress(ctx, attempt, result); err != nil {
+				return result, err
+			}
+		}
+
+		// Check state
+		if p.failure != nil {
+			if err := p.failure(result); err != nil {
+				return result, PollFailedError{Attempts: attempt, Err: err}
+			}
+		}
+		if p.isDone(result) {
+			return result, nil
+		}
+
+		// Wait and check again
+		delay := retry.NextBackOff()
+		if delay == backoff.Stop {
+			return result, PollTimeoutError{Description: p.description, Attempts: attempt, Elapsed: time.Since(startedAt), Err: errors.New("backoff limit exceeded")}
+		}
+		select {
+		case <-ctx.Done():
+			return result, PollTimeoutError{Description: p.description, Attempts: attempt, Elapsed: time.Since(startedAt), Err: ctx.Err()}
+		case <-time.After(delay):
+			// try again
+		}
+	}
+}
+
+func (p Poller[T]) send(ctx context.Context, attempt int) (T, error) {
+	if p.onAttempt == nil {
+		return p.request.Send(ctx)
+	}
+	attemptCtx, done := p.onAttempt(ctx, attempt)
+	result, err := p.request.Send(attemptCtx)
+	if done != nil {
+		done(err)
+	}
+	return result, err
+}
+
+// SpanAttemptTrace returns a PollAttemptTrace, which creates a span for each attempt.
+func SpanAttemptTrace(tracerName, spanName string) PollAttemptTrace {
+	return func(ctx context.Context, attempt int) (context.Context, func(err error)) {
+		ctx, span := trace.SpanFromContext(ctx).TracerProvider().Tracer(tracerName).Start(ctx, spanName, trace.WithAttributes(attribute.Int("poll.attempt", attempt)))
+		return ctx, func(err error) {
+			if err != nil {
+				span.RecordError(err)
+				span.SetStatus(codes.Error, err.Error())
+			}
+			span.End()
+		}
+	}
+}
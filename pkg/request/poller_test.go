@@ -0,0 +1,135 @@
+package request_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/cenkalti/backoff/v4"
+	"github.com/jarcoal/httpmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/keboola/go-client/pkg/client"
+	"github.com/keboola/go-client/pkg/request"
+)
+
+type pollJob struct {
+	Status string `json:"status"`
+}
+
+func newPollJobRequest(t *testing.T, statuses ...string) (request.APIRequest[*pollJob], *httpmock.MockTransport) {
+	t.Helper()
+
+	c, transport := client.NewMockedClient()
+	var responders []httpmock.Responder
+	for _, status := range statuses {
+		responders = append(responders, httpmock.NewJsonResponderOrPanic(http.StatusOK, map[string]any{"status": status}))
+	}
+	responder := responders[0]
+	for _, r := range responders[1:] {
+		responder = responder.Then(r)
+	}
+	transport.RegisterResponder(http.MethodGet, "https://example.com/job", responder)
+
+	job := &pollJob{}
+	return request.NewAPIRequest(job, request.NewHTTPRequest(c).WithGet("https://example.com/job").WithResult(job)), transport
+}
+
+func newTestPoller(req request.APIRequest[*pollJob]) request.Poller[*pollJob] {
+	return request.
+		NewPoller(req, func(job *pollJob) bool { return job.Status == "success" }).
+		WithFailure(func(job *pollJob) error {
+			if job.Status == "error" {
+				return errors.New("job failed")
+			}
+			return nil
+		}).
+		WithBackoff(func() backoff.BackOff { return backoff.NewConstantBackOff(time.Millisecond) }).
+		WithDescription(`job "123"`)
+}
+
+func TestPoller_Success(t *testing.T) {
+	t.Parallel()
+
+	req, transport := newPollJobRequest(t, "waiting", "processing", "success")
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+
+	var progress []string
+	var attempts []int
+	job, err := newTestPoller(req).
+		WithAttemptTrace(func(ctx context.Context, attempt int) (context.Context, func(err error)) {
+			attempts = append(attempts, attempt)
+			return ctx, nil
+		}).
+		WithOnProgress(func(_ context.Context, attempt int, job *pollJob) error {
+			progress = append(progress, job.Status)
+			return nil
+		}).
+		Wait(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, "success", job.Status)
+	assert.Equal(t, []int{1, 2, 3}, attempts)
+	assert.Equal(t, []string{"waiting", "processing", "success"}, progress)
+	assert.Equal(t, 3, transport.GetTotalCallCount())
+}
+
+func TestPoller_Failed(t *testing.T) {
+	t.Parallel()
+
+	req, _ := newPollJobRequest(t, "waiting", "error")
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+
+	_, err := newTestPoller(req).Wait(ctx)
+	require.Error(t, err)
+	assert.Equal(t, "job failed", err.Error())
+	var failedErr request.PollFailedError
+	require.ErrorAs(t, err, &failedErr)
+	assert.Equal(t, 2, failedErr.Attempts)
+}
+
+func TestPoller_SendError(t *testing.T) {
+	t.Parallel()
+
+	req, transport := newPollJobRequest(t, "waiting")
+	transport.RegisterResponder(http.MethodGet, "https://example.com/job", httpmock.NewStringResponder(http.StatusNotFound, "not found"))
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+
+	_, err := newTestPoller(req).Wait(ctx)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), `error after 0 retries, total time `)
+	var sendErr request.PollSendError
+	require.ErrorAs(t, err, &sendErr)
+	assert.Equal(t, 1, sendErr.Attempts)
+	assert.Equal(t, `request GET "https://example.com/job" failed: 404 Not Found`, sendErr.Err.Error())
+}
+
+func TestPoller_Timeout(t *testing.T) {
+	t.Parallel()
+
+	req, _ := newPollJobRequest(t, "waiting")
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	_, err := newTestPoller(req).WithBackoff(func() backoff.BackOff { return backoff.NewConstantBackOff(time.Second) }).Wait(ctx)
+	require.Error(t, err)
+	assert.Equal(t, `error while waiting for the job "123" to complete: context deadline exceeded`, err.Error())
+	var timeoutErr request.PollTimeoutError
+	require.ErrorAs(t, err, &timeoutErr)
+	assert.Equal(t, 1, timeoutErr.Attempts)
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+}
+
+func TestPoller_NoDeadline(t *testing.T) {
+	t.Parallel()
+
+	req, _ := newPollJobRequest(t, "waiting")
+	_, err := newTestPoller(req).Wait(context.Background())
+	require.Error(t, err)
+	assert.Equal(t, `timeout for the job "123" was not set`, err.Error())
+}
@@ -0,0 +1,164 @@
+package request
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sync"
+
+	"go.opentelemetry.io/otel/trace"
+	"golang.org/x/sync/semaphore"
+	"golang.org/x/time/rate"
+)
+
+// RateLimit defines a token bucket, the Rate of requests per second and the Burst size.
+// The zero value means no limit.
+type RateLimit struct {
+	Rate  float64
+	Burst int
+}
+
+// RateLimiter throttles requests of one or more Senders, see RateLimiter.Sender method.
+//
+// Each request must obtain a token from the bucket of its host and from the bucket of its service, see HTTPRequest.WithService.
+// Then the number of in-flight requests is limited, globally, on top of the RunGroup and WaitGroup limits.
+//
+// The limits apply to the HTTPRequest as a whole, retries of the request are not throttled again.
+// The RateLimiter can be shared by multiple Senders, to throttle all requests sent from the process.
+type RateLimiter struct {
+	config   rateLimiterConfig
+	inFlight *semaphore.Weighted // nil if there is no limit
+
+	lock     sync.Mutex
+	hosts    map[string]*rate.Limiter
+	services map[string]*rate.Limiter
+}
+
+type rateLimiterConfig struct {
+	maxInFlight int64
+	hostLimit   RateLimit
+	services    map[string]RateLimit
+}
+
+type RateLimiterOption func(c *rateLimiterConfig)
+
+// WithMaxInFlight sets the maximum number of concurrent requests, 0 means no limit.
+func WithMaxInFlight(v int64) RateLimiterOption {
+	if v < 0 {
+		panic(fmt.Errorf("max in-flight requests cannot be negative, found %d", v))
+	}
+	return func(c *rateLimiterConfig) {
+		c.maxInFlight = v
+	}
+}
+
+// WithHostRateLimit sets the token bucket used for each host.
+func WithHostRateLimit(limit RateLimit) RateLimiterOption {
+	limit.validate()
+	return func(c *rateLimiterConfig) {
+		c.hostLimit = limit
+	}
+}
+
+// WithServiceRateLimit sets the token bucket used for the service, see HTTPRequest.WithService.
+func WithServiceRateLimit(service string, limit RateLimit) RateLimiterOption {
+	limit.validate()
+	return func(c *rateLimiterConfig) {
+		c.services[service] = limit
+	}
+}
+
+// NewRateLimiter creates a RateLimiter, without options, requests are not limited.
+func NewRateLimiter(opts ...RateLimiterOption) *RateLimiter {
+	cfg := rateLimiterConfig{services: make(map[string]RateLimit)}
+	for _, o := range opts {
+		o(&cfg)
+	}
+
+	l := &RateLimiter{
+		config:   cfg,
+		hosts:    make(map[string]*rate.Limiter),
+		services: make(map[string]*rate.Limiter),
+	}
+	if cfg.maxInFlight > 0 {
+		l.inFlight = semaphore.NewWeighted(cfg.maxInFlight)
+	}
+	return l
+}
+
+// Sender wraps the sender, all requests sent by the returned Sender are throttled by the RateLimiter.
+func (l *RateLimiter) Sender(sender Sender) Sender {
+	return rateLimitedSender{limiter: l, sender: sender}
+}
+
+// Wait blocks until the request can be sent, the returned function must be called when the request is done.
+func (l *RateLimiter) Wait(ctx context.Context, request HTTPRequest) (done func(), err error) {
+	// Host, relative URLs are resolved by the Sender, so they share one bucket
+	if limiter := l.limiter(l.hosts, request.URL().Host, l.config.hostLimit); limiter != nil {
+		if err := limiter.Wait(ctx); err != nil {
+			return nil, fmt.Errorf(`request %s "%s": rate limit: %w`, request.Method(), request.URL().String(), err)
+		}
+	}
+
+	// Service
+	if service := request.Service(); service != "" {
+		if limiter := l.limiter(l.services, service, l.config.services[service]); limiter != nil {
+			if err := limiter.Wait(ctx); err != nil {
+				return nil, fmt.Errorf(`request %s "%s": rate limit of the service "%s": %w`, request.Method(), request.URL().String(), service, err)
+			}
+		}
+	}
+
+	// In-flight requests
+	if l.inFlight == nil {
+		return func() {}, nil
+	}
+	if err := l.inFlight.Acquire(ctx, 1); err != nil {
+		return nil, fmt.Errorf(`request %s "%s": max in-flight requests: %w`, request.Method(), request.URL().String(), err)
+	}
+	return func() { l.inFlight.Release(1) }, nil
+}
+
+// limiter returns the token bucket for the key, it is created on the first use.
+func (l *RateLimiter) limiter(limiters map[string]*rate.Limiter, key string, limit RateLimit) *rate.Limiter {
+	if limit.Rate == 0 {
+		return nil
+	}
+
+	l.lock.Lock()
+	defer l.lock.Unlock()
+	limiter, found := limiters[key]
+	if !found {
+		limiter = rate.NewLimiter(rate.Limit(limit.Rate), max(limit.Burst, 1))
+		limiters[key] = limiter
+	}
+	return limiter
+}
+
+func (v RateLimit) validate() {
+	if v.Rate < 0 || v.Burst < 0 {
+		panic(fmt.Errorf("rate limit cannot be negative, found rate=%v burst=%d", v.Rate, v.Burst))
+	}
+}
+
+// rateLimitedSender implements Sender interface, see RateLimiter.Sender.
+type rateLimitedSender struct {
+	limiter *RateLimiter
+	sender  Sender
+}
+
+func (s rateLimitedSender) Send(ctx context.Context, request HTTPRequest) (*http.Response, any, error) {
+	done, err := s.limiter.Wait(ctx, request)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer done()
+	return s.sender.Send(ctx, request)
+}
+
+func (s rateLimitedSender) Tracer() trace.Tracer {
+	if tp, ok := s.sender.(withTracer); ok {
+		return tp.Tracer()
+	}
+	return nil
+}
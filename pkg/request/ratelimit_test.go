@@ -0,0 +1,107 @@
+package request_test
+
+import (
+	"context"
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/jarcoal/httpmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/keboola/go-client/pkg/client"
+	"github.com/keboola/go-client/pkg/request"
+)
+
+func TestRateLimiter_MaxInFlight(t *testing.T) {
+	t.Parallel()
+
+	// Mocked response, count concurrent requests
+	var inFlight, maxInFlight atomic.Int64
+	c, transport := client.NewMockedClient()
+	transport.RegisterResponder(http.MethodGet, "https://example.com", func(req *http.Request) (*http.Response, error) {
+		current := inFlight.Add(1)
+		defer inFlight.Add(-1)
+		for {
+			v := maxInFlight.Load()
+			if current <= v || maxInFlight.CompareAndSwap(v, current) {
+				break
+			}
+		}
+		time.Sleep(10 * time.Millisecond)
+		return httpmock.NewStringResponse(http.StatusOK, "OK"), nil
+	})
+
+	// Send requests concurrently, by two groups sharing one limiter
+	ctx := context.Background()
+	limiter := request.NewRateLimiter(request.WithMaxInFlight(2))
+	wg := &sync.WaitGroup{}
+	for range 2 {
+		group := request.NewWaitGroup(ctx)
+		sender := limiter.Sender(c)
+		for range 5 {
+			group.Send(request.NewHTTPRequest(sender).WithGet("https://example.com"))
+		}
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			assert.NoError(t, group.Wait())
+		}()
+	}
+	wg.Wait()
+
+	assert.Equal(t, 10, transport.GetTotalCallCount())
+	assert.Equal(t, int64(2), maxInFlight.Load())
+}
+
+func TestRateLimiter_Host(t *testing.T) {
+	t.Parallel()
+
+	c, transport := client.NewMockedClient()
+	transport.RegisterResponder(http.MethodGet, `=~^https://`, httpmock.NewStringResponder(http.StatusOK, "OK"))
+	sender := request.NewRateLimiter(request.WithHostRateLimit(request.RateLimit{Rate: 0.1, Burst: 1})).Sender(c)
+
+	// The first request consumes the burst
+	ctx := context.Background()
+	require.NoError(t, request.NewHTTPRequest(sender).WithGet("https://foo.example.com").SendOrErr(ctx))
+
+	// The next request to the same host must wait, longer than the context deadline
+	ctxTimeout, cancel := context.WithTimeout(ctx, 50*time.Millisecond)
+	defer cancel()
+	err := request.NewHTTPRequest(sender).WithGet("https://foo.example.com/bar").SendOrErr(ctxTimeout)
+	if assert.Error(t, err) {
+		assert.Contains(t, err.Error(), `request GET "https://foo.example.com/bar": rate limit: `)
+	}
+
+	// Other host has own bucket
+	require.NoError(t, request.NewHTTPRequest(sender).WithGet("https://bar.example.com").SendOrErr(ctxTimeout))
+	assert.Equal(t, 2, transport.GetTotalCallCount())
+}
+
+func TestRateLimiter_Service(t *testing.T) {
+	t.Parallel()
+
+	c, transport := client.NewMockedClient()
+	transport.RegisterResponder(http.MethodGet, `=~^https://`, httpmock.NewStringResponder(http.StatusOK, "OK"))
+	sender := request.NewRateLimiter(request.WithServiceRateLimit("queue", request.RateLimit{Rate: 0.1, Burst: 1})).Sender(c)
+
+	// The first request consumes the burst
+	ctx := context.Background()
+	require.NoError(t, request.NewHTTPRequest(sender).WithGet("https://example.com/jobs").WithService("queue").SendOrErr(ctx))
+
+	// The next request to the same service must wait, longer than the context deadline
+	ctxTimeout, cancel := context.WithTimeout(ctx, 50*time.Millisecond)
+	defer cancel()
+	err := request.NewHTTPRequest(sender).WithGet("https://example.com/jobs").WithService("queue").SendOrErr(ctxTimeout)
+	if assert.Error(t, err) {
+		assert.Contains(t, err.Error(), `request GET "https://example.com/jobs": rate limit of the service "queue": `)
+	}
+
+	// Other service and requests without service are not limited
+	require.NoError(t, request.NewHTTPRequest(sender).WithGet("https://example.com/storage").WithService("storage").SendOrErr(ctxTimeout))
+	require.NoError(t, request.NewHTTPRequest(sender).WithGet("https://example.com/other").SendOrErr(ctxTimeout))
+	assert.Equal(t, 3, transport.GetTotalCallCount())
+}
@@ -0,0 +1,151 @@
+package request
+
+import (
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// RedactedValue replaces sensitive values, see Redactor.
+const RedactedValue = "****"
+
+// Redactor masks sensitive header, query and path parameter values, see NewRedactor function.
+// The zero value masks nothing.
+//
+// The same rules are used by the ToCurl and ToRawHTTP functions, the otel package and the trace.SlogTracer.
+type Redactor struct {
+	headers     map[string]struct{}
+	queryParams map[string]struct{}
+	pathParams  map[string]struct{}
+}
+
+// RedactOption configures the Redactor.
+type RedactOption func(r *Redactor)
+
+// DefaultRedactedHeaders returns headers, which values are always masked by the Redactor.
+// The list is the same as in the otelhttptrace package, plus the Keboola Storage API token.
+func DefaultRedactedHeaders() []string {
+	return []string{
+		"authorization",
+		"www-authenticate",
+		"proxy-authenticate",
+		"proxy-authorization",
+		"cookie",
+		"set-cookie",
+		"x-storageapi-token",
+	}
+}
+
+// WithRedactedHeaders masks values of the headers, in addition to the DefaultRedactedHeaders.
+func WithRedactedHeaders(headers ...string) RedactOption {
+	return func(r *Redactor) {
+		for _, h := range headers {
+			r.headers[strings.ToLower(h)] = struct{}{}
+		}
+	}
+}
+
+// WithRedactedQueryParams masks values of the query parameters.
+func WithRedactedQueryParams(params ...string) RedactOption {
+	return func(r *Redactor) {
+		for _, p := range params {
+			r.queryParams[strings.ToLower(p)] = struct{}{}
+		}
+	}
+}
+
+// WithRedactedPathParams masks values of the path parameters, see HTTPRequest.AndPathParam.
+func WithRedactedPathParams(params ...string) RedactOption {
+	return func(r *Redactor) {
+		for _, p := range params {
+			r.pathParams[strings.ToLower(p)] = struct{}{}
+		}
+	}
+}
+
+// NewRedactor creates a Redactor, the DefaultRedactedHeaders are always masked.
+func NewRedactor(opts ...RedactOption) Redactor {
+	r := Redactor{
+		headers:     make(map[string]struct{}),
+		queryParams: make(map[string]struct{}),
+		pathParams:  make(map[string]struct{}),
+	}
+	WithRedactedHeaders(DefaultRedactedHeaders()...)(&r)
+	for _, o := range opts {
+		o(&r)
+	}
+	return r
+}
+
+// IsRedactedHeader returns true, if the header value should be masked.
+func (r Redactor) IsRedactedHeader(name string) bool {
+	_, found := r.headers[strings.ToLower(name)]
+	return found
+}
+
+// IsRedactedQueryParam returns true, if the query parameter value should be masked.
+func (r Redactor) IsRedactedQueryParam(name string) bool {
+	_, found := r.queryParams[strings.ToLower(name)]
+	return found
+}
+
+// IsRedactedPathParam returns true, if the path parameter value should be masked.
+func (r Redactor) IsRedactedPathParam(name string) bool {
+	_, found := r.pathParams[strings.ToLower(name)]
+	return found
+}
+
+// HeaderValue returns the value of the header, or the RedactedValue, if the header is sensitive.
+func (r Redactor) HeaderValue(name, value string) string {
+	if r.IsRedactedHeader(name) {
+		return RedactedValue
+	}
+	return value
+}
+
+// Header returns a clone of the header with sensitive values masked.
+// The Referer header contains the URL of the previous request, for example before a redirect, so its query parameters are masked too.
+func (r Redactor) Header(header http.Header) http.Header {
+	out := header.Clone()
+	for name, values := range out {
+		for i, value := range values {
+			if r.IsRedactedHeader(name) {
+				values[i] = RedactedValue
+			} else if strings.EqualFold(name, "Referer") {
+				if u, err := url.Parse(value); err == nil {
+					values[i] = r.URL(u).String()
+				}
+			}
+		}
+	}
+	return out
+}
+
+// URL returns a clone of the URL with sensitive query parameter values masked.
+func (r Redactor) URL(u *url.URL) *url.URL {
+	out := *u
+	if out.RawQuery != "" {
+		query := out.Query()
+		for key, values := range query {
+			if r.IsRedactedQueryParam(key) {
+				for i := range values {
+					values[i] = RedactedValue
+				}
+			}
+		}
+		out.RawQuery = query.Encode()
+	}
+	return &out
+}
+
+// PathParams returns a clone of the path parameters with sensitive values masked.
+func (r Redactor) PathParams(params map[string]string) map[string]string {
+	out := make(map[string]string, len(params))
+	for k, v := range params {
+		if r.IsRedactedPathParam(k) {
+			v = RedactedValue
+		}
+		out[k] = v
+	}
+	return out
+}
@@ -0,0 +1,52 @@
+package request_test
+
+import (
+	"net/http"
+	"net/url"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/keboola/go-client/pkg/request"
+)
+
+func TestRedactor(t *testing.T) {
+	t.Parallel()
+
+	r := request.NewRedactor(
+		request.WithRedactedHeaders("X-Custom-Secret"),
+		request.WithRedactedQueryParams("Signature"),
+		request.WithRedactedPathParams("fileId"),
+	)
+
+	// Default headers are always redacted
+	assert.True(t, r.IsRedactedHeader("Authorization"))
+	assert.True(t, r.IsRedactedHeader("x-storageapi-token"))
+	assert.True(t, r.IsRedactedHeader("X-Custom-Secret"))
+	assert.False(t, r.IsRedactedHeader("Content-Type"))
+	assert.Equal(t, http.Header{
+		"Authorization":      {"****"},
+		"X-Storageapi-Token": {"****"},
+		"Content-Type":       {"application/json"},
+		"Referer":            {"https://example.com/files?signature=%2A%2A%2A%2A"},
+	}, r.Header(http.Header{
+		"Authorization":      {"Bearer abc"},
+		"X-Storageapi-Token": {"abc"},
+		"Content-Type":       {"application/json"},
+		"Referer":            {"https://example.com/files?signature=abc"},
+	}))
+
+	// Query params
+	u, _ := url.Parse("https://example.com/files?signature=abc&name=foo")
+	assert.Equal(t, "https://example.com/files?name=foo&signature=%2A%2A%2A%2A", r.URL(u).String())
+	assert.Equal(t, "https://example.com/files?signature=abc&name=foo", u.String())
+
+	// Path params
+	assert.Equal(t, map[string]string{"fileId": "****", "branchId": "123"}, r.PathParams(map[string]string{"fileId": "abc", "branchId": "123"}))
+
+	// The Storage API token is masked without options
+	assert.True(t, request.NewRedactor().IsRedactedHeader("X-StorageApi-Token"))
+
+	// The zero value masks nothing
+	assert.False(t, request.Redactor{}.IsRedactedHeader("Authorization"))
+}
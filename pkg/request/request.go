@@ -8,5 +8,25 @@
 // It contains target data type to which the API response will be mapped.
 // Use NewAPIRequest function to create a APIRequest from a HTTPRequest.
 //
+// TypedHTTPRequest[R Result] wraps a HTTPRequest, the result type is checked at compile time, see NewTypedHTTPRequest function.
+//
 // RunGroup, WaitGroup, ParallelAPIRequests are helpers for concurrent requests.
+//
+// Map and Then compose APIRequests, DAG runs requests with dependencies, see NewDAG function.
+//
+// Paginator[T] loads all items of a list endpoint, page by page, see NewPaginator function.
+//
+// Poller[T] waits for an asynchronous operation, for example a job, see NewPoller function.
+//
+// StreamResult[T] decodes a large JSON array element by element, see NewStreamResult function.
+//
+// RateLimiter throttles requests sent by one or more Senders, see NewRateLimiter function.
+//
+// Deduplicator collapses concurrent identical GET requests into one, see NewDeduplicator function.
+//
+// Plan records mutating requests without sending them, for a dry run, see NewPlan function.
+//
+// ToCurl and ToRawHTTP export a request, for example to reproduce a failed request.
+//
+// Middleware wraps a Sender, middlewares are composed by the Chain function.
 package request
@@ -0,0 +1,89 @@
+package request
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/cenkalti/backoff/v4"
+)
+
+// IdempotencyKeyHeader marks a non-idempotent request, which can be safely retried, see IsIdempotent.
+const IdempotencyKeyHeader = "Idempotency-Key"
+
+// RetryConfig configures retries of a HTTPRequest.
+// The default configuration is set in the Sender, for example, see client.Client.WithRetry method.
+// It can be overridden for a single request, see HTTPRequest.WithRetry method.
+type RetryConfig struct {
+	// Condition defines which responses of an idempotent request should retry, see IsIdempotent.
+	Condition RetryCondition
+	// NonIdempotentCondition defines which responses of a non-idempotent request should retry, see IsIdempotent.
+	// The previous attempt may have been processed by the server, so it should match only failures,
+	// when the request has not been sent at all, see client.ConnectionRetryCondition.
+	// If it is nil, the client.ConnectionRetryCondition is used,
+	// set it to the client.NeverRetryCondition to disable retries of non-idempotent requests.
+	NonIdempotentCondition RetryCondition
+	Count                  int
+	TotalRequestTimeout    time.Duration
+	WaitTimeStart          time.Duration
+	WaitTimeMax            time.Duration
+}
+
+// RetryModifier adjusts the effective retry configuration of the Sender for a request, see HTTPRequest.WithRetryModifier.
+type RetryModifier func(RetryConfig) RetryConfig
+
+// RetryCondition defines which responses should retry.
+type RetryCondition func(*http.Response, error) bool
+
+// ConditionFor returns the retry condition according to the idempotency of the request.
+func (c RetryConfig) ConditionFor(idempotent bool) RetryCondition {
+	if idempotent {
+		return c.Condition
+	}
+	return c.NonIdempotentCondition
+}
+
+// IsIdempotent returns true if the HTTP request can be safely repeated.
+// The explicit flag, if it is not nil, has priority, see HTTPRequest.WithIdempotent.
+// Otherwise, the request is idempotent if it has the IdempotencyKeyHeader or an idempotent method, see IsIdempotentMethod.
+func IsIdempotent(req *http.Request, explicit *bool) bool {
+	if explicit != nil {
+		return *explicit
+	}
+	if req.Header.Get(IdempotencyKeyHeader) != "" {
+		return true
+	}
+	return IsIdempotentMethod(req.Method)
+}
+
+// IsIdempotentMethod returns true if the HTTP method is idempotent according to the RFC 9110.
+func IsIdempotentMethod(method string) bool {
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions, http.MethodTrace, http.MethodPut, http.MethodDelete:
+		return true
+	default:
+		return false
+	}
+}
+
+// IsSafeMethod returns true if the HTTP method is read-only according to the RFC 9110.
+// Requests with other methods are mutations.
+func IsSafeMethod(method string) bool {
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions, http.MethodTrace:
+		return true
+	default:
+		return false
+	}
+}
+
+// NewBackoff returns an exponential backoff for HTTP retries.
+func (c RetryConfig) NewBackoff() backoff.BackOff {
+	b := backoff.NewExponentialBackOff()
+	b.InitialInterval = c.WaitTimeStart
+	b.MaxInterval = c.WaitTimeMax
+	b.MaxElapsedTime = c.TotalRequestTimeout
+	b.Multiplier = 2
+	b.RandomizationFactor = 0
+	b.Reset()
+	return b
+}
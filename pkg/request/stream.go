@@ -0,0 +1,60 @@
+package request
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+)
+
+// StreamDecoder is a result definition which decodes the JSON response body by itself, as a stream, see StreamResult.
+type StreamDecoder interface {
+	DecodeStream(body io.Reader) error
+}
+
+// StreamResult decodes a top-level JSON array of the response element by element, see NewStreamResult function.
+// Each element is passed to the callback as soon as it is decoded, so the whole array is never loaded into memory.
+type StreamResult[T any] struct {
+	fn    func(item T) error
+	count int
+}
+
+// NewStreamResult creates a StreamResult, use it as the HTTPRequest result definition, see HTTPRequest.WithResult.
+// If the callback returns an error, the decoding stops and the error is returned by the Sender.
+func NewStreamResult[T any](fn func(item T) error) *StreamResult[T] {
+	return &StreamResult[T]{fn: fn}
+}
+
+// Count returns the number of processed elements.
+func (r *StreamResult[T]) Count() int {
+	return r.count
+}
+
+// DecodeStream implements the StreamDecoder interface.
+func (r *StreamResult[T]) DecodeStream(body io.Reader) error {
+	decoder := json.NewDecoder(body)
+
+	// Array start
+	if token, err := decoder.Token(); err != nil {
+		return fmt.Errorf(`cannot decode JSON stream: %w`, err)
+	} else if delim, ok := token.(json.Delim); !ok || delim != '[' {
+		return fmt.Errorf(`cannot decode JSON stream: expected an array, found "%v"`, token)
+	}
+
+	// Elements
+	for decoder.More() {
+		var item T
+		if err := decoder.Decode(&item); err != nil {
+			return fmt.Errorf(`cannot decode JSON stream element %d: %w`, r.count, err)
+		}
+		r.count++
+		if err := r.fn(item); err != nil {
+			return err
+		}
+	}
+
+	// Array end
+	if _, err := decoder.Token(); err != nil {
+		return fmt.Errorf(`cannot decode JSON stream: %w`, err)
+	}
+	return nil
+}
@@ -0,0 +1,62 @@
+package request_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/jarcoal/httpmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/keboola/go-client/pkg/client"
+	"github.com/keboola/go-client/pkg/request"
+)
+
+type streamItem struct {
+	ID int `json:"id"`
+}
+
+func TestStreamResult(t *testing.T) {
+	t.Parallel()
+
+	// Mocked response
+	c, transport := client.NewMockedClient()
+	transport.RegisterResponder(http.MethodGet, "https://example.com", httpmock.NewJsonResponderOrPanic(http.StatusOK, []streamItem{{ID: 1}, {ID: 2}, {ID: 3}}))
+	ctx := context.Background()
+
+	// Each element is passed to the callback
+	var ids []int
+	result := request.NewStreamResult(func(item streamItem) error {
+		ids = append(ids, item.ID)
+		return nil
+	})
+	_, _, err := request.NewHTTPRequest(c).WithGet("https://example.com").WithResult(result).Send(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, []int{1, 2, 3}, ids)
+	assert.Equal(t, 3, result.Count())
+
+	// Error from the callback stops the decoding
+	result = request.NewStreamResult(func(item streamItem) error {
+		if item.ID == 2 {
+			return errors.New("some error")
+		}
+		return nil
+	})
+	_, _, err = request.NewHTTPRequest(c).WithGet("https://example.com").WithResult(result).Send(ctx)
+	assert.EqualError(t, err, `cannot process response body GET "https://example.com": some error`)
+	assert.Equal(t, 2, result.Count())
+}
+
+func TestStreamResult_NotArray(t *testing.T) {
+	t.Parallel()
+
+	// Mocked response
+	c, transport := client.NewMockedClient()
+	transport.RegisterResponder(http.MethodGet, "https://example.com", httpmock.NewJsonResponderOrPanic(http.StatusOK, map[string]any{"id": 1}))
+
+	result := request.NewStreamResult(func(item streamItem) error { return nil })
+	_, _, err := request.NewHTTPRequest(c).WithGet("https://example.com").WithResult(result).Send(context.Background())
+	assert.EqualError(t, err, `cannot process response body GET "https://example.com": cannot decode JSON stream: expected an array, found "{"`)
+}
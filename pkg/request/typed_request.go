@@ -0,0 +1,104 @@
+package request
+
+import (
+	"context"
+)
+
+// TypedHTTPRequest is an immutable HTTP request with the result of the type R, see NewTypedHTTPRequest function.
+//
+// Unlike the HTTPRequest, the result definition is checked at compile time and the Send method returns R.
+// The Request method is an adapter to the HTTPRequest interface, for the existing code.
+type TypedHTTPRequest[R Result] interface {
+	// Request method returns the underlying HTTPRequest, with the result definition set.
+	Request() HTTPRequest
+	// Result method returns the result definition.
+	Result() R
+	// WithRequest method modifies the underlying HTTPRequest, the result definition is kept.
+	WithRequest(fn func(r HTTPRequest) HTTPRequest) TypedHTTPRequest[R]
+	// WithResult method sets the result definition.
+	WithResult(result R) TypedHTTPRequest[R]
+	// WithOnComplete method registers callback to be executed when the request is completed.
+	WithOnComplete(func(ctx context.Context, response HTTPResponse, result R, err error) error) TypedHTTPRequest[R]
+	// WithOnSuccess method registers callback to be executed when the request is completed and `code >= 200 and <= 299`.
+	WithOnSuccess(func(ctx context.Context, response HTTPResponse, result R) error) TypedHTTPRequest[R]
+	// WithOnError method registers callback to be executed when the request is completed and `code >= 400`.
+	WithOnError(func(ctx context.Context, response HTTPResponse, err error) error) TypedHTTPRequest[R]
+	// APIRequest method converts the request to the APIRequest with the same result.
+	APIRequest() APIRequest[R]
+	// Send method sends the request by the sender and returns the result decoded by the sender.
+	// If the sender returns no result of the type R, for example on an error, the result definition is returned.
+	Send(ctx context.Context) (result R, err error)
+	SendOrErr(ctx context.Context) error
+}
+
+// typedHTTPRequest implements generic TypedHTTPRequest interface.
+type typedHTTPRequest[R Result] struct {
+	request HTTPRequest
+	result  R
+}
+
+// NewTypedHTTPRequest creates immutable HTTP request with the result of the type *T.
+// The result is the target of the response mapping, see HTTPRequest.WithResult, so it must be a pointer, it is checked at compile time.
+func NewTypedHTTPRequest[T any](request HTTPRequest, result *T) TypedHTTPRequest[*T] {
+	return typedHTTPRequest[*T]{request: request.WithResult(result), result: result}
+}
+
+func (r typedHTTPRequest[R]) Request() HTTPRequest {
+	return r.request
+}
+
+func (r typedHTTPRequest[R]) Result() R {
+	return r.result
+}
+
+func (r typedHTTPRequest[R]) WithRequest(fn func(r HTTPRequest) HTTPRequest) TypedHTTPRequest[R] {
+	r.request = fn(r.request).WithResult(r.result)
+	return r
+}
+
+func (r typedHTTPRequest[R]) WithResult(result R) TypedHTTPRequest[R] {
+	r.request = r.request.WithResult(result)
+	r.result = result
+	return r
+}
+
+func (r typedHTTPRequest[R]) WithOnComplete(fn func(ctx context.Context, response HTTPResponse, result R, err error) error) TypedHTTPRequest[R] {
+	r.request = r.request.WithOnComplete(func(ctx context.Context, response HTTPResponse, err error) error {
+		return fn(ctx, response, resultOf[R](response), err)
+	})
+	return r
+}
+
+func (r typedHTTPRequest[R]) WithOnSuccess(fn func(ctx context.Context, response HTTPResponse, result R) error) TypedHTTPRequest[R] {
+	r.request = r.request.WithOnSuccess(func(ctx context.Context, response HTTPResponse) error {
+		return fn(ctx, response, resultOf[R](response))
+	})
+	return r
+}
+
+func (r typedHTTPRequest[R]) WithOnError(fn func(ctx context.Context, response HTTPResponse, err error) error) TypedHTTPRequest[R] {
+	r.request = r.request.WithOnError(fn)
+	return r
+}
+
+func (r typedHTTPRequest[R]) APIRequest() APIRequest[R] {
+	return newAPIRequest(r.result, callerName(2), r.request)
+}
+
+func (r typedHTTPRequest[R]) Send(ctx context.Context) (R, error) {
+	_, result, err := r.request.Send(ctx)
+	if v, ok := result.(R); ok {
+		return v, err
+	}
+	return r.result, err
+}
+
+func (r typedHTTPRequest[R]) SendOrErr(ctx context.Context) error {
+	return r.request.SendOrErr(ctx)
+}
+
+// resultOf returns the result definition of the sent request, it is always of the type R, see typedHTTPRequest.WithResult.
+func resultOf[R Result](response HTTPResponse) R {
+	result, _ := response.ResultDef().(R)
+	return result
+}
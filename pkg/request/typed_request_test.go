@@ -0,0 +1,70 @@
+package request_test
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/jarcoal/httpmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/keboola/go-client/pkg/client"
+	"github.com/keboola/go-client/pkg/request"
+)
+
+type typedResult struct {
+	Foo string `json:"foo"`
+}
+
+func TestTypedHTTPRequest(t *testing.T) {
+	t.Parallel()
+
+	// Mocked response
+	c, transport := client.NewMockedClient()
+	transport.RegisterResponder(http.MethodGet, "https://example.com/foo", httpmock.NewJsonResponderOrPanic(http.StatusOK, map[string]any{"foo": "bar"}))
+	ctx := context.Background()
+
+	// The result definition is set to the underlying request
+	var onSuccess *typedResult
+	req := request.
+		NewTypedHTTPRequest(request.NewHTTPRequest(c).WithGet("https://example.com"), &typedResult{}).
+		WithRequest(func(r request.HTTPRequest) request.HTTPRequest {
+			return r.WithGet("https://example.com/foo")
+		}).
+		WithOnSuccess(func(_ context.Context, response request.HTTPResponse, result *typedResult) error {
+			assert.Equal(t, http.StatusOK, response.StatusCode())
+			onSuccess = result
+			return nil
+		})
+	assert.Same(t, req.Result(), req.Request().ResultDef())
+
+	// Send returns the typed result
+	result, err := req.Send(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, &typedResult{Foo: "bar"}, result)
+	assert.Same(t, result, onSuccess)
+
+	// APIRequest, callbacks receive the actual result definition
+	apiResult, err := req.WithResult(&typedResult{}).APIRequest().Send(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, &typedResult{Foo: "bar"}, apiResult)
+	assert.Same(t, apiResult, onSuccess)
+}
+
+func TestTypedHTTPRequest_SenderResult(t *testing.T) {
+	t.Parallel()
+
+	// The sender returns another value of the result type, for example a placeholder, see Plan
+	placeholder := &typedResult{Foo: "placeholder"}
+	sender := request.SenderFunc(func(_ context.Context, _ request.HTTPRequest) (*http.Response, any, error) {
+		return nil, placeholder, nil
+	})
+
+	// Send returns the result of the sender, not the result definition
+	req := request.NewTypedHTTPRequest(request.NewHTTPRequest(sender).WithGet("https://example.com/foo"), &typedResult{})
+	result, err := req.Send(context.Background())
+	require.NoError(t, err)
+	assert.Same(t, placeholder, result)
+	assert.Equal(t, &typedResult{}, req.Result())
+}